@@ -2,6 +2,7 @@ package config
 
 import (
 	"generate-service/internal/service/register"
+	"shared/signedlink"
 	"time"
 )
 
@@ -73,9 +74,17 @@ type KafkaConfig struct {
 	Version  string   `mapstructure:"version"`
 	ClientID string   `mapstructure:"client_id"`
 	Producer Producer `mapstructure:"producer"`
+	Consumer Consumer `mapstructure:"consumer"`
 	Net      Net      `mapstructure:"net"`
 }
 
+// Consumer 消费 redirect-service 发出的链接事件
+type Consumer struct {
+	GroupID        string        `mapstructure:"group_id"`
+	Topics         []string      `mapstructure:"topics"`
+	SessionTimeout time.Duration `mapstructure:"session_timeout"`
+}
+
 type Producer struct {
 	RequiredAcks int `mapstructure:"required_acks"` // 对应 sarama.WaitForAll（-1）
 	Compression  int `mapstructure:"compression"`   // 对应 sarama.CompressionSnappy（2）
@@ -100,6 +109,39 @@ type RateLimitConfig struct {
 	RequestPerMinute int `mapstructure:"requests_per_minute"`
 }
 
+// AuthConfig 认证配置
+type AuthConfig struct {
+	APIKeyPrefix string    `mapstructure:"api_key_prefix"` // API密钥前缀，用于区分API密钥与JWT
+	DefaultRole  string    `mapstructure:"default_role"`   // JWT 未携带 role 声明时使用的角色
+	JWT          JWTConfig `mapstructure:"jwt"`
+}
+
+// JWTConfig JWT 校验配置，HS256 与 RS256 可同时启用
+type JWTConfig struct {
+	HS256Secret        string `mapstructure:"hs256_secret"`
+	RS256PublicKeyFile string `mapstructure:"rs256_public_key_file"`
+	Issuer             string `mapstructure:"issuer"`
+	Audience           string `mapstructure:"audience"`
+}
+
+// LinkConfig 短链创建配置
+type LinkConfig struct {
+	ReuseExisting bool `mapstructure:"reuse_existing"` // 请求未指定 reuse_existing 时的默认值
+}
+
+// SigningConfig 签名短链的密钥环，redirect-service 需配置相同的密钥
+type SigningConfig struct {
+	ActiveKey string           `mapstructure:"active_key"` // 签名使用的密钥ID，为空时不能生成签名地址
+	Keys      []signedlink.Key `mapstructure:"keys"`
+	MaxTTL    int              `mapstructure:"max_ttl"` // 签名地址的最长有效期（秒）
+}
+
+// StatsConfig 统计查询转发到 statistics-service，令牌需与其 auth.internal_token 一致
+type StatsConfig struct {
+	BaseURL       string `mapstructure:"base_url"`
+	InternalToken string `mapstructure:"internal_token"`
+}
+
 type Config struct {
 	Server      ServerConfig        `mapstructure:"server"`
 	Database    DatabaseConfig      `mapstructure:"database"`
@@ -110,4 +152,8 @@ type Config struct {
 	Kafka       KafkaConfig         `mapstructure:"kafka"`
 	Etcd        register.EtcdConfig `mapstructure:"etcd"`
 	RateLimit   RateLimitConfig     `mapstructure:"rate_limit"`
+	Auth        AuthConfig          `mapstructure:"auth"`
+	Link        LinkConfig          `mapstructure:"link"`
+	Signing     SigningConfig       `mapstructure:"signing"`
+	Stats       StatsConfig         `mapstructure:"stats"`
 }
@@ -12,15 +12,24 @@ func TestLoadConfig(t *testing.T) {
 	// 创建临时配置文件
 	configContent := `
 server:
-  port: 8080
-  host: "0.0.0.0"
-  mode: "test"
+  http:
+    port: 8080
+    host: "0.0.0.0"
+    mode: "test"
   base_url: "http://127.0.0.1:8080"
 database:
   mysql:
     dsn: "test:test@tcp(localhost:3306)/test"
     max_idle_conns: 5
     max_open_conns: 10
+auth:
+  api_key_prefix: "sk_"
+  default_role: "viewer"
+  jwt:
+    hs256_secret: "secret"
+    issuer: "short-url"
+link:
+  reuse_existing: true
 `
 	tmpFile, err := os.CreateTemp("", "config-*.yaml")
 	require.NoError(t, err)
@@ -36,9 +45,14 @@ database:
 	require.NotNil(t, cfg)
 
 	// 验证配置值
-	assert.Equal(t, 8080, cfg.Server.Port)
-	assert.Equal(t, "0.0.0.0", cfg.Server.Host)
-	assert.Equal(t, "test", cfg.Server.Mode)
+	assert.Equal(t, 8080, cfg.Server.HTTP.Port)
+	assert.Equal(t, "0.0.0.0", cfg.Server.HTTP.Host)
+	assert.Equal(t, "test", cfg.Server.HTTP.Mode)
 	assert.Equal(t, "http://127.0.0.1:8080", cfg.Server.BaseURL)
 	assert.Equal(t, "test:test@tcp(localhost:3306)/test", cfg.Database.MySQL.DSN)
+	assert.Equal(t, "sk_", cfg.Auth.APIKeyPrefix)
+	assert.Equal(t, "viewer", cfg.Auth.DefaultRole)
+	assert.Equal(t, "secret", cfg.Auth.JWT.HS256Secret)
+	assert.Equal(t, "short-url", cfg.Auth.JWT.Issuer)
+	assert.True(t, cfg.Link.ReuseExisting)
 }
@@ -0,0 +1,111 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"generate-service/internal/config"
+	"log"
+
+	"github.com/IBM/sarama"
+)
+
+// MessageHandler 消息处理器接口（每个Topic对应一个实现）
+type MessageHandler interface {
+	// Handle 处理消息，返回处理是否成功，成功后才提交offset
+	Handle(topic string, value []byte) bool
+}
+
+type KafkaConsumer struct {
+	config   *config.KafkaConfig
+	consumer sarama.ConsumerGroup
+	handlers map[string]MessageHandler
+	ctx      context.Context
+	cancel   context.CancelFunc
+}
+
+func NewKafkaConsumer(cfg *config.KafkaConfig, handlers map[string]MessageHandler) (*KafkaConsumer, error) {
+	saramaCfg := sarama.NewConfig()
+	version, err := sarama.ParseKafkaVersion(cfg.Version)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing Kafka version: %w", err)
+	}
+	saramaCfg.Version = version
+	saramaCfg.ClientID = cfg.ClientID
+
+	// 消费者配置
+	saramaCfg.Consumer.Return.Errors = true
+	saramaCfg.Consumer.Group.Session.Timeout = cfg.Consumer.SessionTimeout
+	saramaCfg.Consumer.Group.Heartbeat.Interval = cfg.Consumer.SessionTimeout / 3 // 心跳间隔通常为会话超时的1/3
+	saramaCfg.Consumer.Offsets.Initial = sarama.OffsetOldest
+
+	consumer, err := sarama.NewConsumerGroup(cfg.Brokers, cfg.Consumer.GroupID, saramaCfg)
+	if err != nil {
+		return nil, fmt.Errorf("error creating consumer group: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &KafkaConsumer{
+		config:   cfg,
+		consumer: consumer,
+		handlers: handlers,
+		ctx:      ctx,
+		cancel:   cancel,
+	}, nil
+}
+
+// Start 启动消费者，阻塞直到 Close
+func (k *KafkaConsumer) Start() {
+	go func() {
+		for err := range k.consumer.Errors() {
+			log.Printf("Error from consumer: %v", err)
+		}
+	}()
+
+	for {
+		select {
+		case <-k.ctx.Done():
+			log.Printf("Consumer shutting down")
+			return
+		default:
+			if err := k.consumer.Consume(k.ctx, k.config.Consumer.Topics, k); err != nil {
+				if errors.Is(err, sarama.ErrClosedConsumerGroup) {
+					log.Printf("Consumer group closed")
+					return
+				}
+				log.Printf("Failed to consume message: %v", err)
+			}
+		}
+	}
+}
+
+func (k *KafkaConsumer) Close() error {
+	k.cancel()
+	return k.consumer.Close()
+}
+
+func (k *KafkaConsumer) Setup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+func (k *KafkaConsumer) Cleanup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+// ConsumeClaim 处理分区消息
+func (k *KafkaConsumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for msg := range claim.Messages() {
+		handler, ok := k.handlers[msg.Topic]
+		if !ok {
+			log.Printf("Skipping message due to missing handler for topic: %s", msg.Topic)
+			session.MarkMessage(msg, "")
+			continue
+		}
+		if handler.Handle(msg.Topic, msg.Value) {
+			session.MarkMessage(msg, "")
+		} else {
+			log.Printf("Failed to consume message for topic: %s", msg.Topic)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	"generate-service/internal/model"
+	"generate-service/internal/pkg/errors"
+	"generate-service/internal/service/link"
+	"log"
+)
+
+// LinkExhaustedHandler 访问次数达到上限后将链接置为过期
+type LinkExhaustedHandler struct {
+	linkService link.Service
+}
+
+func NewLinkExhaustedHandler(linkService link.Service) *LinkExhaustedHandler {
+	return &LinkExhaustedHandler{linkService: linkService}
+}
+
+func (h *LinkExhaustedHandler) Handle(topic string, value []byte) bool {
+	var msg model.LinkExhaustedMessage
+	if err := json.Unmarshal(value, &msg); err != nil {
+		// 无法解析的消息重试也不会成功，直接跳过
+		log.Printf("invalid link exhausted message: %v", err)
+		return true
+	}
+	err := h.linkService.ExpireExhausted(context.Background(), msg.WorkspaceID, msg.Domain, msg.ShortCode)
+	if err != nil && err != errors.ErrLinkNotFound {
+		log.Printf("failed to expire exhausted link %s: %v", msg.ShortCode, err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"generate-service/internal/model"
+	"generate-service/internal/service/auth"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyHandler struct {
+	authService auth.Service
+}
+
+func NewAPIKeyHandler(authService auth.Service) *APIKeyHandler {
+	return &APIKeyHandler{
+		authService: authService,
+	}
+}
+
+// CreateAPIKey
+// @Router /api/v1/api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var req model.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	resp, err := h.authService.CreateAPIKey(c.Request.Context(), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, resp)
+}
+
+// ListAPIKeys
+// @Router /api/v1/api-keys [get]
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	resp, err := h.authService.ListAPIKeys(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// RevokeAPIKey
+// @Router /api/v1/api-keys/{id} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "API key id is required",
+		})
+		return
+	}
+	if err := h.authService.RevokeAPIKey(c.Request.Context(), id); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
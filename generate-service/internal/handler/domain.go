@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"generate-service/internal/model"
+	"generate-service/internal/service/domain"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// App 关联文档由系统定期拉取，允许短时间缓存
+const appLinksCacheControl = "public, max-age=3600"
+
+type DomainHandler struct {
+	domainService domain.Service
+}
+
+func NewDomainHandler(domainService domain.Service) *DomainHandler {
+	return &DomainHandler{
+		domainService: domainService,
+	}
+}
+
+// AddDomain
+// @Router /api/v1/domains [post]
+func (h *DomainHandler) AddDomain(c *gin.Context) {
+	var req model.CreateDomainRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	resp, err := h.domainService.AddDomain(c.Request.Context(), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, resp)
+}
+
+// ListDomains
+// @Router /api/v1/domains [get]
+func (h *DomainHandler) ListDomains(c *gin.Context) {
+	resp, err := h.domainService.ListDomains(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// RemoveDomain
+// @Router /api/v1/domains/{id} [delete]
+func (h *DomainHandler) RemoveDomain(c *gin.Context) {
+	if err := h.domainService.RemoveDomain(c.Request.Context(), c.Param("id")); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// VerifyDomain 检查 DNS TXT 记录并验证域名所有权
+// @Router /api/v1/domains/{id}/verify [post]
+func (h *DomainHandler) VerifyDomain(c *gin.Context) {
+	resp, err := h.domainService.VerifyDomain(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// UpdateAppLinks 设置域名关联的 iOS 和 Android App
+// @Router /api/v1/domains/{id}/app-links [put]
+func (h *DomainHandler) UpdateAppLinks(c *gin.Context) {
+	var req model.UpdateAppLinksRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	resp, err := h.domainService.UpdateAppLinks(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// AppleAppSiteAssociation 按请求主机名返回 iOS universal link 关联文档
+// @Router /.well-known/apple-app-site-association [get]
+func (h *DomainHandler) AppleAppSiteAssociation(c *gin.Context) {
+	doc, err := h.domainService.AppleAppSiteAssociation(c.Request.Context(), c.Request.Host)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.Header("Cache-Control", appLinksCacheControl)
+	c.JSON(http.StatusOK, doc)
+}
+
+// AssetLinks 按请求主机名返回 Android App Links 关联文档
+// @Router /.well-known/assetlinks.json [get]
+func (h *DomainHandler) AssetLinks(c *gin.Context) {
+	statements, err := h.domainService.AssetLinks(c.Request.Context(), c.Request.Host)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.Header("Cache-Control", appLinksCacheControl)
+	c.JSON(http.StatusOK, statements)
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"fmt"
+	"generate-service/internal/model"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 链接的 ETag 即其版本号，每次修改后递增
+func setLinkETag(c *gin.Context, version uint) {
+	c.Header("ETag", fmt.Sprintf(`"%d"`, version))
+}
+
+// 解析 If-Match 请求头中的链接版本。未设置或为 * 时返回 nil 表示不校验版本，
+// 无法识别的值（包括弱校验 ETag 和多个 ETag）返回 ok=false
+func ifMatchVersion(c *gin.Context) (version *uint, ok bool) {
+	value := strings.TrimSpace(c.GetHeader("If-Match"))
+	if value == "" || value == "*" {
+		return nil, true
+	}
+	if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+		return nil, false
+	}
+	v, err := strconv.ParseUint(value[1:len(value)-1], 10, 32)
+	if err != nil {
+		return nil, false
+	}
+	u := uint(v)
+	return &u, true
+}
+
+// 无法识别的 If-Match 按与当前版本不匹配处理
+func preconditionFailed(c *gin.Context) {
+	c.JSON(http.StatusPreconditionFailed, model.ErrorResponse{
+		Error:   "version_conflict",
+		Message: "If-Match must be a single ETag returned by the server",
+	})
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"generate-service/internal/model"
+	"generate-service/internal/service/folder"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FolderHandler struct {
+	folderService folder.Service
+}
+
+func NewFolderHandler(folderService folder.Service) *FolderHandler {
+	return &FolderHandler{
+		folderService: folderService,
+	}
+}
+
+// CreateFolder
+// @Router /api/v1/folders [post]
+func (h *FolderHandler) CreateFolder(c *gin.Context) {
+	var req model.CreateFolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	resp, err := h.folderService.CreateFolder(c.Request.Context(), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, resp)
+}
+
+// ListFolders
+// @Router /api/v1/folders [get]
+func (h *FolderHandler) ListFolders(c *gin.Context) {
+	resp, err := h.folderService.ListFolders(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// UpdateFolder
+// @Router /api/v1/folders/{id} [put]
+func (h *FolderHandler) UpdateFolder(c *gin.Context) {
+	var req model.UpdateFolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	resp, err := h.folderService.UpdateFolder(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeleteFolder
+// @Router /api/v1/folders/{id} [delete]
+func (h *FolderHandler) DeleteFolder(c *gin.Context) {
+	if err := h.folderService.DeleteFolder(c.Request.Context(), c.Param("id")); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
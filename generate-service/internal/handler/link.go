@@ -5,6 +5,8 @@ import (
 	"generate-service/internal/model"
 	linkSrc "generate-service/internal/service/link"
 	"net/http"
+	"net/url"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -38,12 +40,19 @@ func (h *LinkHandler) CreateShortURL(c *gin.Context) {
 	}
 
 	resp := model.CreateShortResponse{
-		ShortURL:  h.buildShortURL(link.ShortCode),
+		Domain:    link.Domain,
+		ShortURL:  link.ShortURL(h.baseURL),
 		LongURL:   link.LongURL,
 		ShortCode: link.ShortCode,
 		CreatedAt: link.CreatedAt,
 		ExpiresAt: link.ExpiresAt,
-		QRCodeURL: h.buildQRCodeURL(link.ShortCode),
+		QRCodeURL: h.buildQRCodeURL(link),
+		Reused:    link.Reused,
+	}
+	// 复用已有短链时未创建新资源，返回200
+	if link.Reused {
+		c.JSON(http.StatusOK, resp)
+		return
 	}
 	c.JSON(http.StatusCreated, resp)
 }
@@ -60,15 +69,16 @@ func (h *LinkHandler) GetLinkInfo(c *gin.Context) {
 		return
 	}
 
-	linkInfo, err := h.linkService.GetLinkInfo(c.Request.Context(), shortCode)
+	linkInfo, err := h.linkService.GetLinkInfo(c.Request.Context(), c.Query("domain"), shortCode)
 	if err != nil {
 		c.Error(err)
 		return
 	}
+	setLinkETag(c, linkInfo.Version)
 	c.JSON(http.StatusOK, linkInfo)
 }
 
-// UpdateLink
+// UpdateLink 带 If-Match 请求头时只在版本一致时更新
 // @Router /api/v1/links/{code} [put]
 func (h *LinkHandler) UpdateLink(c *gin.Context) {
 	shortCode := c.Param("code")
@@ -79,6 +89,11 @@ func (h *LinkHandler) UpdateLink(c *gin.Context) {
 		})
 		return
 	}
+	ifMatch, ok := ifMatchVersion(c)
+	if !ok {
+		preconditionFailed(c)
+		return
+	}
 	var req model.UpdateLinkRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, model.ErrorResponse{
@@ -88,15 +103,50 @@ func (h *LinkHandler) UpdateLink(c *gin.Context) {
 		return
 	}
 
-	linkInfo, err := h.linkService.UpdateLink(c.Request.Context(), shortCode, &req)
+	linkInfo, err := h.linkService.UpdateLink(c.Request.Context(), c.Query("domain"), shortCode, ifMatch, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	setLinkETag(c, linkInfo.Version)
+	c.JSON(http.StatusOK, linkInfo)
+}
+
+// PatchLink 按 JSON Merge Patch 部分更新链接，值为 null 的字段恢复默认值
+// @Router /api/v1/links/{code} [patch]
+func (h *LinkHandler) PatchLink(c *gin.Context) {
+	switch c.ContentType() {
+	case "application/merge-patch+json", "application/json":
+	default:
+		c.JSON(http.StatusUnsupportedMediaType, model.ErrorResponse{
+			Error:   "unsupported_media_type",
+			Message: "Content-Type must be application/merge-patch+json",
+		})
+		return
+	}
+	ifMatch, ok := ifMatchVersion(c)
+	if !ok {
+		preconditionFailed(c)
+		return
+	}
+	patch, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+	linkInfo, err := h.linkService.PatchLink(c.Request.Context(), c.Query("domain"), c.Param("code"), ifMatch, patch)
 	if err != nil {
 		c.Error(err)
 		return
 	}
+	setLinkETag(c, linkInfo.Version)
 	c.JSON(http.StatusOK, linkInfo)
 }
 
-// DeleteLink
+// DeleteLink 带 If-Match 请求头时只在版本一致时删除
 // @Router /api/v1/links/{code} [delete]
 func (h *LinkHandler) DeleteLink(c *gin.Context) {
 	shortCode := c.Param("code")
@@ -107,7 +157,12 @@ func (h *LinkHandler) DeleteLink(c *gin.Context) {
 		})
 		return
 	}
-	err := h.linkService.DeleteLink(c.Request.Context(), shortCode)
+	ifMatch, ok := ifMatchVersion(c)
+	if !ok {
+		preconditionFailed(c)
+		return
+	}
+	err := h.linkService.DeleteLink(c.Request.Context(), c.Query("domain"), shortCode, ifMatch)
 	if err != nil {
 		c.Error(err)
 		return
@@ -115,6 +170,56 @@ func (h *LinkHandler) DeleteLink(c *gin.Context) {
 	c.Status(http.StatusOK)
 }
 
+// SignLink 生成带有效期的签名地址
+// @Router /api/v1/links/{code}/signed-url [post]
+func (h *LinkHandler) SignLink(c *gin.Context) {
+	var req model.SignLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+	signed, err := h.linkService.SignLink(c.Request.Context(), c.Query("domain"), c.Param("code"), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, signed)
+}
+
+// GetLinkHistory 查询链接的修订历史
+// @Router /api/v1/links/{code}/history [get]
+func (h *LinkHandler) GetLinkHistory(c *gin.Context) {
+	history, err := h.linkService.GetLinkHistory(c.Request.Context(), c.Query("domain"), c.Param("code"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, history)
+}
+
+// RollbackLink 将链接恢复到指定版本
+// @Router /api/v1/links/{code}/rollback/{version} [post]
+func (h *LinkHandler) RollbackLink(c *gin.Context) {
+	version, err := strconv.ParseUint(c.Param("version"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "invalid_version",
+			Message: "Version must be a non-negative integer",
+		})
+		return
+	}
+	linkInfo, err := h.linkService.RollbackLink(c.Request.Context(), c.Query("domain"), c.Param("code"), uint(version))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	setLinkETag(c, linkInfo.Version)
+	c.JSON(http.StatusOK, linkInfo)
+}
+
 // ListLinks
 // @Router /api/v1/links [get]
 func (h *LinkHandler) ListLinks(c *gin.Context) {
@@ -153,12 +258,49 @@ func (h *LinkHandler) BatchCreate(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
-// 构建完整的短链URL
-func (h *LinkHandler) buildShortURL(shortCode string) string {
-	return fmt.Sprintf("%s/%s", h.baseURL, shortCode)
+// TagLinks
+// @Router /api/v1/links/bulk/tag [post]
+func (h *LinkHandler) TagLinks(c *gin.Context) {
+	var req model.BulkTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+	resp, err := h.linkService.TagLinks(c.Request.Context(), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// UntagLinks
+// @Router /api/v1/links/bulk/untag [post]
+func (h *LinkHandler) UntagLinks(c *gin.Context) {
+	var req model.BulkTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+	resp, err := h.linkService.UntagLinks(c.Request.Context(), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
 }
 
-// 构建二维码URL
-func (h *LinkHandler) buildQRCodeURL(shortCode string) string {
-	return fmt.Sprintf("%s/api/v1/links/%s/qrcode", h.baseURL, shortCode)
+// 构建二维码URL，自定义域名的链接需带上 domain 参数
+func (h *LinkHandler) buildQRCodeURL(link *model.Link) string {
+	qrURL := fmt.Sprintf("%s/api/v1/links/qrcode/%s", h.baseURL, link.ShortCode)
+	if link.Domain != "" {
+		qrURL += "?domain=" + url.QueryEscape(link.Domain)
+	}
+	return qrURL
 }
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"generate-service/internal/model"
+	"generate-service/internal/service/pixel"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PixelHandler struct {
+	pixelService pixel.Service
+}
+
+func NewPixelHandler(pixelService pixel.Service) *PixelHandler {
+	return &PixelHandler{
+		pixelService: pixelService,
+	}
+}
+
+// CreatePixel
+// @Router /api/v1/pixels [post]
+func (h *PixelHandler) CreatePixel(c *gin.Context) {
+	var req model.CreatePixelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	resp, err := h.pixelService.CreatePixel(c.Request.Context(), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, resp)
+}
+
+// ListPixels
+// @Router /api/v1/pixels [get]
+func (h *PixelHandler) ListPixels(c *gin.Context) {
+	resp, err := h.pixelService.ListPixels(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// UpdatePixel
+// @Router /api/v1/pixels/{id} [put]
+func (h *PixelHandler) UpdatePixel(c *gin.Context) {
+	var req model.UpdatePixelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	resp, err := h.pixelService.UpdatePixel(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeletePixel
+// @Router /api/v1/pixels/{id} [delete]
+func (h *PixelHandler) DeletePixel(c *gin.Context) {
+	if err := h.pixelService.DeletePixel(c.Request.Context(), c.Param("id")); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
@@ -1,7 +1,6 @@
 package handler
 
 import (
-	"fmt"
 	"generate-service/internal/model"
 	"generate-service/internal/service/link"
 	"net/http"
@@ -13,13 +12,11 @@ import (
 
 type QRCodeHandler struct {
 	linkService link.Service
-	baseURL     string
 }
 
-func NewQRCodeHandler(linkService link.Service, baseURL string) *QRCodeHandler {
+func NewQRCodeHandler(linkService link.Service) *QRCodeHandler {
 	return &QRCodeHandler{
 		linkService: linkService,
-		baseURL:     baseURL,
 	}
 }
 
@@ -36,7 +33,7 @@ func (h *QRCodeHandler) GenerateQRCode(c *gin.Context) {
 	}
 
 	// 验证短码是否存在
-	_, err := h.linkService.GetLinkInfo(c.Request.Context(), shortCode)
+	linkInfo, err := h.linkService.GetLinkInfo(c.Request.Context(), c.Query("domain"), shortCode)
 	if err != nil {
 		c.Error(err)
 		return
@@ -55,11 +52,8 @@ func (h *QRCodeHandler) GenerateQRCode(c *gin.Context) {
 		size = 1024
 	}
 
-	// 生成短链接URL
-	shortURL := fmt.Sprintf("%s/%s", h.baseURL, shortCode)
-
-	// 生成二维码
-	png, err := qrcode.Encode(shortURL, qrcode.Medium, size)
+	// 生成二维码，使用链接所属域名的短链
+	png, err := qrcode.Encode(linkInfo.ShortURL, qrcode.Medium, size)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse{
 			Error:   "qrcode generation failed",
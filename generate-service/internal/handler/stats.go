@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"generate-service/internal/config"
+	"generate-service/internal/service/link"
+	"net/http/httputil"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HeaderInternalToken statistics-service 校验的内部调用令牌
+const HeaderInternalToken = "X-Internal-Token"
+
+// StatsHandler 代理链接统计查询。statistics-service 不做认证，由这里校验调用方
+// 对链接的访问权限后转发，并以链接所在的工作空间作为租户
+type StatsHandler struct {
+	linkService link.Service
+	proxy       *httputil.ReverseProxy
+	token       string
+}
+
+func NewStatsHandler(linkService link.Service, cfg config.StatsConfig) (*StatsHandler, error) {
+	target, err := url.Parse(cfg.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+	return &StatsHandler{
+		linkService: linkService,
+		proxy:       httputil.NewSingleHostReverseProxy(target),
+		token:       cfg.InternalToken,
+	}, nil
+}
+
+// ProxyStats
+// @Router /api/v1/links/{code}/stats/{path} [get]
+func (h *StatsHandler) ProxyStats(c *gin.Context) {
+	shortCode := c.Param("code")
+	info, err := h.linkService.GetLinkInfo(c.Request.Context(), c.Query("domain"), shortCode)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	req := c.Request.Clone(c.Request.Context())
+	req.URL.Path = "/api/v1/stats/" + url.PathEscape(shortCode) + c.Param("path")
+	req.URL.RawPath = ""
+	query := req.URL.Query()
+	query.Set("domain", info.Domain)
+	req.URL.RawQuery = query.Encode()
+	// 调用方凭证不转发给下游
+	req.Header.Del("Authorization")
+	req.Header.Del("X-API-Key")
+	req.Header.Del("Cookie")
+	req.Header.Set("X-Workspace-ID", info.WorkspaceID)
+	req.Header.Set(HeaderInternalToken, h.token)
+	req.Host = ""
+	h.proxy.ServeHTTP(c.Writer, req)
+}
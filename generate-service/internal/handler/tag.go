@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"generate-service/internal/model"
+	"generate-service/internal/service/tag"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TagHandler struct {
+	tagService tag.Service
+}
+
+func NewTagHandler(tagService tag.Service) *TagHandler {
+	return &TagHandler{
+		tagService: tagService,
+	}
+}
+
+// CreateTag
+// @Router /api/v1/tags [post]
+func (h *TagHandler) CreateTag(c *gin.Context) {
+	var req model.CreateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	resp, err := h.tagService.CreateTag(c.Request.Context(), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, resp)
+}
+
+// ListTags
+// @Router /api/v1/tags [get]
+func (h *TagHandler) ListTags(c *gin.Context) {
+	resp, err := h.tagService.ListTags(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// UpdateTag
+// @Router /api/v1/tags/{id} [put]
+func (h *TagHandler) UpdateTag(c *gin.Context) {
+	var req model.UpdateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	resp, err := h.tagService.UpdateTag(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeleteTag
+// @Router /api/v1/tags/{id} [delete]
+func (h *TagHandler) DeleteTag(c *gin.Context) {
+	if err := h.tagService.DeleteTag(c.Request.Context(), c.Param("id")); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"time"
+)
+
+type APIKeyStatus string
+
+const (
+	APIKeyStatusActive  APIKeyStatus = "active"
+	APIKeyStatusRevoked APIKeyStatus = "revoked"
+)
+
+// APIKey API密钥模型，只保存密钥的SHA-256摘要，明文仅在创建时返回一次
+type APIKey struct {
+	ID          uint64       `gorm:"primaryKey" json:"id"`
+	Name        string       `gorm:"size:100;not null" json:"name"`
+	KeyPrefix   string       `gorm:"size:16;not null" json:"key_prefix"`
+	KeyHash     string       `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	OwnerID     string       `gorm:"size:100;not null;index" json:"owner_id"`
+	Role        string       `gorm:"size:20;not null" json:"role"`
+	Status      APIKeyStatus `gorm:"size:20;default:active" json:"status"`
+	ExpiresAt   *time.Time   `json:"expires_at,omitempty"`
+	LastUsedAt  *time.Time   `json:"last_used_at,omitempty"`
+	CreatedAt   time.Time    `gorm:"autoCreateTime" json:"created_at"`
+	CreatedBy   string       `gorm:"size:100" json:"created_by,omitempty"`
+	UpdatedAt   time.Time    `gorm:"autoUpdateTime" json:"updated_at"`
+	UpdatedBy   string       `gorm:"size:100" json:"updated_by,omitempty"`
+	Description string       `gorm:"size:500" json:"description,omitempty"`
+	DeleteFlag  string       `gorm:"size:1" json:"delete_flag,omitempty"`
+	Version     uint         `gorm:"default:0" json:"version"`
+}
+
+// TableName 指定表名
+func (k *APIKey) TableName() string {
+	return "api_keys"
+}
+
+// IsActive 检查密钥是否可用
+func (k *APIKey) IsActive() bool {
+	if k.Status != APIKeyStatusActive {
+		return false
+	}
+	if k.ExpiresAt != nil && k.ExpiresAt.Before(time.Now()) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+// CreateAPIKeyRequest 创建API密钥请求
+type CreateAPIKeyRequest struct {
+	Name        string     `json:"name" binding:"required,max=100"`
+	Role        *string    `json:"role,omitempty" binding:"omitempty,oneof=viewer editor admin"` // 为空时继承创建者角色
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	Description *string    `json:"description,omitempty" binding:"omitempty,max=500"`
+}
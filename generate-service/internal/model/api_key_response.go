@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// APIKeyResponse API密钥信息响应（不包含明文密钥）
+type APIKeyResponse struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	KeyPrefix   string     `json:"key_prefix"`
+	Role        string     `json:"role"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	Description string     `json:"description,omitempty"`
+}
+
+// CreateAPIKeyResponse 创建API密钥响应，明文密钥只在此时返回一次
+type CreateAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+// ListAPIKeysResponse API密钥列表响应
+type ListAPIKeysResponse struct {
+	Keys []APIKeyResponse `json:"keys"`
+}
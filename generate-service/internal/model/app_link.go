@@ -0,0 +1,73 @@
+package model
+
+import "regexp"
+
+var (
+	androidPackagePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*(\.[A-Za-z][A-Za-z0-9_]*)+$`)
+	appleAppIDPattern     = regexp.MustCompile(`^[A-Z0-9]{10}\.[A-Za-z0-9.-]+$`)
+	certFingerprint       = regexp.MustCompile(`^([0-9A-F]{2}:){31}[0-9A-F]{2}$`)
+)
+
+// DeepLink 移动端深度链接，iOS 和 Android 访问者优先打开 App，未安装时跳转到应用商店，
+// 未配置商店地址时跳转到链接的网页地址
+type DeepLink struct {
+	IOSURL          string `json:"ios_url,omitempty" binding:"omitempty,max=2048"`        // App 的 URL scheme 或 universal link，如 myapp://item/1
+	IOSStoreURL     string `json:"ios_store_url,omitempty" binding:"omitempty,url"`       // App Store 地址
+	AndroidURL      string `json:"android_url,omitempty" binding:"omitempty,max=2048"`    // App 的 URL scheme 地址，跳转时转换为 intent://
+	AndroidPackage  string `json:"android_package,omitempty" binding:"omitempty,max=255"` // App 包名，如 com.example.app
+	AndroidStoreURL string `json:"android_store_url,omitempty" binding:"omitempty,url"`   // Google Play 地址，为空时按包名生成
+}
+
+// IsEmpty 是否未配置任何平台
+func (d *DeepLink) IsEmpty() bool {
+	return d.IOSURL == "" && d.IOSStoreURL == "" &&
+		d.AndroidURL == "" && d.AndroidPackage == "" && d.AndroidStoreURL == ""
+}
+
+// ValidAndroidPackage 是否为合法的 Android 包名，如 com.example.app
+func ValidAndroidPackage(name string) bool {
+	return androidPackagePattern.MatchString(name)
+}
+
+// ValidAppleAppID 是否为合法的 Apple App ID，格式为 <TeamID>.<BundleID>
+func ValidAppleAppID(id string) bool {
+	return appleAppIDPattern.MatchString(id)
+}
+
+// ValidCertFingerprint 是否为冒号分隔的大写 SHA-256 证书指纹
+func ValidCertFingerprint(fp string) bool {
+	return certFingerprint.MatchString(fp)
+}
+
+// AndroidApp 关联到自定义域名的 Android App，用于生成 assetlinks.json
+type AndroidApp struct {
+	PackageName  string   `json:"package_name" binding:"required,max=255"`
+	Fingerprints []string `json:"sha256_cert_fingerprints" binding:"required,min=1,max=10,dive,required"` // 签名证书的 SHA-256 指纹，如 14:6D:E9:...
+}
+
+// AppleAppSiteAssociation apple-app-site-association 文档
+type AppleAppSiteAssociation struct {
+	AppLinks AppleAppLinks `json:"applinks"`
+}
+
+type AppleAppLinks struct {
+	Apps    []string          `json:"apps"` // 固定为空数组
+	Details []AppleAppDetails `json:"details"`
+}
+
+type AppleAppDetails struct {
+	AppID string   `json:"appID"`
+	Paths []string `json:"paths"`
+}
+
+// AssetLinkStatement assetlinks.json 中的一条声明
+type AssetLinkStatement struct {
+	Relation []string        `json:"relation"`
+	Target   AssetLinkTarget `json:"target"`
+}
+
+type AssetLinkTarget struct {
+	Namespace              string   `json:"namespace"`
+	PackageName            string   `json:"package_name"`
+	SHA256CertFingerprints []string `json:"sha256_cert_fingerprints"`
+}
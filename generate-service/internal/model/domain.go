@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// Domain 工作空间注册的自定义品牌域名，每个域名拥有独立的短码命名空间
+type Domain struct {
+	ID          uint64 `gorm:"primaryKey" json:"id"`
+	WorkspaceID uint64 `gorm:"not null;uniqueIndex:uk_workspace_hostname" json:"workspace_id"`
+	Hostname    string `gorm:"size:253;not null;uniqueIndex:uk_workspace_hostname" json:"hostname"`
+
+	// 所有权验证：注册时生成令牌，发布到 DNS TXT 记录并验证通过后才能创建链接和解析跳转。
+	// 验证通过时写入 VerifiedHostname，其唯一索引保证同一主机名只归属一个工作空间
+	VerificationToken string     `gorm:"size:64;not null" json:"-"`
+	VerifiedHostname  *string    `gorm:"size:253;uniqueIndex" json:"-"`
+	VerifiedAt        *time.Time `json:"verified_at,omitempty"`
+
+	AppleAppIDs []string     `gorm:"type:json;serializer:json" json:"apple_app_ids,omitempty"` // 关联的 iOS App，用于 apple-app-site-association
+	AndroidApps []AndroidApp `gorm:"type:json;serializer:json" json:"android_apps,omitempty"`  // 关联的 Android App，用于 assetlinks.json
+	CreatedAt   time.Time    `gorm:"autoCreateTime" json:"created_at"`
+	CreatedBy   string       `gorm:"size:100" json:"created_by,omitempty"`
+	UpdatedAt   time.Time    `gorm:"autoUpdateTime" json:"updated_at"`
+	UpdatedBy   string       `gorm:"size:100" json:"updated_by,omitempty"`
+	DeleteFlag  string       `gorm:"size:1" json:"delete_flag,omitempty"`
+	Version     uint         `gorm:"default:0" json:"version"`
+}
+
+// TableName 指定表名
+func (d *Domain) TableName() string {
+	return "domains"
+}
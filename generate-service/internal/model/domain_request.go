@@ -0,0 +1,12 @@
+package model
+
+// CreateDomainRequest 注册自定义域名请求
+type CreateDomainRequest struct {
+	Hostname string `json:"hostname" binding:"required,max=253"`
+}
+
+// UpdateAppLinksRequest 设置域名关联的 App，整体替换，空数组表示清除
+type UpdateAppLinksRequest struct {
+	AppleAppIDs []string     `json:"apple_app_ids" binding:"omitempty,max=20,dive,required"` // <TeamID>.<BundleID>
+	AndroidApps []AndroidApp `json:"android_apps" binding:"omitempty,max=20,dive"`
+}
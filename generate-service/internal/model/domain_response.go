@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// DomainResponse 自定义域名响应
+type DomainResponse struct {
+	ID          string       `json:"id"`
+	Hostname    string       `json:"hostname"`
+	Verified    bool         `json:"verified"`
+	AppleAppIDs []string     `json:"apple_app_ids,omitempty"`
+	AndroidApps []AndroidApp `json:"android_apps,omitempty"`
+	CreatedAt   time.Time    `json:"created_at"`
+
+	Verification *DomainVerification `json:"verification,omitempty"` // 未验证时返回需要发布的 DNS TXT 记录
+}
+
+// DomainVerification 域名所有权验证所需的 DNS 记录
+type DomainVerification struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// ListDomainsResponse 自定义域名列表响应
+type ListDomainsResponse struct {
+	Domains []DomainResponse `json:"domains"`
+}
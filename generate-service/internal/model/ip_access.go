@@ -0,0 +1,35 @@
+package model
+
+import "shared/ipaccess"
+
+// IPAccess CIDR 访问控制列表，命中 Deny 的地址拒绝访问；Allow 非空时只允许其中的地址。
+// 条目可以是地址段或单个IP，保存时统一规范化为地址段
+type IPAccess struct {
+	Allow []string `json:"allow,omitempty"` // 如 10.0.0.0/8、2001:db8::/32
+	Deny  []string `json:"deny,omitempty"`
+}
+
+// IsEmpty 是否未设置任何地址段
+func (a *IPAccess) IsEmpty() bool {
+	return len(a.Allow) == 0 && len(a.Deny) == 0
+}
+
+// Normalize 校验并规范化地址段，单个IP转换为 /32 或 /128。未设置任何地址段时返回 nil
+func (a *IPAccess) Normalize() (*IPAccess, error) {
+	if a == nil {
+		return nil, nil
+	}
+	allow, err := ipaccess.Normalize(a.Allow)
+	if err != nil {
+		return nil, err
+	}
+	deny, err := ipaccess.Normalize(a.Deny)
+	if err != nil {
+		return nil, err
+	}
+	normalized := &IPAccess{Allow: allow, Deny: deny}
+	if normalized.IsEmpty() {
+		return nil, nil
+	}
+	return normalized, nil
+}
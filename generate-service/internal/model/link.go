@@ -3,6 +3,7 @@ package model
 import (
 	"database/sql/driver"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -12,6 +13,25 @@ const (
 	LinkStatusActive   LinkStatus = "active"
 	LinkStatusDisabled LinkStatus = "disabled"
 	LinkStatusExpired  LinkStatus = "expired"
+	LinkStatusFlagged  LinkStatus = "flagged" // 被标记为可疑，访问前展示风险提示页
+	LinkStatusPending  LinkStatus = "pending" // 尚未到生效时间，仅用于展示，不存储
+)
+
+// 跳转方式，空表示 302
+const (
+	RedirectTypeMovedPermanently  = "301"
+	RedirectTypeFound             = "302"
+	RedirectTypeTemporaryRedirect = "307"
+	RedirectTypePermanentRedirect = "308"
+	RedirectTypeMetaRefresh       = "meta_refresh" // 返回 HTML 页面，通过 meta refresh 跳转
+	RedirectTypeJavaScript        = "javascript"   // 返回 HTML 页面，通过脚本跳转
+)
+
+// 转发查询参数与目标地址参数同名时的处理方式
+const (
+	QueryConflictDestination = "destination" // 保留目标地址的参数，默认
+	QueryConflictIncoming    = "incoming"    // 使用访问地址的参数
+	QueryConflictAppend      = "append"      // 两者都保留
 )
 
 // Scan 实现数据库接口扫描
@@ -37,21 +57,49 @@ func (ls LinkStatus) Value() (driver.Value, error) {
 	return string(ls), nil
 }
 
+// Reachable 是否允许访问，被标记为可疑的链接仍可在确认风险提示后访问
+func (ls LinkStatus) Reachable() bool {
+	return ls == LinkStatusActive || ls == LinkStatusFlagged
+}
+
 // Link 短链接模型
 type Link struct {
-	ID          uint64     `gorm:"primaryKey" json:"id"`
-	ShortCode   string     `gorm:"size:10;not null;uniqueIndex" json:"short_code"`
-	LongURL     string     `gorm:"type:text;not null" json:"long_url"`
-	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
-	ClickCount  int64      `gorm:"default:0" json:"click_count"`
-	Status      LinkStatus `gorm:"size:20;default:active" json:"status"`
-	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
-	CreatedBy   string     `gorm:"size:100" json:"created_by,omitempty"`
-	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
-	UpdatedBy   string     `gorm:"size:100" json:"updated_by,omitempty"`
-	Description string     `gorm:"size:500" json:"description,omitempty"`
-	DeleteFlag  string     `gorm:"size:1" json:"delete_flag,omitempty"`
-	Version     uint       `gorm:"default:0" json:"version"`
+	ID            uint64         `gorm:"primaryKey" json:"id"`
+	WorkspaceID   uint64         `gorm:"not null;index;index:idx_owner_url_hash,priority:1" json:"workspace_id"`
+	Domain        string         `gorm:"size:253;not null;default:'';uniqueIndex:uk_domain_code" json:"domain,omitempty"` // 空表示默认域名
+	ShortCode     string         `gorm:"size:10;not null;uniqueIndex:uk_domain_code" json:"short_code"`
+	LongURL       string         `gorm:"type:text;not null" json:"long_url"`
+	Template      bool           `gorm:"not null;default:false" json:"template,omitempty"`                         // LongURL 为带占位符的目标地址模板，跳转时展开
+	URLHash       string         `gorm:"size:64;not null;default:'';index:idx_owner_url_hash,priority:3" json:"-"` // 标准化后长链接的SHA-256，用于去重查询
+	PasswordHash  string         `gorm:"size:100;not null;default:''" json:"-"`                                    // 访问密码的 bcrypt 哈希，空表示无需密码
+	FolderID      uint64         `gorm:"not null;default:0;index" json:"folder_id,omitempty"`                      // 0表示未归入文件夹
+	ActivateAt    *time.Time     `json:"activate_at,omitempty"`                                                    // 生效时间，nil表示立即生效
+	ExpiresAt     *time.Time     `json:"expires_at,omitempty"`
+	ClickCount    int64          `gorm:"default:0" json:"click_count"`
+	MaxClicks     int64          `gorm:"not null;default:0" json:"max_clicks,omitempty"`              // 最大访问次数，0表示不限制
+	Rules         []RedirectRule `gorm:"type:json;serializer:json" json:"rules,omitempty"`            // 条件跳转规则，均未命中时跳转到 LongURL
+	Variants      []LinkVariant  `gorm:"type:json;serializer:json" json:"variants,omitempty"`         // A/B 测试分组，未命中规则的访问按权重分流
+	DeepLink      *DeepLink      `gorm:"type:json;serializer:json" json:"deep_link,omitempty"`        // 移动端深度链接，iOS 和 Android 访问者优先打开 App
+	OpenGraph     *OpenGraph     `gorm:"type:json;serializer:json" json:"open_graph,omitempty"`       // 分享卡片覆盖值，社交平台爬虫抓取时使用
+	IPAccess      *IPAccess      `gorm:"type:json;serializer:json" json:"ip_access,omitempty"`        // IP访问控制列表，跳转时按客户端IP检查
+	ForwardQuery  bool           `gorm:"not null;default:false" json:"forward_query,omitempty"`       // 跳转时转发访问地址的查询参数
+	QueryConflict string         `gorm:"size:20;not null;default:''" json:"query_conflict,omitempty"` // 转发参数与目标地址参数同名时的处理方式，空表示保留目标地址的参数
+	ForwardPath   bool           `gorm:"not null;default:false" json:"forward_path,omitempty"`        // 将短码之后的路径追加到目标地址
+	RedirectType  string         `gorm:"size:20;not null;default:''" json:"redirect_type,omitempty"`  // 跳转方式，空表示 302
+	SignedOnly    bool           `gorm:"not null;default:false" json:"signed_only,omitempty"`         // 只允许通过签名地址访问
+	Status        LinkStatus     `gorm:"size:20;default:active" json:"status"`
+	CreatedAt     time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	CreatedBy     string         `gorm:"size:100;index:idx_owner_url_hash,priority:2" json:"created_by,omitempty"`
+	UpdatedAt     time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	UpdatedBy     string         `gorm:"size:100" json:"updated_by,omitempty"`
+	Description   string         `gorm:"size:500" json:"description,omitempty"`
+	DeleteFlag    string         `gorm:"size:1" json:"delete_flag,omitempty"`
+	Version       uint           `gorm:"default:0" json:"version"`
+
+	Reused bool    `gorm:"-" json:"-"` // 本次创建是否复用了已有链接
+	Pixels []Pixel `gorm:"-" json:"-"` // 挂载的重定向像素，保存在关联表中，发送缓存消息前加载
+
+	WorkspaceIPAccess *IPAccess `gorm:"-" json:"-"` // 所属工作空间的IP访问控制列表，回源查询时加载
 }
 
 // TableName 指定表名
@@ -61,12 +109,39 @@ func (l *Link) TableName() string {
 
 // IsActive 检查链接是否有效
 func (l *Link) IsActive() bool {
-	if l.Status != LinkStatusActive {
+	if !l.Status.Reachable() {
 		return false
 	}
 
 	if l.ExpiresAt != nil && l.ExpiresAt.Before(time.Now()) {
 		return false
 	}
-	return true
+	return !l.Pending()
+}
+
+// Pending 检查链接是否已启用但尚未到生效时间
+func (l *Link) Pending() bool {
+	return l.Status.Reachable() && l.ActivateAt != nil && l.ActivateAt.After(time.Now())
+}
+
+// EffectiveStatus 实际状态，已过了过期时间返回 expired，尚未到生效时间返回 pending
+func (l *Link) EffectiveStatus() LinkStatus {
+	if !l.Status.Reachable() {
+		return l.Status
+	}
+	if l.ExpiresAt != nil && l.ExpiresAt.Before(time.Now()) {
+		return LinkStatusExpired
+	}
+	if l.Pending() {
+		return LinkStatusPending
+	}
+	return l.Status
+}
+
+// ShortURL 构建完整短链，自定义域名统一使用 https，默认域名使用 baseURL
+func (l *Link) ShortURL(baseURL string) string {
+	if l.Domain != "" {
+		return fmt.Sprintf("https://%s/%s", l.Domain, l.ShortCode)
+	}
+	return fmt.Sprintf("%s/%s", strings.TrimSuffix(baseURL, "/"), l.ShortCode)
 }
@@ -4,35 +4,89 @@ import "time"
 
 // CreateShortRequest 创建短链请求
 type CreateShortRequest struct {
-	LongURL     string     `json:"long_url" binding:"required,url"`
-	CustomCode  *string    `json:"custom_code,omitempty" binding:"omitempty,alphanum,min=3,max=10"` // 使用指针类型，区分“未设置”和“设置”，指针为 nil，表示客户端没有提供该字段
-	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
-	Description *string    `json:"description,omitempty" binding:"omitempty,max=500"`
-	CreatedBy   *string    `json:"created_by,omitempty" binding:"omitempty,max=100"`
+	LongURL       string         `json:"long_url" binding:"required,url"`
+	CustomCode    *string        `json:"custom_code,omitempty" binding:"omitempty,alphanum,min=3,max=10"` // 使用指针类型，区分“未设置”和“设置”，指针为 nil，表示客户端没有提供该字段
+	Domain        *string        `json:"domain,omitempty" binding:"omitempty,max=253"`                    // 自定义域名，为空使用默认域名
+	ActivateAt    *time.Time     `json:"activate_at,omitempty"`                                           // 生效时间，之前访问返回"尚未生效"
+	ExpiresAt     *time.Time     `json:"expires_at,omitempty"`
+	Description   *string        `json:"description,omitempty" binding:"omitempty,max=500"`
+	FolderID      *string        `json:"folder_id,omitempty"`
+	Password      *string        `json:"password,omitempty" binding:"omitempty,min=4,max=72"` // 访问密码，bcrypt 最多支持72字节
+	TagIDs        []string       `json:"tag_ids,omitempty" binding:"omitempty,max=20,dive,required"`
+	PixelIDs      []string       `json:"pixel_ids,omitempty" binding:"omitempty,max=10,dive,required"`                              // 重定向像素，跳转前在中间页触发
+	MaxClicks     *int64         `json:"max_clicks,omitempty" binding:"omitempty,min=1"`                                            // 最大访问次数，达到后链接失效
+	OneTime       *bool          `json:"one_time,omitempty"`                                                                        // 阅后即焚，等同于 max_clicks=1
+	Rules         []RedirectRule `json:"rules,omitempty" binding:"omitempty,max=20,dive"`                                           // 条件跳转规则，按顺序匹配
+	Variants      []LinkVariant  `json:"variants,omitempty" binding:"omitempty,min=2,max=10,dive"`                                  // A/B 测试分组
+	DeepLink      *DeepLink      `json:"deep_link,omitempty"`                                                                       // 移动端深度链接
+	OpenGraph     *OpenGraph     `json:"open_graph,omitempty"`                                                                      // 分享卡片覆盖值
+	IPAccess      *IPAccess      `json:"ip_access,omitempty"`                                                                       // IP访问控制列表
+	ForwardQuery  *bool          `json:"forward_query,omitempty"`                                                                   // 跳转时转发访问地址的查询参数
+	QueryConflict *string        `json:"query_conflict,omitempty" binding:"omitempty,oneof=destination incoming append"`            // 参数同名时的处理方式，默认 destination
+	ForwardPath   *bool          `json:"forward_path,omitempty"`                                                                    // 将短码之后的路径追加到目标地址
+	RedirectType  *string        `json:"redirect_type,omitempty" binding:"omitempty,oneof=301 302 307 308 meta_refresh javascript"` // 跳转方式，默认 302
+	SignedOnly    *bool          `json:"signed_only,omitempty"`                                                                     // 只允许通过签名地址访问
+	// 复用当前用户已有的相同长链接的有效短链，为空时使用配置默认值；指定 custom_code 时不生效
+	ReuseExisting *bool `json:"reuse_existing,omitempty"`
 }
 
 // BatchCreateRequest 批量创建短链请求
 type BatchCreateRequest struct {
-	URLs []BatchURLItem `json:"urls" binding:"required,min=1,max=100"`
+	URLs          []BatchURLItem `json:"urls" binding:"required,min=1,max=100"`
+	ReuseExisting *bool          `json:"reuse_existing,omitempty"` // 对所有条目生效
 }
 
 type BatchURLItem struct {
 	LongURL    string  `json:"long_url" binding:"required,url"`
 	CustomCode *string `json:"custom_code,omitempty" binding:"omitempty,alphanum,min=3,max=10"`
+	Domain     *string `json:"domain,omitempty" binding:"omitempty,max=253"`
 }
 
 // UpdateLinkRequest 更新链接请求
 type UpdateLinkRequest struct {
-	LongURL     *string    `json:"long_url" binding:"required,url" binding:"omitempty,url"` // 使用指针类型，区分“未设置”和“设置”
-	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
-	Status      *string    `json:"status,omitempty" binding:"omitempty,oneof=active disabled"`
-	Description *string    `json:"description,omitempty" binding:"omitempty,max=500"`
+	LongURL       *string         `json:"long_url" binding:"required,url" binding:"omitempty,url"` // 使用指针类型，区分“未设置”和“设置”
+	ActivateAt    *time.Time      `json:"activate_at,omitempty"`                                   // 生效时间，之前访问返回"尚未生效"
+	ExpiresAt     *time.Time      `json:"expires_at,omitempty"`
+	Status        *string         `json:"status,omitempty" binding:"omitempty,oneof=active disabled flagged"` // flagged 仅管理员可设置或解除
+	Description   *string         `json:"description,omitempty" binding:"omitempty,max=500"`
+	FolderID      *string         `json:"folder_id,omitempty"`                            // 空字符串表示移出文件夹
+	Password      *string         `json:"password,omitempty" binding:"omitempty,max=72"`  // 空字符串表示取消密码
+	MaxClicks     *int64          `json:"max_clicks,omitempty" binding:"omitempty,min=0"` // 0表示取消访问次数限制
+	Rules         *[]RedirectRule `json:"rules,omitempty"`                                // 整体替换条件跳转规则，空数组表示清除
+	Variants      *[]LinkVariant  `json:"variants,omitempty"`                             // 整体替换 A/B 测试分组，空数组表示清除
+	DeepLink      *DeepLink       `json:"deep_link,omitempty"`                            // 整体替换深度链接配置，空对象表示清除
+	OpenGraph     *OpenGraph      `json:"open_graph,omitempty"`                           // 整体替换分享卡片覆盖值，空对象表示清除
+	IPAccess      *IPAccess       `json:"ip_access,omitempty"`                            // 整体替换IP访问控制列表，空对象表示清除
+	ForwardQuery  *bool           `json:"forward_query,omitempty"`
+	QueryConflict *string         `json:"query_conflict,omitempty" binding:"omitempty,oneof=destination incoming append"`
+	ForwardPath   *bool           `json:"forward_path,omitempty"`
+	RedirectType  *string         `json:"redirect_type,omitempty" binding:"omitempty,oneof=301 302 307 308 meta_refresh javascript"`
+	SignedOnly    *bool           `json:"signed_only,omitempty"`
+	PixelIDs      *[]string       `json:"pixel_ids,omitempty" binding:"omitempty,max=10,dive,required"` // 整体替换重定向像素，空数组表示清除
 }
 
-// ListLinksRequest 列表查询请求
+// SignLinkRequest 生成签名地址请求，IP 和邮箱最多绑定一个
+type SignLinkRequest struct {
+	TTL       int64   `json:"ttl" binding:"required,min=60"`                          // 有效期（秒），不超过配置的上限
+	BindIP    *string `json:"bind_ip,omitempty" binding:"omitempty,ip"`               // 只允许该IP访问
+	BindEmail *string `json:"bind_email,omitempty" binding:"omitempty,email,max=254"` // 访问者需填写该邮箱
+}
+
+// ListLinksRequest 列表查询请求，时间参数使用 RFC3339 格式
 type ListLinksRequest struct {
-	Page      int     `form:"page,default=1" binding:"omitempty,min=1"`
-	PageSize  int     `form:"page_size,default=10" binding:"omitempty,min=1,max=100"`
-	CreatedBy *string `form:"created_by,omitempty" binding:"omitempty,max=100"`
-	Status    *string `form:"status,omitempty" binding:"omitempty,oneof=active disabled expired"`
+	Page          int        `form:"page,default=1" binding:"omitempty,min=1"`
+	PageSize      int        `form:"page_size,default=10" binding:"omitempty,min=1,max=100"`
+	CreatedBy     *string    `form:"created_by,omitempty" binding:"omitempty,max=100"` // 仅管理员有效
+	Status        *string    `form:"status,omitempty" binding:"omitempty,oneof=active disabled expired flagged"`
+	Search        *string    `form:"q,omitempty" binding:"omitempty,max=200"` // 搜索短码、长链接和描述
+	CreatedAfter  *time.Time `form:"created_after,omitempty"`
+	CreatedBefore *time.Time `form:"created_before,omitempty"`
+	ExpiresAfter  *time.Time `form:"expires_after,omitempty"`
+	ExpiresBefore *time.Time `form:"expires_before,omitempty"`
+	NeverExpires  *bool      `form:"never_expires,omitempty"`
+	Tag           *string    `form:"tag,omitempty"`    // 标签ID
+	Folder        *string    `form:"folder,omitempty"` // 文件夹ID，包含子文件夹中的链接
+	Sort          string     `form:"sort,default=created_at" binding:"omitempty,oneof=created_at click_count expires_at"`
+	Order         string     `form:"order,default=desc" binding:"omitempty,oneof=asc desc"`
+	Cursor        string     `form:"cursor,omitempty" binding:"omitempty,max=512"` // 上一页返回的 next_cursor，设置后忽略 page
 }
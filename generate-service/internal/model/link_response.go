@@ -4,25 +4,62 @@ import "time"
 
 // CreateShortResponse 创建短链接响应
 type CreateShortResponse struct {
+	Domain    string     `json:"domain,omitempty"`
 	ShortURL  string     `json:"short_url"`
 	LongURL   string     `json:"long_url"`
 	ShortCode string     `json:"short_code"`
 	CreatedAt time.Time  `json:"created_at"`
 	ExpiresAt *time.Time `json:"expires_at,omitempty"`
 	QRCodeURL string     `json:"qr_code_url,omitempty"`
+	Reused    bool       `json:"reused,omitempty"` // 为 true 表示返回的是已存在的短链
 }
 
 // LinkInfoResponse 链接信息响应
 type LinkInfoResponse struct {
-	ShortCode    string     `json:"short_code"`
-	LongURL      string     `json:"long_url"`
-	CreatedAt    time.Time  `json:"created_at"`
-	UpdatedAt    time.Time  `json:"updated_at"`
-	ExpiresAt    *time.Time `json:"expires_at,omitempty"` // nil值永不过期
-	ClickCount   int64      `json:"click_count"`
-	LastAccessed *time.Time `json:"last_accessed,omitempty"` // nil值从未被访问
-	Status       string     `json:"status"`
-	Description  string     `json:"description,omitempty"`
+	WorkspaceID   string              `json:"workspace_id"`
+	Domain        string              `json:"domain,omitempty"`
+	ShortCode     string              `json:"short_code"`
+	ShortURL      string              `json:"short_url"`
+	LongURL       string              `json:"long_url"`
+	Template      bool                `json:"template,omitempty"` // long_url 为目标地址模板
+	CreatedAt     time.Time           `json:"created_at"`
+	UpdatedAt     time.Time           `json:"updated_at"`
+	ActivateAt    *time.Time          `json:"activate_at,omitempty"` // nil值立即生效
+	ExpiresAt     *time.Time          `json:"expires_at,omitempty"`  // nil值永不过期
+	ClickCount    int64               `json:"click_count"`
+	LastAccessed  *time.Time          `json:"last_accessed,omitempty"` // nil值从未被访问
+	Status        string              `json:"status"`
+	Description   string              `json:"description,omitempty"`
+	FolderID      string              `json:"folder_id,omitempty"`
+	Protected     bool                `json:"password_protected"`
+	MaxClicks     int64               `json:"max_clicks,omitempty"`
+	Rules         []RedirectRule      `json:"rules,omitempty"`
+	Variants      []LinkVariant       `json:"variants,omitempty"`
+	DeepLink      *DeepLink           `json:"deep_link,omitempty"`
+	OpenGraph     *OpenGraph          `json:"open_graph,omitempty"`
+	IPAccess      *IPAccess           `json:"ip_access,omitempty"`
+	ForwardQuery  bool                `json:"forward_query"`
+	QueryConflict string              `json:"query_conflict,omitempty"`
+	ForwardPath   bool                `json:"forward_path"`
+	RedirectType  string              `json:"redirect_type"`
+	SignedOnly    bool                `json:"signed_only"`
+	Tags          []LinkTagResponse   `json:"tags"`
+	Pixels        []LinkPixelResponse `json:"pixels"`
+	Version       uint                `json:"version"` // 每次修改递增，回滚时指定要恢复的版本
+}
+
+// LinkTagResponse 链接上的标签
+type LinkTagResponse struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color,omitempty"`
+}
+
+// LinkPixelResponse 链接上的重定向像素
+type LinkPixelResponse struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Provider string `json:"provider"`
 }
 
 // BatchCreateResponse 批量创建响应
@@ -35,6 +72,7 @@ type BatchResult struct {
 	LongURL   string `json:"long_url"`
 	ShortURL  string `json:"short_url"`
 	ShortCode string `json:"short_code"`
+	Reused    bool   `json:"reused,omitempty"`
 }
 
 type BatchFailed struct {
@@ -42,11 +80,37 @@ type BatchFailed struct {
 	Error   string `json:"error"`
 }
 
-// ListLinksResponse 链接列表响应
+// ListLinksResponse 链接列表响应，游标分页时不统计总数，Total、Page、Pages 为0
 type ListLinksResponse struct {
-	Links    []LinkInfoResponse `json:"links"`
-	Total    int64              `json:"total"`
-	Page     int                `json:"page"`
-	PageSize int                `json:"page_size"`
-	Pages    int                `json:"pages"`
+	Links      []LinkInfoResponse `json:"links"`
+	Total      int64              `json:"total"`
+	Page       int                `json:"page"`
+	PageSize   int                `json:"page_size"`
+	Pages      int                `json:"pages"`
+	HasMore    bool               `json:"has_more"`
+	NextCursor string             `json:"next_cursor,omitempty"` // 获取下一页时作为 cursor 参数传入
+}
+
+// SignedLinkResponse 签名地址，绑定值只参与签名，不出现在地址中
+type SignedLinkResponse struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Binding   string    `json:"binding,omitempty"` // ip / email
+}
+
+// LinkRevisionResponse 链接的一条修订记录
+type LinkRevisionResponse struct {
+	Version         uint          `json:"version"`
+	Action          string        `json:"action"`                     // create / update / rollback / delete
+	RestoredVersion *uint         `json:"restored_version,omitempty"` // 回滚时恢复的版本
+	Changes         []FieldChange `json:"changes"`
+	CreatedAt       time.Time     `json:"created_at"`
+	CreatedBy       string        `json:"created_by,omitempty"`
+}
+
+// LinkHistoryResponse 链接修订历史，按版本从新到旧排列
+type LinkHistoryResponse struct {
+	ShortURL  string                 `json:"short_url"`
+	Version   uint                   `json:"version"` // 当前版本
+	Revisions []LinkRevisionResponse `json:"revisions"`
 }
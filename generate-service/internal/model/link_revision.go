@@ -0,0 +1,63 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// 修订记录的操作类型
+const (
+	RevisionActionCreate   = "create"
+	RevisionActionUpdate   = "update"
+	RevisionActionRollback = "rollback"
+	RevisionActionDelete   = "delete"
+)
+
+// LinkSnapshot 链接可编辑字段的快照，回滚时整体恢复。标签不属于链接内容，不纳入快照
+type LinkSnapshot struct {
+	LongURL       string         `json:"long_url"`
+	Template      bool           `json:"template"`
+	PasswordHash  string         `json:"password_hash"` // 对外展示变更时只标明是否设置了密码
+	FolderID      uint64         `json:"folder_id"`
+	ActivateAt    *time.Time     `json:"activate_at"`
+	ExpiresAt     *time.Time     `json:"expires_at"`
+	MaxClicks     int64          `json:"max_clicks"`
+	Rules         []RedirectRule `json:"rules"`
+	Variants      []LinkVariant  `json:"variants"`
+	DeepLink      *DeepLink      `json:"deep_link"`
+	OpenGraph     *OpenGraph     `json:"open_graph"`
+	IPAccess      *IPAccess      `json:"ip_access"`
+	ForwardQuery  bool           `json:"forward_query"`
+	QueryConflict string         `json:"query_conflict"`
+	ForwardPath   bool           `json:"forward_path"`
+	RedirectType  string         `json:"redirect_type"`
+	SignedOnly    bool           `json:"signed_only"`
+	PixelIDs      []string       `json:"pixel_ids"`
+	Status        LinkStatus     `json:"status"`
+	Description   string         `json:"description"`
+}
+
+// FieldChange 单个字段变更前后的值
+type FieldChange struct {
+	Field  string          `json:"field"`
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+// LinkRevision 链接的不可变修订记录，每次变更生成一条。Version 为变更后的链接版本，
+// Snapshot 为变更后的完整状态，Changes 为相对上一版本的差异
+type LinkRevision struct {
+	LinkID          uint64        `gorm:"primaryKey;autoIncrement:false" json:"link_id"`
+	Version         uint          `gorm:"primaryKey;autoIncrement:false" json:"version"`
+	Action          string        `gorm:"size:20;not null" json:"action"`
+	RestoredVersion *uint         `json:"restored_version,omitempty"` // 回滚时恢复的版本
+	Snapshot        LinkSnapshot  `gorm:"type:json;serializer:json" json:"-"`
+	Changes         []FieldChange `gorm:"type:json;serializer:json" json:"changes,omitempty"`
+	CreatedAt       time.Time     `gorm:"autoCreateTime" json:"created_at"`
+	CreatedBy       string        `gorm:"size:100" json:"created_by,omitempty"`
+}
+
+// TableName 指定表名
+func (r *LinkRevision) TableName() string {
+	return "link_revisions"
+}
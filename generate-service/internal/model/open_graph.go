@@ -0,0 +1,14 @@
+package model
+
+// OpenGraph 社交平台分享卡片的覆盖值，爬虫抓取短链时优先使用，
+// 未设置的字段使用从目标页面抓取的元数据
+type OpenGraph struct {
+	Title       string `json:"title,omitempty" binding:"omitempty,max=200"`
+	Description string `json:"description,omitempty" binding:"omitempty,max=500"`
+	Image       string `json:"image,omitempty" binding:"omitempty,url,max=2048"` // 卡片图片地址
+}
+
+// IsEmpty 是否未设置任何覆盖值
+func (o *OpenGraph) IsEmpty() bool {
+	return o.Title == "" && o.Description == "" && o.Image == ""
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"regexp"
+	"time"
+)
+
+// 重定向像素支持的平台，跳转页只按平台生成固定的代码片段
+const (
+	PixelProviderFacebook = "facebook" // Meta Pixel，TrackingID 为数字 Pixel ID
+	PixelProviderGoogle   = "google"   // Google tag，TrackingID 如 G-XXXXXXX、AW-123456789
+	PixelProviderLinkedIn = "linkedin" // LinkedIn Insight Tag，TrackingID 为数字 Partner ID
+)
+
+var pixelTrackingIDPatterns = map[string]*regexp.Regexp{
+	PixelProviderFacebook: regexp.MustCompile(`^[0-9]{6,20}$`),
+	PixelProviderGoogle:   regexp.MustCompile(`^(G|AW|DC)-[A-Z0-9]{4,20}$`),
+	PixelProviderLinkedIn: regexp.MustCompile(`^[0-9]{3,20}$`),
+}
+
+// ValidPixelTrackingID 跟踪ID是否符合平台格式
+func ValidPixelTrackingID(provider, trackingID string) bool {
+	pattern, ok := pixelTrackingIDPatterns[provider]
+	return ok && pattern.MatchString(trackingID)
+}
+
+// Pixel 重定向像素，工作空间内共享，与链接为多对多关系。
+// 挂载像素的链接跳转时先展示触发像素的中间页
+type Pixel struct {
+	ID          uint64    `gorm:"primaryKey" json:"id"`
+	WorkspaceID uint64    `gorm:"not null;uniqueIndex:uk_workspace_pixel" json:"workspace_id"`
+	Name        string    `gorm:"size:100;not null;uniqueIndex:uk_workspace_pixel" json:"name"`
+	Provider    string    `gorm:"size:20;not null" json:"provider"`
+	TrackingID  string    `gorm:"size:64;not null" json:"tracking_id"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	CreatedBy   string    `gorm:"size:100" json:"created_by,omitempty"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	UpdatedBy   string    `gorm:"size:100" json:"updated_by,omitempty"`
+	DeleteFlag  string    `gorm:"size:1" json:"delete_flag,omitempty"`
+	Version     uint      `gorm:"default:0" json:"version"`
+}
+
+// TableName 指定表名
+func (p *Pixel) TableName() string {
+	return "pixels"
+}
+
+// LinkPixel 链接与像素的关联
+type LinkPixel struct {
+	LinkID    uint64    `gorm:"primaryKey" json:"link_id"`
+	PixelID   uint64    `gorm:"primaryKey;index" json:"pixel_id"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	CreatedBy string    `gorm:"size:100" json:"created_by,omitempty"`
+}
+
+// TableName 指定表名
+func (lp *LinkPixel) TableName() string {
+	return "link_pixels"
+}
+
+// RetargetingPixel 缓存消息中的像素快照，redirect-service 据此渲染跳转页
+type RetargetingPixel struct {
+	Provider   string `json:"provider"`
+	TrackingID string `json:"tracking_id"`
+}
@@ -0,0 +1,14 @@
+package model
+
+// CreatePixelRequest 创建重定向像素请求
+type CreatePixelRequest struct {
+	Name       string `json:"name" binding:"required,max=100"`
+	Provider   string `json:"provider" binding:"required,oneof=facebook google linkedin"`
+	TrackingID string `json:"tracking_id" binding:"required,max=64"`
+}
+
+// UpdatePixelRequest 更新重定向像素请求，平台不可修改
+type UpdatePixelRequest struct {
+	Name       *string `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	TrackingID *string `json:"tracking_id,omitempty" binding:"omitempty,min=1,max=64"`
+}
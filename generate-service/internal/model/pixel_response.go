@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// PixelResponse 重定向像素响应
+type PixelResponse struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Provider   string    `json:"provider"`
+	TrackingID string    `json:"tracking_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ListPixelsResponse 重定向像素列表响应
+type ListPixelsResponse struct {
+	Pixels []PixelResponse `json:"pixels"`
+}
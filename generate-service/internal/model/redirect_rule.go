@@ -0,0 +1,32 @@
+package model
+
+// 条件跳转规则可选的设备类型和操作系统
+var (
+	RuleDevices = []string{"mobile", "tablet", "desktop"}
+	RuleOS      = []string{"ios", "android", "windows", "macos", "linux"}
+)
+
+// RedirectRule 条件跳转规则，按顺序匹配，已设置的条件全部满足时跳转到 Destination
+type RedirectRule struct {
+	Countries   []string `json:"countries,omitempty" binding:"omitempty,max=50,dive,len=2,alpha"` // ISO 3166-1 alpha-2 国家代码，如 DE
+	Devices     []string `json:"devices,omitempty" binding:"omitempty,dive,oneof=mobile tablet desktop"`
+	OS          []string `json:"os,omitempty" binding:"omitempty,dive,oneof=ios android windows macos linux"`
+	Languages   []string `json:"languages,omitempty" binding:"omitempty,max=20,dive,min=2,max=35"` // 语言标签，如 de、en-US
+	TimeStart   string   `json:"time_start,omitempty" binding:"omitempty,datetime=15:04"`          // 每日生效时段起点，晚于终点表示跨零点
+	TimeEnd     string   `json:"time_end,omitempty" binding:"omitempty,datetime=15:04"`            // 每日生效时段终点（不含）
+	Timezone    string   `json:"timezone,omitempty" binding:"omitempty,max=64"`                    // IANA 时区，默认 UTC
+	Destination string   `json:"destination" binding:"required,url"`
+}
+
+// HasCondition 是否至少设置了一个匹配条件
+func (r *RedirectRule) HasCondition() bool {
+	return len(r.Countries) > 0 || len(r.Devices) > 0 || len(r.OS) > 0 ||
+		len(r.Languages) > 0 || r.TimeStart != ""
+}
+
+// LinkVariant A/B 测试分组，访问者按权重稳定分配到其中一个分组
+type LinkVariant struct {
+	ID          string `json:"id" binding:"required,alphanum,max=20"`
+	Destination string `json:"destination" binding:"required,url"`
+	Weight      int    `json:"weight" binding:"required,min=1,max=100"` // 百分比权重，所有分组之和为100
+}
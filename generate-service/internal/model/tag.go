@@ -0,0 +1,54 @@
+package model
+
+import "time"
+
+// Tag 标签，工作空间内共享，与链接为多对多关系
+type Tag struct {
+	ID          uint64    `gorm:"primaryKey" json:"id"`
+	WorkspaceID uint64    `gorm:"not null;uniqueIndex:uk_workspace_tag" json:"workspace_id"`
+	Name        string    `gorm:"size:50;not null;uniqueIndex:uk_workspace_tag" json:"name"`
+	Color       string    `gorm:"size:20" json:"color,omitempty"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	CreatedBy   string    `gorm:"size:100" json:"created_by,omitempty"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	UpdatedBy   string    `gorm:"size:100" json:"updated_by,omitempty"`
+	DeleteFlag  string    `gorm:"size:1" json:"delete_flag,omitempty"`
+	Version     uint      `gorm:"default:0" json:"version"`
+}
+
+// TableName 指定表名
+func (t *Tag) TableName() string {
+	return "tags"
+}
+
+// LinkTag 链接与标签的关联
+type LinkTag struct {
+	LinkID    uint64    `gorm:"primaryKey" json:"link_id"`
+	TagID     uint64    `gorm:"primaryKey;index" json:"tag_id"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	CreatedBy string    `gorm:"size:100" json:"created_by,omitempty"`
+}
+
+// TableName 指定表名
+func (lt *LinkTag) TableName() string {
+	return "link_tags"
+}
+
+// Folder 文件夹，ParentID 为0表示顶级文件夹
+type Folder struct {
+	ID          uint64    `gorm:"primaryKey" json:"id"`
+	WorkspaceID uint64    `gorm:"not null;uniqueIndex:uk_workspace_parent_name" json:"workspace_id"`
+	ParentID    uint64    `gorm:"not null;default:0;uniqueIndex:uk_workspace_parent_name" json:"parent_id"`
+	Name        string    `gorm:"size:100;not null;uniqueIndex:uk_workspace_parent_name" json:"name"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	CreatedBy   string    `gorm:"size:100" json:"created_by,omitempty"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	UpdatedBy   string    `gorm:"size:100" json:"updated_by,omitempty"`
+	DeleteFlag  string    `gorm:"size:1" json:"delete_flag,omitempty"`
+	Version     uint      `gorm:"default:0" json:"version"`
+}
+
+// TableName 指定表名
+func (f *Folder) TableName() string {
+	return "folders"
+}
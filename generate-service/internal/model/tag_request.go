@@ -0,0 +1,32 @@
+package model
+
+// CreateTagRequest 创建标签请求
+type CreateTagRequest struct {
+	Name  string `json:"name" binding:"required,max=50"`
+	Color string `json:"color,omitempty" binding:"omitempty,max=20"`
+}
+
+// UpdateTagRequest 更新标签请求
+type UpdateTagRequest struct {
+	Name  *string `json:"name,omitempty" binding:"omitempty,min=1,max=50"`
+	Color *string `json:"color,omitempty" binding:"omitempty,max=20"`
+}
+
+// BulkTagRequest 批量为链接添加或移除标签
+type BulkTagRequest struct {
+	ShortCodes []string `json:"short_codes" binding:"required,min=1,max=100,dive,required,max=10"`
+	Domain     *string  `json:"domain,omitempty" binding:"omitempty,max=253"` // 短码所属域名，为空使用默认域名
+	TagIDs     []string `json:"tag_ids" binding:"required,min=1,max=20,dive,required"`
+}
+
+// CreateFolderRequest 创建文件夹请求
+type CreateFolderRequest struct {
+	Name     string  `json:"name" binding:"required,max=100"`
+	ParentID *string `json:"parent_id,omitempty"` // 为空表示顶级文件夹
+}
+
+// UpdateFolderRequest 更新文件夹请求，parent_id 为空字符串表示移动到顶级
+type UpdateFolderRequest struct {
+	Name     *string `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	ParentID *string `json:"parent_id,omitempty"`
+}
@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// TagResponse 标签响应
+type TagResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ListTagsResponse 标签列表响应
+type ListTagsResponse struct {
+	Tags []TagResponse `json:"tags"`
+}
+
+// BulkTagResponse 批量打标签响应
+type BulkTagResponse struct {
+	Updated []string        `json:"updated"`
+	Failed  []BulkTagFailed `json:"failed,omitempty"`
+}
+
+type BulkTagFailed struct {
+	ShortCode string `json:"short_code"`
+	Error     string `json:"error"`
+}
+
+// FolderResponse 文件夹响应
+type FolderResponse struct {
+	ID        string    `json:"id"`
+	ParentID  string    `json:"parent_id,omitempty"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ListFoldersResponse 文件夹列表响应
+type ListFoldersResponse struct {
+	Folders []FolderResponse `json:"folders"`
+}
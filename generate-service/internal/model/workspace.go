@@ -0,0 +1,49 @@
+package model
+
+import "time"
+
+// WorkspaceSettings 工作空间设置
+type WorkspaceSettings struct {
+	DefaultExpireDays int       `json:"default_expire_days,omitempty"` // 新建链接默认有效天数，0表示永不过期
+	IPAccess          *IPAccess `json:"ip_access,omitempty"`           // 工作空间内所有链接的IP访问控制列表，与链接自身的列表同时生效
+}
+
+// Workspace 工作空间（租户），拥有链接、成员、配额和设置
+type Workspace struct {
+	ID          uint64            `gorm:"primaryKey" json:"id"`
+	Name        string            `gorm:"size:100;not null" json:"name"`
+	Slug        string            `gorm:"size:50;not null;uniqueIndex" json:"slug"`
+	MaxLinks    int64             `gorm:"default:0" json:"max_links"` // 链接数量上限，0表示不限制
+	Settings    WorkspaceSettings `gorm:"type:json;serializer:json" json:"settings"`
+	CreatedAt   time.Time         `gorm:"autoCreateTime" json:"created_at"`
+	CreatedBy   string            `gorm:"size:100" json:"created_by,omitempty"`
+	UpdatedAt   time.Time         `gorm:"autoUpdateTime" json:"updated_at"`
+	UpdatedBy   string            `gorm:"size:100" json:"updated_by,omitempty"`
+	Description string            `gorm:"size:500" json:"description,omitempty"`
+	DeleteFlag  string            `gorm:"size:1" json:"delete_flag,omitempty"`
+	Version     uint              `gorm:"default:0" json:"version"`
+}
+
+// TableName 指定表名
+func (w *Workspace) TableName() string {
+	return "workspaces"
+}
+
+// WorkspaceMember 工作空间成员，角色仅在所属工作空间内生效
+type WorkspaceMember struct {
+	ID          uint64    `gorm:"primaryKey" json:"id"`
+	WorkspaceID uint64    `gorm:"not null;uniqueIndex:uk_workspace_user" json:"workspace_id"`
+	UserID      string    `gorm:"size:100;not null;uniqueIndex:uk_workspace_user;index" json:"user_id"`
+	Role        string    `gorm:"size:20;not null" json:"role"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	CreatedBy   string    `gorm:"size:100" json:"created_by,omitempty"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	UpdatedBy   string    `gorm:"size:100" json:"updated_by,omitempty"`
+	DeleteFlag  string    `gorm:"size:1" json:"delete_flag,omitempty"`
+	Version     uint      `gorm:"default:0" json:"version"`
+}
+
+// TableName 指定表名
+func (m *WorkspaceMember) TableName() string {
+	return "workspace_members"
+}
@@ -0,0 +1,16 @@
+package model
+
+// CreateWorkspaceRequest 创建工作空间请求
+type CreateWorkspaceRequest struct {
+	Name        string             `json:"name" binding:"required,max=100"`
+	Slug        string             `json:"slug" binding:"required,min=2,max=50,alphanum"`
+	MaxLinks    *int64             `json:"max_links,omitempty" binding:"omitempty,min=0"`
+	Settings    *WorkspaceSettings `json:"settings,omitempty"`
+	Description *string            `json:"description,omitempty" binding:"omitempty,max=500"`
+}
+
+// AddMemberRequest 添加工作空间成员请求
+type AddMemberRequest struct {
+	UserID string `json:"user_id" binding:"required,max=100"`
+	Role   string `json:"role" binding:"required,oneof=viewer editor admin"`
+}
@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// WorkspaceResponse 工作空间信息响应
+type WorkspaceResponse struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Slug        string            `json:"slug"`
+	MaxLinks    int64             `json:"max_links"`
+	Settings    WorkspaceSettings `json:"settings"`
+	Role        string            `json:"role,omitempty"` // 当前用户在该工作空间的角色
+	CreatedAt   time.Time         `json:"created_at"`
+	Description string            `json:"description,omitempty"`
+}
+
+// ListWorkspacesResponse 工作空间列表响应
+type ListWorkspacesResponse struct {
+	Workspaces []WorkspaceResponse `json:"workspaces"`
+}
+
+// MemberResponse 工作空间成员响应
+type MemberResponse struct {
+	UserID    string    `json:"user_id"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ListMembersResponse 工作空间成员列表响应
+type ListMembersResponse struct {
+	Members []MemberResponse `json:"members"`
+}
@@ -5,11 +5,52 @@ import "fmt"
 // 定义错误类型
 var (
 	ErrLinkNotFound     = NewBusinessError("link not found")
+	ErrVersionConflict  = NewBusinessError("link was modified concurrently")
+	ErrRevisionNotFound = NewBusinessError("link revision not found")
 	ErrLinkExpired      = NewBusinessError("link expired")
 	ErrLinkDisabled     = NewBusinessError("link disabled")
 	ErrInvalidURL       = NewBusinessError("invalid URL")
 	ErrShortCodeExists  = NewBusinessError("short code already exists")
 	ErrInvalidShortCode = NewBusinessError("invalid short code")
+	ErrUnauthorized     = NewBusinessError("unauthorized")
+	ErrForbidden        = NewBusinessError("forbidden")
+	ErrAPIKeyNotFound   = NewBusinessError("api key not found")
+	ErrInvalidCursor    = NewBusinessError("invalid cursor")
+	ErrInvalidSchedule  = NewBusinessError("activate_at must be before expires_at")
+	ErrInvalidRule      = NewBusinessError("invalid redirect rule")
+	ErrInvalidVariants  = NewBusinessError("invalid link variants")
+	ErrInvalidDeepLink  = NewBusinessError("invalid deep link")
+	ErrInvalidTemplate  = NewBusinessError("invalid destination template")
+	ErrInvalidIPAccess  = NewBusinessError("invalid ip access list")
+	ErrInvalidPatch     = NewBusinessError("invalid merge patch")
+
+	ErrWorkspaceRequired   = NewBusinessError("workspace required")
+	ErrWorkspaceNotFound   = NewBusinessError("workspace not found")
+	ErrWorkspaceSlugExists = NewBusinessError("workspace slug already exists")
+	ErrWorkspaceQuota      = NewBusinessError("workspace link quota exceeded")
+	ErrMemberNotFound      = NewBusinessError("workspace member not found")
+	ErrMemberExists        = NewBusinessError("workspace member already exists")
+
+	ErrDomainNotFound   = NewBusinessError("domain not found")
+	ErrDomainExists     = NewBusinessError("domain already registered")
+	ErrInvalidDomain    = NewBusinessError("invalid domain")
+	ErrDomainInUse      = NewBusinessError("domain still has links")
+	ErrDomainUnverified = NewBusinessError("domain ownership not verified")
+	ErrInvalidAppLinks  = NewBusinessError("invalid app links")
+
+	ErrTagNotFound         = NewBusinessError("tag not found")
+	ErrTagExists           = NewBusinessError("tag already exists")
+	ErrFolderNotFound      = NewBusinessError("folder not found")
+	ErrFolderExists        = NewBusinessError("folder already exists")
+	ErrFolderNotEmpty      = NewBusinessError("folder is not empty")
+	ErrInvalidFolderParent = NewBusinessError("invalid parent folder")
+
+	ErrPixelNotFound = NewBusinessError("pixel not found")
+	ErrPixelExists   = NewBusinessError("pixel already exists")
+	ErrInvalidPixel  = NewBusinessError("invalid pixel")
+
+	ErrInvalidSignedLink  = NewBusinessError("invalid signed link request")
+	ErrSigningUnavailable = NewBusinessError("link signing is not configured")
 )
 
 type BusinessError struct {
@@ -0,0 +1,90 @@
+package apikey
+
+import (
+	"context"
+	"generate-service/internal/model"
+	"generate-service/internal/pkg/errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type MySQLRepository struct {
+	db *gorm.DB
+}
+
+func (r *MySQLRepository) Create(ctx context.Context, key *model.APIKey) error {
+	result := r.db.WithContext(ctx).Create(key)
+	if result.Error != nil {
+		return &errors.RepositoryError{Operation: "CreateAPIKey", Err: result.Error}
+	}
+	return nil
+}
+
+func (r *MySQLRepository) FindByHash(ctx context.Context, keyHash string) (*model.APIKey, error) {
+	var key model.APIKey
+	result := r.db.WithContext(ctx).Where("key_hash = ? and delete_flag = 'N'", keyHash).First(&key)
+	if result.Error != nil {
+		if result.Error.Error() == gorm.ErrRecordNotFound.Error() {
+			return nil, errors.ErrAPIKeyNotFound
+		}
+		return nil, &errors.RepositoryError{Operation: "FindAPIKeyByHash", Err: result.Error}
+	}
+	return &key, nil
+}
+
+func (r *MySQLRepository) FindByID(ctx context.Context, id uint64) (*model.APIKey, error) {
+	var key model.APIKey
+	result := r.db.WithContext(ctx).Where("id = ? and delete_flag = 'N'", id).First(&key)
+	if result.Error != nil {
+		if result.Error.Error() == gorm.ErrRecordNotFound.Error() {
+			return nil, errors.ErrAPIKeyNotFound
+		}
+		return nil, &errors.RepositoryError{Operation: "FindAPIKeyByID", Err: result.Error}
+	}
+	return &key, nil
+}
+
+func (r *MySQLRepository) ListByOwner(ctx context.Context, ownerID string) ([]model.APIKey, error) {
+	var keys []model.APIKey
+	result := r.db.WithContext(ctx).
+		Where("owner_id = ? and delete_flag = 'N'", ownerID).
+		Order("created_at DESC").
+		Find(&keys)
+	if result.Error != nil {
+		return nil, &errors.RepositoryError{Operation: "ListAPIKeys", Err: result.Error}
+	}
+	return keys, nil
+}
+
+func (r *MySQLRepository) Revoke(ctx context.Context, key *model.APIKey) error {
+	result := r.db.WithContext(ctx).Model(&model.APIKey{}).
+		Where("id = ? AND version = ?", key.ID, key.Version).
+		Updates(map[string]interface{}{
+			"status":     model.APIKeyStatusRevoked,
+			"version":    key.Version + 1,
+			"updated_at": time.Now(),
+			"updated_by": key.UpdatedBy,
+		})
+	if result.Error != nil {
+		return &errors.RepositoryError{Operation: "RevokeAPIKey", Err: result.Error}
+	}
+	if result.RowsAffected == 0 {
+		return errors.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+func (r *MySQLRepository) TouchLastUsed(ctx context.Context, id uint64) error {
+	result := r.db.WithContext(ctx).Model(&model.APIKey{}).
+		Where("id = ?", id).
+		UpdateColumn("last_used_at", time.Now())
+	if result.Error != nil {
+		return &errors.RepositoryError{Operation: "TouchAPIKeyLastUsed", Err: result.Error}
+	}
+	return nil
+}
+
+func NewMySQLRepository(db *gorm.DB) *MySQLRepository {
+	return &MySQLRepository{db: db}
+}
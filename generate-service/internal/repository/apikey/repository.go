@@ -0,0 +1,25 @@
+package apikey
+
+import (
+	"context"
+	"generate-service/internal/model"
+)
+
+// Repository API密钥数据访问接口
+type Repository interface {
+	// Create 创建密钥
+	Create(ctx context.Context, key *model.APIKey) error
+
+	// FindByHash 根据密钥摘要查询
+	FindByHash(ctx context.Context, keyHash string) (*model.APIKey, error)
+	FindByID(ctx context.Context, id uint64) (*model.APIKey, error)
+
+	// ListByOwner 查询用户的所有密钥
+	ListByOwner(ctx context.Context, ownerID string) ([]model.APIKey, error)
+
+	// Revoke 吊销密钥
+	Revoke(ctx context.Context, key *model.APIKey) error
+
+	// TouchLastUsed 更新最后使用时间
+	TouchLastUsed(ctx context.Context, id uint64) error
+}
@@ -0,0 +1,168 @@
+package domain
+
+import (
+	"context"
+	"generate-service/internal/model"
+	"generate-service/internal/pkg/errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MySQLRepository struct {
+	db *gorm.DB
+}
+
+func (r *MySQLRepository) Create(ctx context.Context, domain *model.Domain) error {
+	result := r.db.WithContext(ctx).Create(domain)
+	if result.Error != nil {
+		if strings.Contains(result.Error.Error(), "Duplicate entry") {
+			return errors.ErrDomainExists
+		}
+		return &errors.RepositoryError{Operation: "CreateDomain", Err: result.Error}
+	}
+	return nil
+}
+
+func (r *MySQLRepository) FindByID(ctx context.Context, id uint64) (*model.Domain, error) {
+	var domain model.Domain
+	result := r.db.WithContext(ctx).Where("id = ?", id).First(&domain)
+	if result.Error != nil {
+		if result.Error.Error() == gorm.ErrRecordNotFound.Error() {
+			return nil, errors.ErrDomainNotFound
+		}
+		return nil, &errors.RepositoryError{Operation: "FindDomainByID", Err: result.Error}
+	}
+	return &domain, nil
+}
+
+// FindByHostname 按 verified_hostname 查询，未通过所有权验证的域名视为不存在
+func (r *MySQLRepository) FindByHostname(ctx context.Context, hostname string) (*model.Domain, error) {
+	var domain model.Domain
+	result := r.db.WithContext(ctx).Where("verified_hostname = ?", hostname).First(&domain)
+	if result.Error != nil {
+		if result.Error.Error() == gorm.ErrRecordNotFound.Error() {
+			return nil, errors.ErrDomainNotFound
+		}
+		return nil, &errors.RepositoryError{Operation: "FindDomainByHostname", Err: result.Error}
+	}
+	return &domain, nil
+}
+
+func (r *MySQLRepository) FindByWorkspaceHostname(ctx context.Context, workspaceID uint64, hostname string) (*model.Domain, error) {
+	var domain model.Domain
+	result := r.db.WithContext(ctx).
+		Where("workspace_id = ? AND hostname = ?", workspaceID, hostname).
+		First(&domain)
+	if result.Error != nil {
+		if result.Error.Error() == gorm.ErrRecordNotFound.Error() {
+			return nil, errors.ErrDomainNotFound
+		}
+		return nil, &errors.RepositoryError{Operation: "FindDomainByWorkspaceHostname", Err: result.Error}
+	}
+	return &domain, nil
+}
+
+func (r *MySQLRepository) ListByWorkspace(ctx context.Context, workspaceID uint64) ([]model.Domain, error) {
+	var domains []model.Domain
+	result := r.db.WithContext(ctx).
+		Where("workspace_id = ?", workspaceID).
+		Order("created_at DESC").
+		Find(&domains)
+	if result.Error != nil {
+		return nil, &errors.RepositoryError{Operation: "ListDomains", Err: result.Error}
+	}
+	return domains, nil
+}
+
+// Update 更新关联的 App，使用结构体更新以便 JSON 列经过序列化
+func (r *MySQLRepository) Update(ctx context.Context, domain *model.Domain) error {
+	result := r.db.WithContext(ctx).Model(&model.Domain{}).
+		Where("id = ? AND version = ?", domain.ID, domain.Version).
+		Select("apple_app_ids", "android_apps", "updated_by", "version").
+		Updates(&model.Domain{
+			AppleAppIDs: domain.AppleAppIDs,
+			AndroidApps: domain.AndroidApps,
+			UpdatedBy:   domain.UpdatedBy,
+			Version:     domain.Version + 1,
+		})
+	if result.Error != nil {
+		return &errors.RepositoryError{Operation: "UpdateDomain", Err: result.Error}
+	}
+	if result.RowsAffected == 0 {
+		return errors.ErrDomainNotFound
+	}
+	domain.Version++
+	return nil
+}
+
+// Verify 标记域名已通过所有权验证，主机名已被其他工作空间验证时返回 ErrDomainExists
+func (r *MySQLRepository) Verify(ctx context.Context, domain *model.Domain) error {
+	now := time.Now()
+	result := r.db.WithContext(ctx).Model(&model.Domain{}).
+		Where("id = ? AND version = ?", domain.ID, domain.Version).
+		Updates(map[string]interface{}{
+			"verified_hostname": domain.Hostname,
+			"verified_at":       now,
+			"updated_by":        domain.UpdatedBy,
+			"version":           domain.Version + 1,
+		})
+	if result.Error != nil {
+		if strings.Contains(result.Error.Error(), "Duplicate entry") {
+			return errors.ErrDomainExists
+		}
+		return &errors.RepositoryError{Operation: "VerifyDomain", Err: result.Error}
+	}
+	if result.RowsAffected == 0 {
+		return errors.ErrDomainNotFound
+	}
+	domain.VerifiedHostname = &domain.Hostname
+	domain.VerifiedAt = &now
+	domain.Version++
+	return nil
+}
+
+// Delete 物理删除，释放主机名以便重新注册（唯一索引不含 delete_flag）。
+// 锁定域名行后统计链接数，与创建链接时对域名行加的共享锁互斥，删除期间不会有新链接写入该域名
+func (r *MySQLRepository) Delete(ctx context.Context, domain *model.Domain) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var locked model.Domain
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Take(&locked, domain.ID).Error; err != nil {
+			return err
+		}
+		// 只有验证通过的域名才能创建链接，未验证的域名无需统计
+		if domain.VerifiedHostname != nil {
+			var count int64
+			if err := tx.Model(&model.Link{}).Where("domain = ? and delete_flag = 'N'", *domain.VerifiedHostname).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return errors.ErrDomainInUse
+			}
+		}
+		result := tx.Where("id = ? AND version = ?", domain.ID, domain.Version).Delete(&model.Domain{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.ErrDomainNotFound
+		}
+		return nil
+	})
+	if err == errors.ErrDomainInUse || err == errors.ErrDomainNotFound {
+		return err
+	}
+	if err != nil {
+		if err.Error() == gorm.ErrRecordNotFound.Error() {
+			return errors.ErrDomainNotFound
+		}
+		return &errors.RepositoryError{Operation: "DeleteDomain", Err: err}
+	}
+	return nil
+}
+
+func NewMySQLRepository(db *gorm.DB) *MySQLRepository {
+	return &MySQLRepository{db: db}
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"context"
+	"generate-service/internal/model"
+)
+
+// Repository 自定义域名数据访问接口
+type Repository interface {
+	// Create 注册域名
+	Create(ctx context.Context, domain *model.Domain) error
+
+	// FindByID 查询域名
+	FindByID(ctx context.Context, id uint64) (*model.Domain, error)
+
+	// FindByHostname 查询已通过所有权验证的域名
+	FindByHostname(ctx context.Context, hostname string) (*model.Domain, error)
+
+	// FindByWorkspaceHostname 查询工作空间注册的域名，不区分是否已验证
+	FindByWorkspaceHostname(ctx context.Context, workspaceID uint64, hostname string) (*model.Domain, error)
+
+	// ListByWorkspace 查询工作空间的所有域名
+	ListByWorkspace(ctx context.Context, workspaceID uint64) ([]model.Domain, error)
+
+	// Update 更新域名，基于版本号乐观锁
+	Update(ctx context.Context, domain *model.Domain) error
+
+	// Verify 标记域名已通过所有权验证
+	Verify(ctx context.Context, domain *model.Domain) error
+
+	// Delete 删除域名，域名下仍有链接时返回 ErrDomainInUse
+	Delete(ctx context.Context, domain *model.Domain) error
+}
@@ -0,0 +1,147 @@
+package folder
+
+import (
+	"context"
+	"generate-service/internal/model"
+	"generate-service/internal/pkg/errors"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MySQLRepository struct {
+	db *gorm.DB
+}
+
+func (r *MySQLRepository) Create(ctx context.Context, folder *model.Folder) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockFolder(tx, folder.ParentID, "SHARE"); err != nil {
+			return err
+		}
+		return tx.Create(folder).Error
+	})
+	if err == errors.ErrFolderNotFound {
+		return err
+	}
+	if err != nil {
+		if strings.Contains(err.Error(), "Duplicate entry") {
+			return errors.ErrFolderExists
+		}
+		return &errors.RepositoryError{Operation: "CreateFolder", Err: err}
+	}
+	return nil
+}
+
+// 锁定文件夹行，id 为 0 表示根目录无需锁定。
+// 创建链接和子文件夹时加共享锁，删除文件夹时加排他锁，二者互斥
+func lockFolder(tx *gorm.DB, id uint64, strength string) error {
+	if id == 0 {
+		return nil
+	}
+	var folder model.Folder
+	err := tx.Clauses(clause.Locking{Strength: strength}).Select("id").Take(&folder, id).Error
+	if err != nil {
+		if err.Error() == gorm.ErrRecordNotFound.Error() {
+			return errors.ErrFolderNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *MySQLRepository) FindByID(ctx context.Context, id uint64) (*model.Folder, error) {
+	var folder model.Folder
+	result := r.db.WithContext(ctx).Where("id = ?", id).First(&folder)
+	if result.Error != nil {
+		if result.Error.Error() == gorm.ErrRecordNotFound.Error() {
+			return nil, errors.ErrFolderNotFound
+		}
+		return nil, &errors.RepositoryError{Operation: "FindFolderByID", Err: result.Error}
+	}
+	return &folder, nil
+}
+
+func (r *MySQLRepository) ListByWorkspace(ctx context.Context, workspaceID uint64) ([]model.Folder, error) {
+	var folders []model.Folder
+	result := r.db.WithContext(ctx).
+		Where("workspace_id = ?", workspaceID).
+		Order("parent_id ASC, name ASC").
+		Find(&folders)
+	if result.Error != nil {
+		return nil, &errors.RepositoryError{Operation: "ListFolders", Err: result.Error}
+	}
+	return folders, nil
+}
+
+func (r *MySQLRepository) Update(ctx context.Context, folder *model.Folder) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockFolder(tx, folder.ParentID, "SHARE"); err != nil {
+			return err
+		}
+		result := tx.Model(&model.Folder{}).
+			Where("id = ? AND version = ?", folder.ID, folder.Version).
+			Updates(map[string]interface{}{
+				"name":       folder.Name,
+				"parent_id":  folder.ParentID,
+				"version":    folder.Version + 1,
+				"updated_by": folder.UpdatedBy,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.ErrFolderNotFound
+		}
+		return nil
+	})
+	if err == errors.ErrFolderNotFound {
+		return err
+	}
+	if err != nil {
+		if strings.Contains(err.Error(), "Duplicate entry") {
+			return errors.ErrFolderExists
+		}
+		return &errors.RepositoryError{Operation: "UpdateFolder", Err: err}
+	}
+	folder.Version++
+	return nil
+}
+
+// Delete 物理删除，锁定文件夹行后统计子文件夹和链接，文件夹不为空时返回 ErrFolderNotEmpty
+func (r *MySQLRepository) Delete(ctx context.Context, folder *model.Folder) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockFolder(tx, folder.ID, "UPDATE"); err != nil {
+			return err
+		}
+		var children, links int64
+		if err := tx.Model(&model.Folder{}).Where("parent_id = ?", folder.ID).Count(&children).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.Link{}).Where("folder_id = ? and delete_flag = 'N'", folder.ID).Count(&links).Error; err != nil {
+			return err
+		}
+		if children > 0 || links > 0 {
+			return errors.ErrFolderNotEmpty
+		}
+		result := tx.Where("id = ? AND version = ?", folder.ID, folder.Version).Delete(&model.Folder{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.ErrFolderNotFound
+		}
+		return nil
+	})
+	if err == errors.ErrFolderNotEmpty || err == errors.ErrFolderNotFound {
+		return err
+	}
+	if err != nil {
+		return &errors.RepositoryError{Operation: "DeleteFolder", Err: err}
+	}
+	return nil
+}
+
+func NewMySQLRepository(db *gorm.DB) *MySQLRepository {
+	return &MySQLRepository{db: db}
+}
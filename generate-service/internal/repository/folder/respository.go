@@ -0,0 +1,24 @@
+package folder
+
+import (
+	"context"
+	"generate-service/internal/model"
+)
+
+// Repository 文件夹数据访问接口
+type Repository interface {
+	// Create 创建文件夹，事务内锁定父文件夹，父文件夹已被删除时返回 ErrFolderNotFound
+	Create(ctx context.Context, folder *model.Folder) error
+
+	// FindByID 查询文件夹
+	FindByID(ctx context.Context, id uint64) (*model.Folder, error)
+
+	// ListByWorkspace 查询工作空间的所有文件夹
+	ListByWorkspace(ctx context.Context, workspaceID uint64) ([]model.Folder, error)
+
+	// Update 更新文件夹名称和父文件夹
+	Update(ctx context.Context, folder *model.Folder) error
+
+	// Delete 删除文件夹，仍有链接或子文件夹时返回 ErrFolderNotEmpty
+	Delete(ctx context.Context, folder *model.Folder) error
+}
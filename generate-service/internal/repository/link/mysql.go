@@ -8,26 +8,113 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type MySQLRepository struct {
 	db *gorm.DB
 }
 
-func (r *MySQLRepository) Create(ctx context.Context, link *model.Link) error {
-	result := r.db.WithContext(ctx).Create(link)
-	if result.Error != nil {
-		if strings.Contains(result.Error.Error(), "Duplicate entry") {
+func (r *MySQLRepository) Create(ctx context.Context, link *model.Link, rev *model.LinkRevision, opts CreateOptions) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if opts.MaxLinks > 0 {
+			if err := checkQuota(tx, link.WorkspaceID, opts.MaxLinks); err != nil {
+				return err
+			}
+		}
+		if err := lockReferences(tx, link); err != nil {
+			return err
+		}
+		if err := tx.Create(link).Error; err != nil {
+			return err
+		}
+		if len(opts.TagIDs) > 0 {
+			rows := make([]model.LinkTag, len(opts.TagIDs))
+			for i, tagID := range opts.TagIDs {
+				rows[i] = model.LinkTag{LinkID: link.ID, TagID: tagID, CreatedBy: link.CreatedBy}
+			}
+			if err := tx.Create(&rows).Error; err != nil {
+				return err
+			}
+		}
+		if err := setPixels(tx, link.ID, opts.PixelIDs, link.CreatedBy); err != nil {
+			return err
+		}
+		rev.LinkID = link.ID
+		rev.Version = link.Version
+		return tx.Create(rev).Error
+	})
+	if err == errors.ErrWorkspaceQuota || err == errors.ErrDomainNotFound || err == errors.ErrFolderNotFound {
+		return err
+	}
+	if err != nil {
+		if strings.Contains(err.Error(), "Duplicate entry") {
 			return errors.ErrShortCodeExists
 		}
-		return &errors.RepositoryError{Operation: "Create", Err: result.Error}
+		return &errors.RepositoryError{Operation: "Create", Err: err}
 	}
 	return nil
 }
 
-func (r *MySQLRepository) FindByShortCode(ctx context.Context, shortCode string) (*model.Link, error) {
+// 写入链接挂载的像素
+func setPixels(tx *gorm.DB, linkID uint64, pixelIDs []uint64, createdBy string) error {
+	if len(pixelIDs) == 0 {
+		return nil
+	}
+	rows := make([]model.LinkPixel, len(pixelIDs))
+	for i, pixelID := range pixelIDs {
+		rows[i] = model.LinkPixel{LinkID: linkID, PixelID: pixelID, CreatedBy: createdBy}
+	}
+	return tx.Create(&rows).Error
+}
+
+// 以共享锁锁定链接引用的域名和文件夹，与删除域名、文件夹时的排他锁互斥，
+// 二者已被删除时返回 ErrDomainNotFound 或 ErrFolderNotFound
+func lockReferences(tx *gorm.DB, link *model.Link) error {
+	if link.Domain != "" {
+		var domain model.Domain
+		err := tx.Clauses(clause.Locking{Strength: "SHARE"}).Select("id").
+			Where("verified_hostname = ?", link.Domain).
+			Take(&domain).Error
+		if err != nil {
+			if err.Error() == gorm.ErrRecordNotFound.Error() {
+				return errors.ErrDomainNotFound
+			}
+			return err
+		}
+	}
+	if link.FolderID != 0 {
+		var folder model.Folder
+		err := tx.Clauses(clause.Locking{Strength: "SHARE"}).Select("id").Take(&folder, link.FolderID).Error
+		if err != nil {
+			if err.Error() == gorm.ErrRecordNotFound.Error() {
+				return errors.ErrFolderNotFound
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// 锁定工作空间行后统计链接数，同一工作空间的创建请求依次检查，不会同时越过上限
+func checkQuota(tx *gorm.DB, workspaceID uint64, maxLinks int64) error {
+	var ws model.Workspace
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Take(&ws, workspaceID).Error; err != nil {
+		return err
+	}
+	var count int64
+	if err := tx.Model(&model.Link{}).Where("workspace_id = ? and delete_flag = 'N'", workspaceID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count >= maxLinks {
+		return errors.ErrWorkspaceQuota
+	}
+	return nil
+}
+
+func (r *MySQLRepository) FindByShortCode(ctx context.Context, domain, shortCode string) (*model.Link, error) {
 	var link model.Link
-	result := r.db.WithContext(ctx).Where("short_code=? and delete_flag = 'N'", shortCode).First(&link)
+	result := r.db.WithContext(ctx).Where("domain=? and short_code=? and delete_flag = 'N'", domain, shortCode).First(&link)
 	if result.Error != nil {
 		if result.Error.Error() == gorm.ErrRecordNotFound.Error() {
 			return nil, errors.ErrLinkNotFound
@@ -37,32 +124,109 @@ func (r *MySQLRepository) FindByShortCode(ctx context.Context, shortCode string)
 	return &link, nil
 }
 
-func (r *MySQLRepository) FindByLongURL(ctx context.Context, longURL string) (*model.Link, error) {
+func (r *MySQLRepository) FindByURLHash(ctx context.Context, workspaceID uint64, createdBy, domain, urlHash string) (*model.Link, error) {
 	var link model.Link
-	result := r.db.WithContext(ctx).Where("long_url=? and delete_flag = 'N'", longURL).First(&link)
+	result := r.db.WithContext(ctx).
+		Where("workspace_id = ? and created_by = ? and url_hash = ? and domain = ?", workspaceID, createdBy, urlHash, domain).
+		Where("status = ? and template = 0 and password_hash = '' and max_clicks = 0 and rules IS NULL and variants IS NULL and deep_link IS NULL and open_graph IS NULL and ip_access IS NULL and forward_query = 0 and forward_path = 0 and query_conflict = '' and redirect_type = '' and signed_only = 0 and delete_flag = 'N'", model.LinkStatusActive).
+		Where("NOT EXISTS (SELECT 1 FROM link_pixels WHERE link_pixels.link_id = links.id)").
+		Where("(activate_at IS NULL OR activate_at <= ?)", time.Now()).
+		Where("(expires_at IS NULL OR expires_at > ?)", time.Now()).
+		Order("created_at DESC").
+		First(&link)
 	if result.Error != nil {
 		if result.Error.Error() == gorm.ErrRecordNotFound.Error() {
 			return nil, errors.ErrLinkNotFound
 		}
-		return nil, &errors.RepositoryError{Operation: "FindByLongURL", Err: result.Error}
+		return nil, &errors.RepositoryError{Operation: "FindByURLHash", Err: result.Error}
 	}
 	return &link, nil
 }
 
-func (r *MySQLRepository) Exists(ctx context.Context, shortCode string) (bool, error) {
+func (r *MySQLRepository) Exists(ctx context.Context, domain, shortCode string) (bool, error) {
 	var count int64
-	result := r.db.WithContext(ctx).Model(&model.Link{}).Where("short_code=?", shortCode).Count(&count)
+	result := r.db.WithContext(ctx).Model(&model.Link{}).Where("domain=? and short_code=?", domain, shortCode).Count(&count)
 	if result.Error != nil {
 		return false, &errors.RepositoryError{Operation: "Exists", Err: result.Error}
 	}
 	return count > 0, nil
 }
 
-func (r *MySQLRepository) Update(ctx context.Context, link *model.Link) error {
-	result := r.db.WithContext(ctx).Save(link)
+func (r *MySQLRepository) FindByShortCodes(ctx context.Context, domain string, shortCodes []string) ([]model.Link, error) {
+	var links []model.Link
+	result := r.db.WithContext(ctx).
+		Where("domain = ? and short_code IN ? and delete_flag = 'N'", domain, shortCodes).
+		Find(&links)
 	if result.Error != nil {
-		return &errors.RepositoryError{Operation: "Update", Err: result.Error}
+		return nil, &errors.RepositoryError{Operation: "FindByShortCodes", Err: result.Error}
 	}
+	return links, nil
+}
+
+func (r *MySQLRepository) FindByIDs(ctx context.Context, ids []uint64) ([]model.Link, error) {
+	var links []model.Link
+	if len(ids) == 0 {
+		return links, nil
+	}
+	result := r.db.WithContext(ctx).
+		Where("id IN ? and delete_flag = 'N'", ids).
+		Find(&links)
+	if result.Error != nil {
+		return nil, &errors.RepositoryError{Operation: "FindByIDs", Err: result.Error}
+	}
+	return links, nil
+}
+
+func (r *MySQLRepository) ListIDsByWorkspace(ctx context.Context, workspaceID uint64) ([]uint64, error) {
+	var ids []uint64
+	result := r.db.WithContext(ctx).Model(&model.Link{}).
+		Where("workspace_id = ? and delete_flag = 'N'", workspaceID).
+		Pluck("id", &ids)
+	if result.Error != nil {
+		return nil, &errors.RepositoryError{Operation: "ListIDsByWorkspace", Err: result.Error}
+	}
+	return ids, nil
+}
+
+func (r *MySQLRepository) Update(ctx context.Context, link *model.Link, rev *model.LinkRevision, pixelIDs []uint64) error {
+	updated := *link
+	updated.Version = link.Version + 1
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockReferences(tx, &updated); err != nil {
+			return err
+		}
+		// 访问次数由点击事件单独累加，不随链接内容覆盖
+		result := tx.Model(&updated).
+			Where("version = ?", link.Version).
+			Select("*").
+			Omit("id", "click_count", "created_at", "created_by").
+			Updates(&updated)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.ErrVersionConflict
+		}
+		if pixelIDs != nil {
+			if err := tx.Where("link_id = ?", link.ID).Delete(&model.LinkPixel{}).Error; err != nil {
+				return err
+			}
+			if err := setPixels(tx, link.ID, pixelIDs, link.UpdatedBy); err != nil {
+				return err
+			}
+		}
+		rev.LinkID = link.ID
+		rev.Version = updated.Version
+		return tx.Create(rev).Error
+	})
+	if err == errors.ErrVersionConflict || err == errors.ErrDomainNotFound || err == errors.ErrFolderNotFound {
+		return err
+	}
+	if err != nil {
+		return &errors.RepositoryError{Operation: "Update", Err: err}
+	}
+	link.Version = updated.Version
+	link.UpdatedAt = updated.UpdatedAt
 	return nil
 }
 
@@ -79,42 +243,65 @@ func (r *MySQLRepository) UpdateClickCount(ctx context.Context, shortCode string
 	return nil
 }
 
-func (r *MySQLRepository) Delete(ctx context.Context, link *model.Link) error {
-	result := r.db.WithContext(ctx).Model(&model.Link{}).
-		Where("id = ? AND version = ?", link.ID, link.Version).
-		Updates(map[string]interface{}{
-			"delete_flag": "Y",
-			"version":     link.Version + 1,
-			"updated_at":  time.Now(),
-			"updated_by":  link.UpdatedBy,
-		})
-	if result.Error != nil {
-		return &errors.RepositoryError{Operation: "Delete", Err: result.Error}
+func (r *MySQLRepository) Delete(ctx context.Context, link *model.Link, rev *model.LinkRevision) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Link{}).
+			Where("id = ? AND version = ?", link.ID, link.Version).
+			Updates(map[string]interface{}{
+				"delete_flag": "Y",
+				"version":     link.Version + 1,
+				"updated_at":  time.Now(),
+				"updated_by":  link.UpdatedBy,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.ErrVersionConflict
+		}
+		rev.LinkID = link.ID
+		rev.Version = link.Version + 1
+		return tx.Create(rev).Error
+	})
+	if err == errors.ErrVersionConflict {
+		return err
 	}
-	if result.RowsAffected == 0 {
-		return errors.ErrLinkNotFound
+	if err != nil {
+		return &errors.RepositoryError{Operation: "Delete", Err: err}
 	}
+	link.Version++
 	return nil
 }
 
-func (r *MySQLRepository) List(ctx context.Context, filter ListFilter, page, pageSize int) ([]model.Link, int64, error) {
+func (r *MySQLRepository) ListRevisions(ctx context.Context, linkID uint64) ([]model.LinkRevision, error) {
+	var revisions []model.LinkRevision
+	result := r.db.WithContext(ctx).
+		Where("link_id = ?", linkID).
+		Order("version DESC").
+		Find(&revisions)
+	if result.Error != nil {
+		return nil, &errors.RepositoryError{Operation: "ListRevisions", Err: result.Error}
+	}
+	return revisions, nil
+}
+
+func (r *MySQLRepository) FindRevision(ctx context.Context, linkID uint64, version uint) (*model.LinkRevision, error) {
+	var rev model.LinkRevision
+	result := r.db.WithContext(ctx).Where("link_id = ? and version = ?", linkID, version).First(&rev)
+	if result.Error != nil {
+		if result.Error.Error() == gorm.ErrRecordNotFound.Error() {
+			return nil, errors.ErrRevisionNotFound
+		}
+		return nil, &errors.RepositoryError{Operation: "FindRevision", Err: result.Error}
+	}
+	return &rev, nil
+}
+
+func (r *MySQLRepository) List(ctx context.Context, filter ListFilter, sort ListSort, page, pageSize int) ([]model.Link, int64, error) {
 	var links []model.Link
 	var total int64
 
-	query := r.db.WithContext(ctx).Model(&model.Link{})
-
-	// 应用过滤器
-	if filter.CreatedBy != "" {
-		query = query.Where("created_by=?", filter.CreatedBy)
-	}
-	if filter.Status != "" {
-		query = query.Where("status=?", filter.Status)
-	}
-	if filter.Search != "" {
-		search := "%" + filter.Search + "%"
-		query = query.Where("short_code LIKE ? OR long_url LIKE ? ", search, search)
-	}
-	query = query.Where("delete_flag = 'N'")
+	query := r.filterQuery(ctx, filter)
 
 	// 计算总数
 	if err := query.Count(&total).Error; err != nil {
@@ -123,7 +310,7 @@ func (r *MySQLRepository) List(ctx context.Context, filter ListFilter, page, pag
 
 	// 分页查询
 	offset := (page - 1) * pageSize
-	result := query.Order("created_at DESC").
+	result := query.Order(orderClause(sort)).
 		Offset(offset).
 		Limit(pageSize).
 		Find(&links)
@@ -133,6 +320,121 @@ func (r *MySQLRepository) List(ctx context.Context, filter ListFilter, page, pag
 	return links, total, nil
 }
 
+func (r *MySQLRepository) ListAfter(ctx context.Context, filter ListFilter, sort ListSort, after *Cursor, limit int) ([]model.Link, error) {
+	var links []model.Link
+
+	query := r.filterQuery(ctx, filter)
+	if after != nil {
+		cond, args := seekCondition(sort, after)
+		query = query.Where(cond, args...)
+	}
+	result := query.Order(orderClause(sort)).
+		Limit(limit).
+		Find(&links)
+	if result.Error != nil {
+		return nil, &errors.RepositoryError{Operation: "ListAfter", Err: result.Error}
+	}
+	return links, nil
+}
+
+// 构造列表查询的过滤条件
+func (r *MySQLRepository) filterQuery(ctx context.Context, filter ListFilter) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&model.Link{}).
+		Where("workspace_id = ?", filter.WorkspaceID)
+
+	// 应用过滤器
+	if filter.CreatedBy != "" {
+		query = query.Where("created_by=?", filter.CreatedBy)
+	}
+	if filter.Status != "" {
+		query = query.Where("status=?", filter.Status)
+	}
+	if filter.Search != "" {
+		search := "%" + escapeLike(filter.Search) + "%"
+		query = query.Where("(short_code LIKE ? OR long_url LIKE ? OR description LIKE ?)", search, search, search)
+	}
+	if filter.CreatedAfter != nil {
+		query = query.Where("created_at >= ?", *filter.CreatedAfter)
+	}
+	if filter.CreatedBefore != nil {
+		query = query.Where("created_at < ?", *filter.CreatedBefore)
+	}
+	if filter.ExpiresAfter != nil {
+		query = query.Where("expires_at >= ?", *filter.ExpiresAfter)
+	}
+	if filter.ExpiresBefore != nil {
+		query = query.Where("expires_at < ?", *filter.ExpiresBefore)
+	}
+	if filter.NeverExpires != nil {
+		if *filter.NeverExpires {
+			query = query.Where("expires_at IS NULL")
+		} else {
+			query = query.Where("expires_at IS NOT NULL")
+		}
+	}
+	if filter.TagID != 0 {
+		query = query.Where("EXISTS (SELECT 1 FROM link_tags WHERE link_tags.link_id = links.id AND link_tags.tag_id = ?)", filter.TagID)
+	}
+	if len(filter.FolderIDs) > 0 {
+		query = query.Where("folder_id IN ?", filter.FolderIDs)
+	}
+	return query.Where("delete_flag = 'N'")
+}
+
+// 排序子句，expires_at 为空的记录排在最后（降序时排在最前）
+func orderClause(sort ListSort) string {
+	dir := "ASC"
+	if sort.Desc {
+		dir = "DESC"
+	}
+	switch sort.Field {
+	case SortByClickCount:
+		return "click_count " + dir + ", id " + dir
+	case SortByExpiresAt:
+		return "expires_at IS NULL " + dir + ", expires_at " + dir + ", id " + dir
+	default:
+		return "created_at " + dir + ", id " + dir
+	}
+}
+
+// 游标分页的定位条件，与 orderClause 的排序保持一致
+func seekCondition(sort ListSort, after *Cursor) (string, []interface{}) {
+	op := ">"
+	if sort.Desc {
+		op = "<"
+	}
+	switch sort.Field {
+	case SortByClickCount:
+		return "(click_count " + op + " ? OR (click_count = ? AND id " + op + " ?))",
+			[]interface{}{after.Count, after.Count, after.ID}
+	case SortByExpiresAt:
+		if after.Time == nil {
+			// 上一页停在永不过期的记录上
+			if sort.Desc {
+				return "((expires_at IS NULL AND id < ?) OR expires_at IS NOT NULL)", []interface{}{after.ID}
+			}
+			return "(expires_at IS NULL AND id > ?)", []interface{}{after.ID}
+		}
+		cond := "(expires_at IS NOT NULL AND (expires_at " + op + " ? OR (expires_at = ? AND id " + op + " ?)))"
+		if !sort.Desc {
+			cond = "(" + cond + " OR expires_at IS NULL)"
+		}
+		return cond, []interface{}{*after.Time, *after.Time, after.ID}
+	default:
+		var createdAt time.Time
+		if after.Time != nil {
+			createdAt = *after.Time
+		}
+		return "(created_at " + op + " ? OR (created_at = ? AND id " + op + " ?))",
+			[]interface{}{createdAt, createdAt, after.ID}
+	}
+}
+
+// 转义 LIKE 通配符
+func escapeLike(s string) string {
+	return strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_").Replace(s)
+}
+
 func (r *MySQLRepository) BatchCreate(ctx context.Context, links []model.Link) ([]model.Link, error) {
 	result := r.db.WithContext(ctx).Create(links)
 	if result.Error != nil {
@@ -145,7 +447,7 @@ func (r *MySQLRepository) CleanupExpired(ctx context.Context) (int64, error) {
 	now := time.Now()
 	result := r.db.WithContext(ctx).
 		Model(&model.Link{}).
-		Where("expired_at IS NOT NULL AND expires_at < ? AND status = ?", now, model.LinkStatusActive).
+		Where("expired_at IS NOT NULL AND expires_at < ? AND status IN ?", now, []model.LinkStatus{model.LinkStatusActive, model.LinkStatusFlagged}).
 		Update("status", model.LinkStatusExpired)
 
 	if result.Error != nil {
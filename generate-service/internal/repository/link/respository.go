@@ -3,27 +3,45 @@ package link
 import (
 	"context"
 	"generate-service/internal/model"
+	"time"
 )
 
 // Repository 链接数据访问接口
 type Repository interface {
-	// Create 创建链接
-	Create(ctx context.Context, link *model.Link) error
+	// Create 创建链接，同时写入首个修订记录、标签和像素，超出工作空间链接数上限时返回 ErrWorkspaceQuota。
+	// 事务内锁定链接引用的域名和文件夹，二者已被删除时返回 ErrDomainNotFound 或 ErrFolderNotFound
+	Create(ctx context.Context, link *model.Link, rev *model.LinkRevision, opts CreateOptions) error
 
-	// FindByShortCode 查询链接
-	FindByShortCode(ctx context.Context, shortCode string) (*model.Link, error)
-	FindByLongURL(ctx context.Context, longURL string) (*model.Link, error)
-	Exists(ctx context.Context, shortCode string) (bool, error)
+	// FindByShortCode 查询链接，domain 为空表示默认域名
+	FindByShortCode(ctx context.Context, domain, shortCode string) (*model.Link, error)
+	// FindByURLHash 查找指定用户在工作空间和域名下长链接哈希相同的最新有效链接，只返回未设置访问限制和跳转选项的普通链接
+	FindByURLHash(ctx context.Context, workspaceID uint64, createdBy, domain, urlHash string) (*model.Link, error)
+	Exists(ctx context.Context, domain, shortCode string) (bool, error)
+	// FindByShortCodes 批量查询同一域名下的链接，不存在的短码会被忽略
+	FindByShortCodes(ctx context.Context, domain string, shortCodes []string) ([]model.Link, error)
+	// FindByIDs 批量查询链接，已删除或不存在的ID会被忽略
+	FindByIDs(ctx context.Context, ids []uint64) ([]model.Link, error)
 
-	// Update 更新链接
-	Update(ctx context.Context, link *model.Link) error
+	// ListIDsByWorkspace 查询工作空间内所有链接的ID
+	ListIDsByWorkspace(ctx context.Context, workspaceID uint64) ([]uint64, error)
+
+	// Update 按版本号更新链接并写入修订记录，版本号不一致时返回 ErrVersionConflict，成功后 link.Version 加一。
+	// pixelIDs 非 nil 时在同一事务中整体替换链接的像素，引用的域名或文件夹锁定方式同 Create
+	Update(ctx context.Context, link *model.Link, rev *model.LinkRevision, pixelIDs []uint64) error
 	UpdateClickCount(ctx context.Context, shortCode string, increment int64) error
 
-	// Delete 删除链接
-	Delete(ctx context.Context, link *model.Link) error
+	// ListRevisions 查询链接的修订记录，按版本从新到旧排列
+	ListRevisions(ctx context.Context, linkID uint64) ([]model.LinkRevision, error)
+	// FindRevision 查询链接的指定版本，不存在时返回 ErrRevisionNotFound
+	FindRevision(ctx context.Context, linkID uint64, version uint) (*model.LinkRevision, error)
+
+	// Delete 按版本号删除链接并写入修订记录，版本号不一致时返回 ErrVersionConflict
+	Delete(ctx context.Context, link *model.Link, rev *model.LinkRevision) error
 
-	// List 列表查询
-	List(ctx context.Context, filter ListFilter, page, pageSize int) ([]model.Link, int64, error)
+	// List 列表查询（偏移分页），返回当前页和总数
+	List(ctx context.Context, filter ListFilter, sort ListSort, page, pageSize int) ([]model.Link, int64, error)
+	// ListAfter 列表查询（游标分页），返回排序位于 after 之后的最多 limit 条记录，after 为 nil 时从头开始
+	ListAfter(ctx context.Context, filter ListFilter, sort ListSort, after *Cursor, limit int) ([]model.Link, error)
 
 	// BatchCreate 批量创建
 	BatchCreate(ctx context.Context, links []model.Link) ([]model.Link, error)
@@ -32,8 +50,45 @@ type Repository interface {
 	CleanupExpired(ctx context.Context) (int64, error)
 }
 
+// CreateOptions 创建链接时在同一事务中完成的检查和关联写入
+type CreateOptions struct {
+	MaxLinks int64    // 工作空间的链接数上限，0表示不限制
+	TagIDs   []uint64 // 挂载的标签
+	PixelIDs []uint64 // 挂载的重定向像素
+}
+
 type ListFilter struct {
-	CreatedBy string
-	Status    string
-	Search    string
+	WorkspaceID   uint64 // 必填，查询范围限定在该工作空间内
+	CreatedBy     string
+	Status        string
+	Search        string // 模糊匹配短码、长链接和描述
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	ExpiresAfter  *time.Time
+	ExpiresBefore *time.Time
+	NeverExpires  *bool    // true 只查永不过期的链接，false 只查设置了过期时间的链接
+	TagID         uint64   // 只查带有该标签的链接
+	FolderIDs     []uint64 // 只查这些文件夹中的链接
+}
+
+// SortField 列表排序字段
+type SortField string
+
+const (
+	SortByCreatedAt  SortField = "created_at"
+	SortByClickCount SortField = "click_count"
+	SortByExpiresAt  SortField = "expires_at" // 永不过期的链接视为最晚过期
+)
+
+// ListSort 列表排序方式，排序值相同时按ID排序保证顺序稳定
+type ListSort struct {
+	Field SortField
+	Desc  bool
+}
+
+// Cursor 游标位置，即上一页最后一条记录的排序值和ID
+type Cursor struct {
+	Time  *time.Time // created_at 或 expires_at 的值，expires_at 为 nil 表示永不过期
+	Count int64      // click_count 的值
+	ID    uint64
 }
@@ -0,0 +1,190 @@
+package pixel
+
+import (
+	"context"
+	"generate-service/internal/model"
+	"generate-service/internal/pkg/errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type MySQLRepository struct {
+	db *gorm.DB
+}
+
+func (r *MySQLRepository) Create(ctx context.Context, pixel *model.Pixel) error {
+	result := r.db.WithContext(ctx).Create(pixel)
+	if result.Error != nil {
+		if strings.Contains(result.Error.Error(), "Duplicate entry") {
+			return errors.ErrPixelExists
+		}
+		return &errors.RepositoryError{Operation: "CreatePixel", Err: result.Error}
+	}
+	return nil
+}
+
+func (r *MySQLRepository) FindByID(ctx context.Context, id uint64) (*model.Pixel, error) {
+	var pixel model.Pixel
+	result := r.db.WithContext(ctx).Where("id = ?", id).First(&pixel)
+	if result.Error != nil {
+		if result.Error.Error() == gorm.ErrRecordNotFound.Error() {
+			return nil, errors.ErrPixelNotFound
+		}
+		return nil, &errors.RepositoryError{Operation: "FindPixelByID", Err: result.Error}
+	}
+	return &pixel, nil
+}
+
+func (r *MySQLRepository) FindByIDs(ctx context.Context, workspaceID uint64, ids []uint64) ([]model.Pixel, error) {
+	var pixels []model.Pixel
+	result := r.db.WithContext(ctx).
+		Where("workspace_id = ? AND id IN ?", workspaceID, ids).
+		Find(&pixels)
+	if result.Error != nil {
+		return nil, &errors.RepositoryError{Operation: "FindPixelsByIDs", Err: result.Error}
+	}
+	return pixels, nil
+}
+
+func (r *MySQLRepository) ListByWorkspace(ctx context.Context, workspaceID uint64) ([]model.Pixel, error) {
+	var pixels []model.Pixel
+	result := r.db.WithContext(ctx).
+		Where("workspace_id = ?", workspaceID).
+		Order("name ASC").
+		Find(&pixels)
+	if result.Error != nil {
+		return nil, &errors.RepositoryError{Operation: "ListPixels", Err: result.Error}
+	}
+	return pixels, nil
+}
+
+func (r *MySQLRepository) Update(ctx context.Context, pixel *model.Pixel) error {
+	result := r.db.WithContext(ctx).Model(&model.Pixel{}).
+		Where("id = ? AND version = ?", pixel.ID, pixel.Version).
+		Updates(map[string]interface{}{
+			"name":        pixel.Name,
+			"tracking_id": pixel.TrackingID,
+			"version":     pixel.Version + 1,
+			"updated_by":  pixel.UpdatedBy,
+		})
+	if result.Error != nil {
+		if strings.Contains(result.Error.Error(), "Duplicate entry") {
+			return errors.ErrPixelExists
+		}
+		return &errors.RepositoryError{Operation: "UpdatePixel", Err: result.Error}
+	}
+	if result.RowsAffected == 0 {
+		return errors.ErrPixelNotFound
+	}
+	pixel.Version++
+	return nil
+}
+
+// Delete 物理删除像素，同时删除关联，释放名称以便重新创建
+func (r *MySQLRepository) Delete(ctx context.Context, pixel *model.Pixel, revs []*model.LinkRevision) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		linkIDs := make([]uint64, len(revs))
+		for i, rev := range revs {
+			linkIDs[i] = rev.LinkID
+			if err := bumpLinkVersion(tx, rev); err != nil {
+				return err
+			}
+			if err := tx.Create(rev).Error; err != nil {
+				return err
+			}
+		}
+		// 读取受影响链接后又有链接挂载了该像素，这些链接没有修订记录，需要重试
+		query := tx.Model(&model.LinkPixel{}).
+			Joins("JOIN links ON links.id = link_pixels.link_id AND links.delete_flag = 'N'").
+			Where("link_pixels.pixel_id = ?", pixel.ID)
+		if len(linkIDs) > 0 {
+			query = query.Where("link_pixels.link_id NOT IN ?", linkIDs)
+		}
+		var unlisted int64
+		if err := query.Count(&unlisted).Error; err != nil {
+			return err
+		}
+		if unlisted > 0 {
+			return errors.ErrVersionConflict
+		}
+		if err := tx.Where("pixel_id = ?", pixel.ID).Delete(&model.LinkPixel{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("id = ? AND version = ?", pixel.ID, pixel.Version).Delete(&model.Pixel{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.ErrPixelNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		if err == errors.ErrPixelNotFound || err == errors.ErrVersionConflict {
+			return err
+		}
+		return &errors.RepositoryError{Operation: "DeletePixel", Err: err}
+	}
+	return nil
+}
+
+// 按修订记录的版本号递增链接版本，链接在生成修订记录后被修改时返回 ErrVersionConflict
+func bumpLinkVersion(tx *gorm.DB, rev *model.LinkRevision) error {
+	result := tx.Model(&model.Link{}).
+		Where("id = ? AND version = ? AND delete_flag = 'N'", rev.LinkID, rev.Version-1).
+		Updates(map[string]interface{}{
+			"version":    rev.Version,
+			"updated_at": time.Now(),
+			"updated_by": rev.CreatedBy,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.ErrVersionConflict
+	}
+	return nil
+}
+
+func (r *MySQLRepository) ListByLinks(ctx context.Context, linkIDs []uint64) (map[uint64][]model.Pixel, error) {
+	pixelsByLink := make(map[uint64][]model.Pixel, len(linkIDs))
+	if len(linkIDs) == 0 {
+		return pixelsByLink, nil
+	}
+	var rows []struct {
+		LinkID uint64
+		model.Pixel
+	}
+	result := r.db.WithContext(ctx).
+		Table("link_pixels").
+		Select("link_pixels.link_id, pixels.*").
+		Joins("JOIN pixels ON pixels.id = link_pixels.pixel_id").
+		Where("link_pixels.link_id IN ?", linkIDs).
+		Order("pixels.name ASC").
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, &errors.RepositoryError{Operation: "ListPixelsByLinks", Err: result.Error}
+	}
+	for _, row := range rows {
+		pixelsByLink[row.LinkID] = append(pixelsByLink[row.LinkID], row.Pixel)
+	}
+	return pixelsByLink, nil
+}
+
+func (r *MySQLRepository) ListLinkIDs(ctx context.Context, pixelID uint64) ([]uint64, error) {
+	var linkIDs []uint64
+	result := r.db.WithContext(ctx).
+		Model(&model.LinkPixel{}).
+		Where("pixel_id = ?", pixelID).
+		Pluck("link_id", &linkIDs)
+	if result.Error != nil {
+		return nil, &errors.RepositoryError{Operation: "ListPixelLinkIDs", Err: result.Error}
+	}
+	return linkIDs, nil
+}
+
+func NewMySQLRepository(db *gorm.DB) *MySQLRepository {
+	return &MySQLRepository{db: db}
+}
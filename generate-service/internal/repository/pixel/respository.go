@@ -0,0 +1,32 @@
+package pixel
+
+import (
+	"context"
+	"generate-service/internal/model"
+)
+
+// Repository 重定向像素数据访问接口
+type Repository interface {
+	// Create 创建像素
+	Create(ctx context.Context, pixel *model.Pixel) error
+
+	// FindByID 查询像素
+	FindByID(ctx context.Context, id uint64) (*model.Pixel, error)
+	// FindByIDs 查询工作空间内的多个像素，不存在的ID会被忽略
+	FindByIDs(ctx context.Context, workspaceID uint64, ids []uint64) ([]model.Pixel, error)
+
+	// ListByWorkspace 查询工作空间的所有像素
+	ListByWorkspace(ctx context.Context, workspaceID uint64) ([]model.Pixel, error)
+
+	// Update 更新像素
+	Update(ctx context.Context, pixel *model.Pixel) error
+
+	// Delete 删除像素及其与链接的关联，并在同一事务中为受影响的链接写入修订记录。
+	// 链接已被修改或有未列出的链接挂载了该像素时返回 ErrVersionConflict
+	Delete(ctx context.Context, pixel *model.Pixel, revs []*model.LinkRevision) error
+
+	// ListByLinks 批量查询链接的像素，按链接ID分组
+	ListByLinks(ctx context.Context, linkIDs []uint64) (map[uint64][]model.Pixel, error)
+	// ListLinkIDs 查询挂载了该像素的链接
+	ListLinkIDs(ctx context.Context, pixelID uint64) ([]uint64, error)
+}
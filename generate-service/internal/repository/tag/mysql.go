@@ -0,0 +1,166 @@
+package tag
+
+import (
+	"context"
+	"generate-service/internal/model"
+	"generate-service/internal/pkg/errors"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MySQLRepository struct {
+	db *gorm.DB
+}
+
+func (r *MySQLRepository) Create(ctx context.Context, tag *model.Tag) error {
+	result := r.db.WithContext(ctx).Create(tag)
+	if result.Error != nil {
+		if strings.Contains(result.Error.Error(), "Duplicate entry") {
+			return errors.ErrTagExists
+		}
+		return &errors.RepositoryError{Operation: "CreateTag", Err: result.Error}
+	}
+	return nil
+}
+
+func (r *MySQLRepository) FindByID(ctx context.Context, id uint64) (*model.Tag, error) {
+	var tag model.Tag
+	result := r.db.WithContext(ctx).Where("id = ?", id).First(&tag)
+	if result.Error != nil {
+		if result.Error.Error() == gorm.ErrRecordNotFound.Error() {
+			return nil, errors.ErrTagNotFound
+		}
+		return nil, &errors.RepositoryError{Operation: "FindTagByID", Err: result.Error}
+	}
+	return &tag, nil
+}
+
+func (r *MySQLRepository) FindByIDs(ctx context.Context, workspaceID uint64, ids []uint64) ([]model.Tag, error) {
+	var tags []model.Tag
+	result := r.db.WithContext(ctx).
+		Where("workspace_id = ? AND id IN ?", workspaceID, ids).
+		Find(&tags)
+	if result.Error != nil {
+		return nil, &errors.RepositoryError{Operation: "FindTagsByIDs", Err: result.Error}
+	}
+	return tags, nil
+}
+
+func (r *MySQLRepository) ListByWorkspace(ctx context.Context, workspaceID uint64) ([]model.Tag, error) {
+	var tags []model.Tag
+	result := r.db.WithContext(ctx).
+		Where("workspace_id = ?", workspaceID).
+		Order("name ASC").
+		Find(&tags)
+	if result.Error != nil {
+		return nil, &errors.RepositoryError{Operation: "ListTags", Err: result.Error}
+	}
+	return tags, nil
+}
+
+func (r *MySQLRepository) Update(ctx context.Context, tag *model.Tag) error {
+	result := r.db.WithContext(ctx).Model(&model.Tag{}).
+		Where("id = ? AND version = ?", tag.ID, tag.Version).
+		Updates(map[string]interface{}{
+			"name":       tag.Name,
+			"color":      tag.Color,
+			"version":    tag.Version + 1,
+			"updated_by": tag.UpdatedBy,
+		})
+	if result.Error != nil {
+		if strings.Contains(result.Error.Error(), "Duplicate entry") {
+			return errors.ErrTagExists
+		}
+		return &errors.RepositoryError{Operation: "UpdateTag", Err: result.Error}
+	}
+	if result.RowsAffected == 0 {
+		return errors.ErrTagNotFound
+	}
+	tag.Version++
+	return nil
+}
+
+// Delete 物理删除标签，同时删除关联，释放名称以便重新创建
+func (r *MySQLRepository) Delete(ctx context.Context, tag *model.Tag) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tag_id = ?", tag.ID).Delete(&model.LinkTag{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("id = ? AND version = ?", tag.ID, tag.Version).Delete(&model.Tag{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.ErrTagNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		if err == errors.ErrTagNotFound {
+			return err
+		}
+		return &errors.RepositoryError{Operation: "DeleteTag", Err: err}
+	}
+	return nil
+}
+
+func (r *MySQLRepository) AddToLinks(ctx context.Context, tagIDs, linkIDs []uint64, createdBy string) error {
+	rows := make([]model.LinkTag, 0, len(tagIDs)*len(linkIDs))
+	for _, linkID := range linkIDs {
+		for _, tagID := range tagIDs {
+			rows = append(rows, model.LinkTag{LinkID: linkID, TagID: tagID, CreatedBy: createdBy})
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&rows)
+	if result.Error != nil {
+		return &errors.RepositoryError{Operation: "AddTagsToLinks", Err: result.Error}
+	}
+	return nil
+}
+
+func (r *MySQLRepository) RemoveFromLinks(ctx context.Context, tagIDs, linkIDs []uint64) error {
+	if len(tagIDs) == 0 || len(linkIDs) == 0 {
+		return nil
+	}
+	result := r.db.WithContext(ctx).
+		Where("tag_id IN ? AND link_id IN ?", tagIDs, linkIDs).
+		Delete(&model.LinkTag{})
+	if result.Error != nil {
+		return &errors.RepositoryError{Operation: "RemoveTagsFromLinks", Err: result.Error}
+	}
+	return nil
+}
+
+func (r *MySQLRepository) ListByLinks(ctx context.Context, linkIDs []uint64) (map[uint64][]model.Tag, error) {
+	tagsByLink := make(map[uint64][]model.Tag, len(linkIDs))
+	if len(linkIDs) == 0 {
+		return tagsByLink, nil
+	}
+	var rows []struct {
+		LinkID uint64
+		model.Tag
+	}
+	result := r.db.WithContext(ctx).
+		Table("link_tags").
+		Select("link_tags.link_id, tags.*").
+		Joins("JOIN tags ON tags.id = link_tags.tag_id").
+		Where("link_tags.link_id IN ?", linkIDs).
+		Order("tags.name ASC").
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, &errors.RepositoryError{Operation: "ListTagsByLinks", Err: result.Error}
+	}
+	for _, row := range rows {
+		tagsByLink[row.LinkID] = append(tagsByLink[row.LinkID], row.Tag)
+	}
+	return tagsByLink, nil
+}
+
+func NewMySQLRepository(db *gorm.DB) *MySQLRepository {
+	return &MySQLRepository{db: db}
+}
@@ -0,0 +1,33 @@
+package tag
+
+import (
+	"context"
+	"generate-service/internal/model"
+)
+
+// Repository 标签数据访问接口
+type Repository interface {
+	// Create 创建标签
+	Create(ctx context.Context, tag *model.Tag) error
+
+	// FindByID 查询标签
+	FindByID(ctx context.Context, id uint64) (*model.Tag, error)
+	// FindByIDs 查询工作空间内的多个标签，不存在的ID会被忽略
+	FindByIDs(ctx context.Context, workspaceID uint64, ids []uint64) ([]model.Tag, error)
+
+	// ListByWorkspace 查询工作空间的所有标签
+	ListByWorkspace(ctx context.Context, workspaceID uint64) ([]model.Tag, error)
+
+	// Update 更新标签
+	Update(ctx context.Context, tag *model.Tag) error
+
+	// Delete 删除标签及其与链接的关联
+	Delete(ctx context.Context, tag *model.Tag) error
+
+	// AddToLinks 为链接添加标签，已存在的关联会被忽略
+	AddToLinks(ctx context.Context, tagIDs, linkIDs []uint64, createdBy string) error
+	// RemoveFromLinks 移除链接上的标签
+	RemoveFromLinks(ctx context.Context, tagIDs, linkIDs []uint64) error
+	// ListByLinks 批量查询链接的标签，按链接ID分组
+	ListByLinks(ctx context.Context, linkIDs []uint64) (map[uint64][]model.Tag, error)
+}
@@ -0,0 +1,140 @@
+package workspace
+
+import (
+	"context"
+	"generate-service/internal/model"
+	"generate-service/internal/pkg/errors"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type MySQLRepository struct {
+	db *gorm.DB
+}
+
+func (r *MySQLRepository) Create(ctx context.Context, ws *model.Workspace, owner *model.WorkspaceMember) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(ws).Error; err != nil {
+			return err
+		}
+		return tx.Create(owner).Error
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "Duplicate entry") {
+			return errors.ErrWorkspaceSlugExists
+		}
+		return &errors.RepositoryError{Operation: "CreateWorkspace", Err: err}
+	}
+	return nil
+}
+
+func (r *MySQLRepository) FindByID(ctx context.Context, id uint64) (*model.Workspace, error) {
+	var ws model.Workspace
+	result := r.db.WithContext(ctx).Where("id = ? and delete_flag = 'N'", id).First(&ws)
+	if result.Error != nil {
+		if result.Error.Error() == gorm.ErrRecordNotFound.Error() {
+			return nil, errors.ErrWorkspaceNotFound
+		}
+		return nil, &errors.RepositoryError{Operation: "FindWorkspaceByID", Err: result.Error}
+	}
+	return &ws, nil
+}
+
+func (r *MySQLRepository) UpdateSettings(ctx context.Context, ws *model.Workspace) error {
+	result := r.db.WithContext(ctx).Model(&model.Workspace{}).
+		Where("id = ? AND version = ?", ws.ID, ws.Version).
+		Updates(map[string]interface{}{
+			"settings":   ws.Settings,
+			"version":    ws.Version + 1,
+			"updated_by": ws.UpdatedBy,
+		})
+	if result.Error != nil {
+		return &errors.RepositoryError{Operation: "UpdateWorkspaceSettings", Err: result.Error}
+	}
+	if result.RowsAffected == 0 {
+		return errors.ErrWorkspaceNotFound
+	}
+	ws.Version++
+	return nil
+}
+
+func (r *MySQLRepository) ListByUser(ctx context.Context, userID string) ([]model.Workspace, []model.WorkspaceMember, error) {
+	var members []model.WorkspaceMember
+	result := r.db.WithContext(ctx).
+		Where("user_id = ? and delete_flag = 'N'", userID).
+		Order("created_at DESC").
+		Find(&members)
+	if result.Error != nil {
+		return nil, nil, &errors.RepositoryError{Operation: "ListWorkspaceMembersByUser", Err: result.Error}
+	}
+	if len(members) == 0 {
+		return nil, nil, nil
+	}
+
+	ids := make([]uint64, len(members))
+	for i, m := range members {
+		ids[i] = m.WorkspaceID
+	}
+	var workspaces []model.Workspace
+	result = r.db.WithContext(ctx).Where("id IN ? and delete_flag = 'N'", ids).Find(&workspaces)
+	if result.Error != nil {
+		return nil, nil, &errors.RepositoryError{Operation: "ListWorkspacesByUser", Err: result.Error}
+	}
+	return workspaces, members, nil
+}
+
+func (r *MySQLRepository) FindMember(ctx context.Context, workspaceID uint64, userID string) (*model.WorkspaceMember, error) {
+	var member model.WorkspaceMember
+	result := r.db.WithContext(ctx).
+		Where("workspace_id = ? and user_id = ? and delete_flag = 'N'", workspaceID, userID).
+		First(&member)
+	if result.Error != nil {
+		if result.Error.Error() == gorm.ErrRecordNotFound.Error() {
+			return nil, errors.ErrMemberNotFound
+		}
+		return nil, &errors.RepositoryError{Operation: "FindWorkspaceMember", Err: result.Error}
+	}
+	return &member, nil
+}
+
+func (r *MySQLRepository) ListMembers(ctx context.Context, workspaceID uint64) ([]model.WorkspaceMember, error) {
+	var members []model.WorkspaceMember
+	result := r.db.WithContext(ctx).
+		Where("workspace_id = ? and delete_flag = 'N'", workspaceID).
+		Order("created_at ASC").
+		Find(&members)
+	if result.Error != nil {
+		return nil, &errors.RepositoryError{Operation: "ListWorkspaceMembers", Err: result.Error}
+	}
+	return members, nil
+}
+
+func (r *MySQLRepository) AddMember(ctx context.Context, member *model.WorkspaceMember) error {
+	result := r.db.WithContext(ctx).Create(member)
+	if result.Error != nil {
+		if strings.Contains(result.Error.Error(), "Duplicate entry") {
+			return errors.ErrMemberExists
+		}
+		return &errors.RepositoryError{Operation: "AddWorkspaceMember", Err: result.Error}
+	}
+	return nil
+}
+
+// RemoveMember 物理删除成员记录，以便之后可重新加入（唯一索引不含 delete_flag）
+func (r *MySQLRepository) RemoveMember(ctx context.Context, member *model.WorkspaceMember) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND version = ?", member.ID, member.Version).
+		Delete(&model.WorkspaceMember{})
+	if result.Error != nil {
+		return &errors.RepositoryError{Operation: "RemoveWorkspaceMember", Err: result.Error}
+	}
+	if result.RowsAffected == 0 {
+		return errors.ErrMemberNotFound
+	}
+	return nil
+}
+
+func NewMySQLRepository(db *gorm.DB) *MySQLRepository {
+	return &MySQLRepository{db: db}
+}
@@ -0,0 +1,31 @@
+package workspace
+
+import (
+	"context"
+	"generate-service/internal/model"
+)
+
+// Repository 工作空间数据访问接口
+type Repository interface {
+	// Create 创建工作空间，并将创建者加入为管理员
+	Create(ctx context.Context, ws *model.Workspace, owner *model.WorkspaceMember) error
+
+	// FindByID 查询工作空间
+	FindByID(ctx context.Context, id uint64) (*model.Workspace, error)
+
+	// UpdateSettings 更新工作空间设置
+	UpdateSettings(ctx context.Context, ws *model.Workspace) error
+
+	// ListByUser 查询用户所属的工作空间及其角色
+	ListByUser(ctx context.Context, userID string) ([]model.Workspace, []model.WorkspaceMember, error)
+
+	// FindMember 查询成员
+	FindMember(ctx context.Context, workspaceID uint64, userID string) (*model.WorkspaceMember, error)
+	ListMembers(ctx context.Context, workspaceID uint64) ([]model.WorkspaceMember, error)
+
+	// AddMember 添加成员
+	AddMember(ctx context.Context, member *model.WorkspaceMember) error
+
+	// RemoveMember 移除成员
+	RemoveMember(ctx context.Context, member *model.WorkspaceMember) error
+}
@@ -2,6 +2,8 @@ package grpc
 
 import (
 	"context"
+	"generate-service/internal/model"
+	"generate-service/internal/pkg/errors"
 	"generate-service/internal/service/link"
 	"log"
 	pb "shared/proto/generate"
@@ -23,23 +25,117 @@ func NewGenerateServer(linkService link.Service) *GenerateServer {
 }
 
 func (s *GenerateServer) GetOriginalUrl(ctx context.Context, req *pb.GetOriginalUrlRequest) (*pb.GetOriginalUrlResponse, error) {
-	log.Printf("gRPC request received: GetOriginalUrl for %s", req.ShortCode)
+	log.Printf("gRPC request received: GetOriginalUrl for %s/%s", req.Domain, req.ShortCode)
 	if req.ShortCode == "" {
 		return nil, status.Error(codes.InvalidArgument, "short code is required")
 	}
 
 	// 调用业务服务
-	lk, err := s.linkService.GetLink(ctx, req.ShortCode)
+	lk, err := s.linkService.GetLink(ctx, req.Domain, req.ShortCode)
 	if err != nil {
-		return nil, status.Error(codes.Canceled, err.Error())
+		return nil, status.Error(grpcCode(err), err.Error())
 	}
 	resp := &pb.GetOriginalUrlResponse{
-		OriginalUrl:  lk.LongURL,
-		IsActive:     true,
-		ErrorMessage: "",
+		OriginalUrl:   lk.LongURL,
+		IsActive:      !lk.Pending(),
+		ErrorMessage:  "",
+		WorkspaceId:   lk.WorkspaceID,
+		PasswordHash:  lk.PasswordHash,
+		MaxClicks:     lk.MaxClicks,
+		ForwardQuery:  lk.ForwardQuery,
+		QueryConflict: lk.QueryConflict,
+		ForwardPath:   lk.ForwardPath,
+		Template:      lk.Template,
+		RedirectType:  lk.RedirectType,
+		Flagged:       lk.Status == model.LinkStatusFlagged,
+		SignedOnly:    lk.SignedOnly,
+	}
+	if lk.ExpiresAt != nil && !lk.ExpiresAt.IsZero() {
+		resp.ExpireTime = timestamppb.New(*lk.ExpiresAt)
+	}
+	if lk.ActivateAt != nil {
+		resp.ActivateTime = timestamppb.New(*lk.ActivateAt)
+	}
+	for _, rule := range lk.Rules {
+		resp.Rules = append(resp.Rules, &pb.RedirectRule{
+			Countries:   rule.Countries,
+			Devices:     rule.Devices,
+			Os:          rule.OS,
+			Languages:   rule.Languages,
+			TimeStart:   rule.TimeStart,
+			TimeEnd:     rule.TimeEnd,
+			Timezone:    rule.Timezone,
+			Destination: rule.Destination,
+		})
+	}
+	for _, v := range lk.Variants {
+		resp.Variants = append(resp.Variants, &pb.LinkVariant{
+			Id:          v.ID,
+			Destination: v.Destination,
+			Weight:      int32(v.Weight),
+		})
+	}
+	if dl := lk.DeepLink; dl != nil {
+		resp.DeepLink = &pb.DeepLink{
+			IosUrl:          dl.IOSURL,
+			IosStoreUrl:     dl.IOSStoreURL,
+			AndroidUrl:      dl.AndroidURL,
+			AndroidPackage:  dl.AndroidPackage,
+			AndroidStoreUrl: dl.AndroidStoreURL,
+		}
+	}
+	if og := lk.OpenGraph; og != nil {
+		resp.OpenGraph = &pb.OpenGraph{
+			Title:       og.Title,
+			Description: og.Description,
+			Image:       og.Image,
+		}
+	}
+	for _, px := range lk.Pixels {
+		resp.Pixels = append(resp.Pixels, &pb.Pixel{
+			Provider:   px.Provider,
+			TrackingId: px.TrackingID,
+		})
+	}
+	resp.IpAccess = toPbIPAccess(lk.IPAccess)
+	resp.WorkspaceIpAccess = toPbIPAccess(lk.WorkspaceIPAccess)
+	return resp, nil
+}
+
+func (s *GenerateServer) GetLinkMetadata(ctx context.Context, req *pb.GetLinkMetadataRequest) (*pb.GetLinkMetadataResponse, error) {
+	if req.ShortCode == "" {
+		return nil, status.Error(codes.InvalidArgument, "short code is required")
+	}
+	lk, err := s.linkService.GetLinkMetadata(ctx, req.Domain, req.ShortCode)
+	if err != nil {
+		return nil, status.Error(grpcCode(err), err.Error())
+	}
+	resp := &pb.GetLinkMetadataResponse{
+		Title:      lk.Description,
+		CreateTime: timestamppb.New(lk.CreatedAt),
+		Status:     string(lk.EffectiveStatus()),
 	}
 	if lk.ExpiresAt != nil && !lk.ExpiresAt.IsZero() {
 		resp.ExpireTime = timestamppb.New(*lk.ExpiresAt)
 	}
 	return resp, nil
 }
+
+// 业务错误映射为 gRPC 状态码，调用方据此区分不存在、已过期和已禁用
+func grpcCode(err error) codes.Code {
+	switch err {
+	case errors.ErrLinkNotFound:
+		return codes.NotFound
+	case errors.ErrLinkExpired, errors.ErrLinkDisabled:
+		return codes.FailedPrecondition
+	default:
+		return codes.Internal
+	}
+}
+
+func toPbIPAccess(a *model.IPAccess) *pb.IPAccess {
+	if a == nil {
+		return nil
+	}
+	return &pb.IPAccess{Allow: a.Allow, Deny: a.Deny}
+}
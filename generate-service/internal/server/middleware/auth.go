@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"generate-service/internal/service/auth"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Auth 认证中间件，支持 Authorization: Bearer <token> 与 X-API-Key 两种方式
+func Auth(authService auth.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, err := authService.Authenticate(c.Request.Context(), extractCredential(c))
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+
+		// 写入请求上下文，供 service 层读取当前用户
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+		c.Next()
+	}
+}
+
+// 提取请求中的凭证
+func extractCredential(c *gin.Context) string {
+	if apiKey := c.GetHeader("X-API-Key"); apiKey != "" {
+		return strings.TrimSpace(apiKey)
+	}
+	authorization := c.GetHeader("Authorization")
+	if len(authorization) > 7 && strings.EqualFold(authorization[:7], "Bearer ") {
+		return strings.TrimSpace(authorization[7:])
+	}
+	return ""
+}
@@ -12,14 +12,15 @@ func Cors() gin.HandlerFunc {
 	return cors.New(cors.Options{
 		AllowedOrigins: []string{"*"},
 		AllowedMethods: []string{
-			"GET", "POST", "PUT", "DELETE", "OPTIONS",
+			"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS",
 		},
 		AllowedHeaders: []string{
 			"Origin", "Content-Type", "Content-Length", "Accept-Encoding",
 			"X-CSRF-Token", "Authorization", "Accept", "Cache-Control", "X-Requested-With",
+			"If-Match",
 		},
 		ExposedHeaders: []string{
-			"Content-Length", "Link",
+			"Content-Length", "Link", "ETag",
 		},
 		AllowCredentials: true,
 		MaxAge:           int(12 * time.Hour),
@@ -27,11 +27,7 @@ func ErrorHandler() gin.HandlerFunc {
 
 			switch err := lastError.(type) {
 			case *errors.BusinessError:
-				statusCode = http.StatusBadRequest
-				errorResp = model.ErrorResponse{
-					Error:   "business_error",
-					Message: err.Error(),
-				}
+				statusCode, errorResp = businessErrorResponse(err)
 			case *errors.ValidationError:
 				statusCode = http.StatusBadRequest
 				errorResp = model.ErrorResponse{
@@ -46,50 +42,10 @@ func ErrorHandler() gin.HandlerFunc {
 					Message: "Internal server error",
 				}
 			default:
-				// 处理已知的业务错误
-				switch lastError {
-				case errors.ErrLinkNotFound:
-					statusCode = http.StatusNotFound
-					errorResp = model.ErrorResponse{
-						Error:   "link_not_found",
-						Message: "Short link not found",
-					}
-				case errors.ErrLinkExpired:
-					statusCode = http.StatusGone
-					errorResp = model.ErrorResponse{
-						Error:   "link_expired",
-						Message: "Short link has expired",
-					}
-				case errors.ErrLinkDisabled:
-					statusCode = http.StatusForbidden
-					errorResp = model.ErrorResponse{
-						Error:   "link_disabled",
-						Message: "Short link is disabled",
-					}
-				case errors.ErrInvalidURL:
-					statusCode = http.StatusBadRequest
-					errorResp = model.ErrorResponse{
-						Error:   "invalid_url",
-						Message: "Invalid URL format",
-					}
-				case errors.ErrShortCodeExists:
-					statusCode = http.StatusConflict
-					errorResp = model.ErrorResponse{
-						Error:   "short_code_exists",
-						Message: "Short code already exists",
-					}
-				case errors.ErrInvalidShortCode:
-					statusCode = http.StatusBadRequest
-					errorResp = model.ErrorResponse{
-						Error:   "invalid_short_code",
-						Message: "Invalid short code format",
-					}
-				default:
-					statusCode = http.StatusInternalServerError
-					errorResp = model.ErrorResponse{
-						Error:   "internal_error",
-						Message: "Internal server error",
-					}
+				statusCode = http.StatusInternalServerError
+				errorResp = model.ErrorResponse{
+					Error:   "internal_error",
+					Message: "Internal server error",
 				}
 			}
 
@@ -99,3 +55,224 @@ func ErrorHandler() gin.HandlerFunc {
 		}
 	}
 }
+
+// 已知业务错误映射为对应的HTTP状态码，其余业务错误统一返回400
+func businessErrorResponse(err *errors.BusinessError) (int, model.ErrorResponse) {
+	switch err {
+	case errors.ErrLinkNotFound:
+		return http.StatusNotFound, model.ErrorResponse{
+			Error:   "link_not_found",
+			Message: "Short link not found",
+		}
+	case errors.ErrVersionConflict:
+		return http.StatusPreconditionFailed, model.ErrorResponse{
+			Error:   "version_conflict",
+			Message: "Short link was modified by another request, reload and retry",
+		}
+	case errors.ErrRevisionNotFound:
+		return http.StatusNotFound, model.ErrorResponse{
+			Error:   "revision_not_found",
+			Message: "Link revision not found",
+		}
+	case errors.ErrLinkExpired:
+		return http.StatusGone, model.ErrorResponse{
+			Error:   "link_expired",
+			Message: "Short link has expired",
+		}
+	case errors.ErrLinkDisabled:
+		return http.StatusForbidden, model.ErrorResponse{
+			Error:   "link_disabled",
+			Message: "Short link is disabled",
+		}
+	case errors.ErrInvalidURL:
+		return http.StatusBadRequest, model.ErrorResponse{
+			Error:   "invalid_url",
+			Message: "Invalid URL format",
+		}
+	case errors.ErrShortCodeExists:
+		return http.StatusConflict, model.ErrorResponse{
+			Error:   "short_code_exists",
+			Message: "Short code already exists",
+		}
+	case errors.ErrInvalidShortCode:
+		return http.StatusBadRequest, model.ErrorResponse{
+			Error:   "invalid_short_code",
+			Message: "Invalid short code format",
+		}
+	case errors.ErrUnauthorized:
+		return http.StatusUnauthorized, model.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "Missing or invalid credentials",
+		}
+	case errors.ErrForbidden:
+		return http.StatusForbidden, model.ErrorResponse{
+			Error:   "forbidden",
+			Message: "You do not have permission to perform this action",
+		}
+	case errors.ErrAPIKeyNotFound:
+		return http.StatusNotFound, model.ErrorResponse{
+			Error:   "api_key_not_found",
+			Message: "API key not found",
+		}
+	case errors.ErrInvalidCursor:
+		return http.StatusBadRequest, model.ErrorResponse{
+			Error:   "invalid_cursor",
+			Message: "Invalid pagination cursor",
+		}
+	case errors.ErrInvalidSchedule:
+		return http.StatusBadRequest, model.ErrorResponse{
+			Error:   "invalid_schedule",
+			Message: "activate_at must be before expires_at",
+		}
+	case errors.ErrInvalidRule:
+		return http.StatusBadRequest, model.ErrorResponse{
+			Error:   "invalid_rule",
+			Message: "Each redirect rule needs at least one valid condition and a destination URL",
+		}
+	case errors.ErrInvalidVariants:
+		return http.StatusBadRequest, model.ErrorResponse{
+			Error:   "invalid_variants",
+			Message: "Variants need 2 to 10 entries with unique IDs, valid destinations and weights summing to 100",
+		}
+	case errors.ErrInvalidTemplate:
+		return http.StatusBadRequest, model.ErrorResponse{
+			Error:   "invalid_template",
+			Message: "Placeholders such as {1} or {q} are only allowed in the path and query of the destination",
+		}
+	case errors.ErrInvalidPatch:
+		return http.StatusBadRequest, model.ErrorResponse{
+			Error:   "invalid_patch",
+			Message: "Request body must be a JSON merge patch object",
+		}
+	case errors.ErrInvalidIPAccess:
+		return http.StatusBadRequest, model.ErrorResponse{
+			Error:   "invalid_ip_access",
+			Message: "IP access entries must be IP addresses or CIDR ranges, at most 100 per list",
+		}
+	case errors.ErrInvalidDeepLink:
+		return http.StatusBadRequest, model.ErrorResponse{
+			Error:   "invalid_deep_link",
+			Message: "App URLs need a scheme, and android_url requires android_package",
+		}
+	case errors.ErrWorkspaceRequired:
+		return http.StatusBadRequest, model.ErrorResponse{
+			Error:   "workspace_required",
+			Message: "X-Workspace-ID header is required",
+		}
+	case errors.ErrWorkspaceNotFound:
+		return http.StatusNotFound, model.ErrorResponse{
+			Error:   "workspace_not_found",
+			Message: "Workspace not found",
+		}
+	case errors.ErrWorkspaceSlugExists:
+		return http.StatusConflict, model.ErrorResponse{
+			Error:   "workspace_slug_exists",
+			Message: "Workspace slug already exists",
+		}
+	case errors.ErrWorkspaceQuota:
+		return http.StatusForbidden, model.ErrorResponse{
+			Error:   "workspace_quota_exceeded",
+			Message: "Workspace link quota exceeded",
+		}
+	case errors.ErrMemberNotFound:
+		return http.StatusNotFound, model.ErrorResponse{
+			Error:   "member_not_found",
+			Message: "Workspace member not found",
+		}
+	case errors.ErrMemberExists:
+		return http.StatusConflict, model.ErrorResponse{
+			Error:   "member_exists",
+			Message: "Workspace member already exists",
+		}
+	case errors.ErrDomainNotFound:
+		return http.StatusNotFound, model.ErrorResponse{
+			Error:   "domain_not_found",
+			Message: "Domain not found",
+		}
+	case errors.ErrDomainExists:
+		return http.StatusConflict, model.ErrorResponse{
+			Error:   "domain_exists",
+			Message: "Domain is already registered",
+		}
+	case errors.ErrInvalidDomain:
+		return http.StatusBadRequest, model.ErrorResponse{
+			Error:   "invalid_domain",
+			Message: "Invalid domain name",
+		}
+	case errors.ErrDomainInUse:
+		return http.StatusConflict, model.ErrorResponse{
+			Error:   "domain_in_use",
+			Message: "Domain still has links and cannot be removed",
+		}
+	case errors.ErrDomainUnverified:
+		return http.StatusBadRequest, model.ErrorResponse{
+			Error:   "domain_unverified",
+			Message: "Domain ownership verification TXT record not found",
+		}
+	case errors.ErrInvalidAppLinks:
+		return http.StatusBadRequest, model.ErrorResponse{
+			Error:   "invalid_app_links",
+			Message: "Invalid Apple app ID, Android package name or certificate fingerprint",
+		}
+	case errors.ErrTagNotFound:
+		return http.StatusNotFound, model.ErrorResponse{
+			Error:   "tag_not_found",
+			Message: "Tag not found",
+		}
+	case errors.ErrTagExists:
+		return http.StatusConflict, model.ErrorResponse{
+			Error:   "tag_exists",
+			Message: "Tag with this name already exists",
+		}
+	case errors.ErrFolderNotFound:
+		return http.StatusNotFound, model.ErrorResponse{
+			Error:   "folder_not_found",
+			Message: "Folder not found",
+		}
+	case errors.ErrFolderExists:
+		return http.StatusConflict, model.ErrorResponse{
+			Error:   "folder_exists",
+			Message: "Folder with this name already exists in the parent folder",
+		}
+	case errors.ErrFolderNotEmpty:
+		return http.StatusConflict, model.ErrorResponse{
+			Error:   "folder_not_empty",
+			Message: "Folder still contains links or subfolders",
+		}
+	case errors.ErrInvalidFolderParent:
+		return http.StatusBadRequest, model.ErrorResponse{
+			Error:   "invalid_folder_parent",
+			Message: "A folder cannot be moved into itself or its subfolders",
+		}
+	case errors.ErrPixelNotFound:
+		return http.StatusNotFound, model.ErrorResponse{
+			Error:   "pixel_not_found",
+			Message: "Pixel not found",
+		}
+	case errors.ErrPixelExists:
+		return http.StatusConflict, model.ErrorResponse{
+			Error:   "pixel_exists",
+			Message: "Pixel with this name already exists",
+		}
+	case errors.ErrInvalidPixel:
+		return http.StatusBadRequest, model.ErrorResponse{
+			Error:   "invalid_pixel",
+			Message: "Tracking ID does not match the provider format",
+		}
+	case errors.ErrInvalidSignedLink:
+		return http.StatusBadRequest, model.ErrorResponse{
+			Error:   "invalid_signed_link",
+			Message: "TTL exceeds the maximum or more than one binding is set",
+		}
+	case errors.ErrSigningUnavailable:
+		return http.StatusServiceUnavailable, model.ErrorResponse{
+			Error:   "signing_unavailable",
+			Message: "No active signing key is configured",
+		}
+	default:
+		return http.StatusBadRequest, model.ErrorResponse{
+			Error:   "business_error",
+			Message: err.Error(),
+		}
+	}
+}
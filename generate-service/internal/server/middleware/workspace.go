@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"generate-service/internal/pkg/errors"
+	"generate-service/internal/service/workspace"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Workspace 工作空间中间件，根据 X-Workspace-ID 校验成员身份并限定后续操作范围，需在 Auth 之后使用
+func Workspace(workspaceService workspace.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := strings.TrimSpace(c.GetHeader("X-Workspace-ID"))
+		if header == "" {
+			c.Error(errors.ErrWorkspaceRequired)
+			c.Abort()
+			return
+		}
+		workspaceID, err := strconv.ParseUint(header, 10, 64)
+		if err != nil {
+			c.Error(errors.ErrWorkspaceNotFound)
+			c.Abort()
+			return
+		}
+		ctx, err := workspaceService.Enter(c.Request.Context(), workspaceID)
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
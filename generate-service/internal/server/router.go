@@ -11,7 +11,7 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func setupRouter(config *config.Config, srv *Server) {
+func setupRouter(config *config.Config, srv *Server) error {
 	// 设置Gin模式
 	gin.SetMode(config.Server.HTTP.Mode)
 	router := gin.New()
@@ -24,7 +24,17 @@ func setupRouter(config *config.Config, srv *Server) {
 
 	// 初始化处理器
 	linkHandler := handler.NewLinkHandler(srv.linkSvc, config.Server.BaseURL)
-	qrcodeHandler := handler.NewQRCodeHandler(srv.linkSvc, config.Server.BaseURL)
+	qrcodeHandler := handler.NewQRCodeHandler(srv.linkSvc)
+	apiKeyHandler := handler.NewAPIKeyHandler(srv.authSvc)
+	workspaceHandler := handler.NewWorkspaceHandler(srv.workspaceSvc)
+	domainHandler := handler.NewDomainHandler(srv.domainSvc)
+	tagHandler := handler.NewTagHandler(srv.tagSvc)
+	folderHandler := handler.NewFolderHandler(srv.folderSvc)
+	pixelHandler := handler.NewPixelHandler(srv.pixelSvc)
+	statsHandler, err := handler.NewStatsHandler(srv.linkSvc, config.Stats)
+	if err != nil {
+		return err
+	}
 
 	// 注册 pprof 路由，默认路径是 /debug/pprof/
 	pprof.Register(router)
@@ -62,23 +72,101 @@ func setupRouter(config *config.Config, srv *Server) {
 		c.JSON(200, health)
 	})
 
+	// App 关联文档，按请求的自定义域名返回，供 iOS 和 Android 校验深度链接
+	wellKnown := router.Group("/.well-known")
+	{
+		wellKnown.GET("/apple-app-site-association", domainHandler.AppleAppSiteAssociation)
+		wellKnown.GET("/assetlinks.json", domainHandler.AssetLinks)
+	}
+
 	api := router.Group("/api/v1")
 	api.Use(middleware.RateLimit(config.RateLimit.RequestPerMinute)) // 每分钟10个请求/minute
 	{
-		// 短链相关接口
+		// 短链相关接口，需通过 X-Workspace-ID 指定工作空间
 		linkGroup := api.Group("/links")
+		linkGroup.Use(middleware.Auth(srv.authSvc), middleware.Workspace(srv.workspaceSvc))
 		linkGroup.POST("/short", linkHandler.CreateShortURL)
 		linkGroup.GET("/:code", linkHandler.GetLinkInfo)
 		linkGroup.PUT("/:code", linkHandler.UpdateLink)
+		linkGroup.PATCH("/:code", linkHandler.PatchLink)
 		linkGroup.DELETE("/:code", linkHandler.DeleteLink)
+		linkGroup.POST("/:code/signed-url", linkHandler.SignLink)
+		linkGroup.GET("/:code/history", linkHandler.GetLinkHistory)
+		linkGroup.POST("/:code/rollback/:version", linkHandler.RollbackLink)
+		linkGroup.GET("/:code/stats/*path", statsHandler.ProxyStats)
 		linkGroup.GET("", linkHandler.ListLinks)
 		linkGroup.POST("/short/batch", linkHandler.BatchCreate)
+		linkGroup.POST("/bulk/tag", linkHandler.TagLinks)
+		linkGroup.POST("/bulk/untag", linkHandler.UntagLinks)
 
 		// 生成二维码相关接口
 		qrcodeGroup := linkGroup.Group("/qrcode")
 		{
 			qrcodeGroup.GET("/:code", qrcodeHandler.GenerateQRCode)
 		}
+
+		// API密钥管理接口
+		apiKeyGroup := api.Group("/api-keys")
+		apiKeyGroup.Use(middleware.Auth(srv.authSvc))
+		{
+			apiKeyGroup.POST("", apiKeyHandler.CreateAPIKey)
+			apiKeyGroup.GET("", apiKeyHandler.ListAPIKeys)
+			apiKeyGroup.DELETE("/:id", apiKeyHandler.RevokeAPIKey)
+		}
+
+		// 自定义域名管理接口，需通过 X-Workspace-ID 指定工作空间
+		domainGroup := api.Group("/domains")
+		domainGroup.Use(middleware.Auth(srv.authSvc), middleware.Workspace(srv.workspaceSvc))
+		{
+			domainGroup.POST("", domainHandler.AddDomain)
+			domainGroup.GET("", domainHandler.ListDomains)
+			domainGroup.DELETE("/:id", domainHandler.RemoveDomain)
+			domainGroup.POST("/:id/verify", domainHandler.VerifyDomain)
+			domainGroup.PUT("/:id/app-links", domainHandler.UpdateAppLinks)
+		}
+
+		// 标签管理接口，需通过 X-Workspace-ID 指定工作空间
+		tagGroup := api.Group("/tags")
+		tagGroup.Use(middleware.Auth(srv.authSvc), middleware.Workspace(srv.workspaceSvc))
+		{
+			tagGroup.POST("", tagHandler.CreateTag)
+			tagGroup.GET("", tagHandler.ListTags)
+			tagGroup.PUT("/:id", tagHandler.UpdateTag)
+			tagGroup.DELETE("/:id", tagHandler.DeleteTag)
+		}
+
+		// 重定向像素管理接口，需通过 X-Workspace-ID 指定工作空间
+		pixelGroup := api.Group("/pixels")
+		pixelGroup.Use(middleware.Auth(srv.authSvc), middleware.Workspace(srv.workspaceSvc))
+		{
+			pixelGroup.POST("", pixelHandler.CreatePixel)
+			pixelGroup.GET("", pixelHandler.ListPixels)
+			pixelGroup.PUT("/:id", pixelHandler.UpdatePixel)
+			pixelGroup.DELETE("/:id", pixelHandler.DeletePixel)
+		}
+
+		// 文件夹管理接口，需通过 X-Workspace-ID 指定工作空间
+		folderGroup := api.Group("/folders")
+		folderGroup.Use(middleware.Auth(srv.authSvc), middleware.Workspace(srv.workspaceSvc))
+		{
+			folderGroup.POST("", folderHandler.CreateFolder)
+			folderGroup.GET("", folderHandler.ListFolders)
+			folderGroup.PUT("/:id", folderHandler.UpdateFolder)
+			folderGroup.DELETE("/:id", folderHandler.DeleteFolder)
+		}
+
+		// 工作空间管理接口
+		workspaceGroup := api.Group("/workspaces")
+		workspaceGroup.Use(middleware.Auth(srv.authSvc))
+		{
+			workspaceGroup.POST("", workspaceHandler.CreateWorkspace)
+			workspaceGroup.GET("", workspaceHandler.ListWorkspaces)
+			workspaceGroup.GET("/:id", workspaceHandler.GetWorkspace)
+			workspaceGroup.GET("/:id/members", workspaceHandler.ListMembers)
+			workspaceGroup.POST("/:id/members", workspaceHandler.AddMember)
+			workspaceGroup.DELETE("/:id/members/:user_id", workspaceHandler.RemoveMember)
+			workspaceGroup.PUT("/:id/ip-access", workspaceHandler.UpdateIPAccess)
+		}
 	}
 
 	api.GET("/info", func(c *gin.Context) {
@@ -89,4 +177,5 @@ func setupRouter(config *config.Config, srv *Server) {
 	})
 
 	srv.router = router
+	return nil
 }
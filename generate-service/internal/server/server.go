@@ -5,19 +5,34 @@ import (
 	"errors"
 	"fmt"
 	"generate-service/internal/config"
+	"generate-service/internal/consumer"
 	"generate-service/internal/pkg/database"
 	"generate-service/internal/pkg/mq"
+	apiKeyRepo "generate-service/internal/repository/apikey"
+	domainRepo "generate-service/internal/repository/domain"
+	folderRepo "generate-service/internal/repository/folder"
 	linkRepo "generate-service/internal/repository/link"
+	pixelRepo "generate-service/internal/repository/pixel"
+	tagRepo "generate-service/internal/repository/tag"
+	workspaceRepo "generate-service/internal/repository/workspace"
 	grpcSrv "generate-service/internal/server/grpc"
+	"generate-service/internal/service/auth"
+	domainService "generate-service/internal/service/domain"
+	folderService "generate-service/internal/service/folder"
 	"generate-service/internal/service/idgen"
 	linkService "generate-service/internal/service/link"
+	pixelService "generate-service/internal/service/pixel"
 	"generate-service/internal/service/register"
+	tagService "generate-service/internal/service/tag"
+	workspaceService "generate-service/internal/service/workspace"
 	"log"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"shared/constants"
 	pb "shared/proto/generate"
+	"shared/signedlink"
 	"syscall"
 	"time"
 
@@ -34,9 +49,22 @@ type Server struct {
 	mysqlDB       *database.MySQLDB
 	redisClient   *database.RedisClient
 	linkRepo      linkRepo.Repository
+	apiKeyRepo    apiKeyRepo.Repository
+	workspaceRepo workspaceRepo.Repository
+	domainRepo    domainRepo.Repository
+	tagRepo       tagRepo.Repository
+	folderRepo    folderRepo.Repository
+	pixelRepo     pixelRepo.Repository
 	idGenerator   idgen.Generator
 	linkSvc       linkService.Service
+	authSvc       auth.Service
+	workspaceSvc  workspaceService.Service
+	domainSvc     domainService.Service
+	tagSvc        tagService.Service
+	folderSvc     folderService.Service
+	pixelSvc      pixelService.Service
 	kafkaProducer *mq.KafkaProducer
+	kafkaConsumer *consumer.KafkaConsumer
 }
 
 func New(cfg *config.Config) *Server {
@@ -61,8 +89,15 @@ func (s *Server) Start() error {
 		return fmt.Errorf("failed to init services: %w", err)
 	}
 
+	// 初始化消费者
+	if err := s.initConsumer(); err != nil {
+		return fmt.Errorf("failed to init consumer: %w", err)
+	}
+
 	// 设置路由
-	setupRouter(s.config, s)
+	if err := setupRouter(s.config, s); err != nil {
+		return fmt.Errorf("failed to setup router: %w", err)
+	}
 
 	// 创建gRPC服务器
 	grpcConfig := s.config.Server.GRPC
@@ -88,7 +123,7 @@ func (s *Server) Start() error {
 	// gRPC 服务端注册到 ETCD
 	reg, err := register.NewServiceRegister(&s.config.Etcd)
 	if err != nil {
-		log.Fatalf("failed to init register service: %w", err)
+		log.Fatalf("failed to init register service: %v", err)
 	}
 	// 监听续租
 	go reg.ListenKeepAlive(s.config.Etcd.Register.Ttl)
@@ -144,6 +179,9 @@ func (s *Server) waitForShutdown() {
 	if s.redisClient != nil {
 		s.redisClient.Close()
 	}
+	if s.kafkaConsumer != nil {
+		s.kafkaConsumer.Close()
+	}
 	if s.kafkaProducer != nil {
 		s.kafkaProducer.Close()
 	}
@@ -168,6 +206,12 @@ func (s *Server) initDatabase() error {
 
 	// 初始化Repository
 	s.linkRepo = linkRepo.NewMySQLRepository(mysqlDB.DB)
+	s.apiKeyRepo = apiKeyRepo.NewMySQLRepository(mysqlDB.DB)
+	s.workspaceRepo = workspaceRepo.NewMySQLRepository(mysqlDB.DB)
+	s.domainRepo = domainRepo.NewMySQLRepository(mysqlDB.DB)
+	s.tagRepo = tagRepo.NewMySQLRepository(mysqlDB.DB)
+	s.folderRepo = folderRepo.NewMySQLRepository(mysqlDB.DB)
+	s.pixelRepo = pixelRepo.NewMySQLRepository(mysqlDB.DB)
 
 	log.Printf("✅ init database success\n")
 	return nil
@@ -181,16 +225,50 @@ func (s *Server) initServices() error {
 	}
 	s.idGenerator = idGenerator
 
+	// 初始化认证服务
+	authSvc, err := auth.NewService(s.apiKeyRepo, s.idGenerator, &s.config.Auth)
+	if err != nil {
+		return fmt.Errorf("init auth service failed: %w", err)
+	}
+	s.authSvc = authSvc
+
+	// 初始化自定义域名服务
+	s.domainSvc = domainService.NewService(s.domainRepo, s.idGenerator, s.config.Server.BaseURL)
+
+	// 初始化标签和文件夹服务
+	s.tagSvc = tagService.NewService(s.tagRepo, s.idGenerator)
+	s.folderSvc = folderService.NewService(s.folderRepo, s.idGenerator)
+
+	// 初始化签名密钥环
+	signer, err := signedlink.NewKeyRing(s.config.Signing.Keys, s.config.Signing.ActiveKey)
+	if err != nil {
+		return fmt.Errorf("init signing keys failed: %w", err)
+	}
+
 	// 初始化短链服务
 	s.linkSvc = linkService.NewService(
 		s.linkRepo,
+		s.workspaceRepo,
+		s.domainRepo,
+		s.tagRepo,
+		s.folderRepo,
+		s.pixelRepo,
 		s.idGenerator,
 		linkService.Config{
-			BaseURL: s.config.Server.BaseURL,
+			BaseURL:       s.config.Server.BaseURL,
+			ReuseExisting: s.config.Link.ReuseExisting,
+			Signer:        signer,
+			MaxSignedTTL:  time.Duration(s.config.Signing.MaxTTL) * time.Second,
 		},
 		s.kafkaProducer,
 	)
 
+	// 初始化重定向像素服务，像素变更时由短链服务刷新相关链接的缓存
+	s.pixelSvc = pixelService.NewService(s.pixelRepo, s.idGenerator, s.linkSvc)
+
+	// 初始化工作空间服务，访问控制变更时由短链服务刷新工作空间内链接的缓存
+	s.workspaceSvc = workspaceService.NewService(s.workspaceRepo, s.idGenerator, s.linkSvc)
+
 	log.Println("✅ Services initialized successfully")
 	return nil
 }
@@ -214,3 +292,17 @@ func (s *Server) initMq() error {
 	s.kafkaProducer = kp
 	return nil
 }
+
+// 消费 redirect-service 发出的链接事件
+func (s *Server) initConsumer() error {
+	handlers := map[string]consumer.MessageHandler{
+		constants.TopicLinkExhausted: consumer.NewLinkExhaustedHandler(s.linkSvc),
+	}
+	kc, err := consumer.NewKafkaConsumer(&s.config.Kafka, handlers)
+	if err != nil {
+		return fmt.Errorf("init kafka consumer failed: %w", err)
+	}
+	s.kafkaConsumer = kc
+	go kc.Start()
+	return nil
+}
@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"fmt"
+	"generate-service/internal/config"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWTVerifier JWT 校验器
+type JWTVerifier struct {
+	hmacSecret []byte
+	rsaKey     *rsa.PublicKey
+	parser     *jwt.Parser
+}
+
+// NewJWTVerifier 创建JWT校验器，未配置任何密钥时返回 nil
+func NewJWTVerifier(cfg *config.JWTConfig) (*JWTVerifier, error) {
+	v := &JWTVerifier{}
+	var methods []string
+	if cfg.HS256Secret != "" {
+		v.hmacSecret = []byte(cfg.HS256Secret)
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if cfg.RS256PublicKeyFile != "" {
+		pem, err := os.ReadFile(cfg.RS256PublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read rs256 public key: %w", err)
+		}
+		key, err := jwt.ParseRSAPublicKeyFromPEM(pem)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse rs256 public key: %w", err)
+		}
+		v.rsaKey = key
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+	if len(methods) == 0 {
+		return nil, nil
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+	v.parser = jwt.NewParser(opts...)
+	return v, nil
+}
+
+// Claims 令牌中的用户信息，sub 为用户标识，role 为角色（可选）
+type Claims struct {
+	Role string `json:"role,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// Verify 校验令牌并返回其中的用户信息
+func (v *JWTVerifier) Verify(tokenString string) (*Claims, error) {
+	var claims Claims
+	_, err := v.parser.ParseWithClaims(tokenString, &claims, v.keyFunc)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("token subject is required")
+	}
+	return &claims, nil
+}
+
+// 根据签名算法选择校验密钥
+func (v *JWTVerifier) keyFunc(token *jwt.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		if v.hmacSecret == nil {
+			return nil, fmt.Errorf("hs256 is not enabled")
+		}
+		return v.hmacSecret, nil
+	case *jwt.SigningMethodRSA:
+		if v.rsaKey == nil {
+			return nil, fmt.Errorf("rs256 is not enabled")
+		}
+		return v.rsaKey, nil
+	default:
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+}
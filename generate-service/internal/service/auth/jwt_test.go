@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"generate-service/internal/config"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWTVerifierHS256(t *testing.T) {
+	verifier, err := NewJWTVerifier(&config.JWTConfig{
+		HS256Secret: "test-secret",
+		Issuer:      "short-url",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, verifier)
+
+	sign := func(secret string, claims Claims) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+		require.NoError(t, err)
+		return token
+	}
+	valid := Claims{
+		Role: "admin",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "alice",
+			Issuer:    "short-url",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+
+	// 合法令牌
+	claims, err := verifier.Verify(sign("test-secret", valid))
+	require.NoError(t, err)
+	assert.Equal(t, "alice", claims.Subject)
+	assert.Equal(t, "admin", claims.Role)
+
+	// 签名密钥错误
+	_, err = verifier.Verify(sign("other-secret", valid))
+	assert.Error(t, err)
+
+	// 已过期
+	expired := valid
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+	_, err = verifier.Verify(sign("test-secret", expired))
+	assert.Error(t, err)
+
+	// 签发者不匹配
+	wrongIssuer := valid
+	wrongIssuer.Issuer = "someone-else"
+	_, err = verifier.Verify(sign("test-secret", wrongIssuer))
+	assert.Error(t, err)
+
+	// 未启用的算法
+	none, err := jwt.NewWithClaims(jwt.SigningMethodNone, valid).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+	_, err = verifier.Verify(none)
+	assert.Error(t, err)
+}
+
+func TestNewJWTVerifierDisabled(t *testing.T) {
+	verifier, err := NewJWTVerifier(&config.JWTConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, verifier)
+}
@@ -0,0 +1,62 @@
+package auth
+
+import "context"
+
+const (
+	TypeAPIKey = "api_key"
+	TypeJWT    = "jwt"
+)
+
+// Principal 已认证的调用方
+type Principal struct {
+	UserID      string
+	Role        Role   // 进入工作空间后为该空间内的有效角色
+	AuthType    string // api_key 或 jwt
+	KeyID       uint64 // 仅 API 密钥认证时有值
+	WorkspaceID uint64 // 当前工作空间，0表示未进入任何工作空间
+	MemberRole  Role   // 在当前工作空间中的成员角色
+}
+
+// InWorkspace 返回限定在指定工作空间内的调用方副本，角色取凭证角色与成员角色中较低者
+func (p *Principal) InWorkspace(workspaceID uint64, memberRole Role) *Principal {
+	scoped := *p
+	scoped.WorkspaceID = workspaceID
+	scoped.Role = MinRole(p.Role, memberRole)
+	scoped.MemberRole = memberRole
+	return &scoped
+}
+
+// ManagementRole 管理工作空间（成员、访问控制）时使用的角色。用户令牌按成员角色，
+// 不受默认角色限制；API 密钥是显式限定了权限的凭证，仍取两者中较低者
+func (p *Principal) ManagementRole() Role {
+	if p.AuthType == TypeJWT {
+		return p.MemberRole
+	}
+	return p.Role
+}
+
+// IsAdmin 是否为管理员
+func (p *Principal) IsAdmin() bool {
+	return p.Role == RoleAdmin
+}
+
+// CanAccess 检查调用方是否具备所需角色，且为资源所有者或管理员
+func (p *Principal) CanAccess(ownerID string, required Role) bool {
+	if !p.Role.AtLeast(required) {
+		return false
+	}
+	return p.IsAdmin() || p.UserID == ownerID
+}
+
+type principalKey struct{}
+
+// WithPrincipal 将调用方信息写入上下文
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext 从上下文获取调用方信息
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrincipalInWorkspace(t *testing.T) {
+	p := &Principal{UserID: "alice", Role: RoleEditor}
+
+	// 有效角色取凭证角色与成员角色中较低者
+	scoped := p.InWorkspace(42, RoleAdmin)
+	assert.Equal(t, uint64(42), scoped.WorkspaceID)
+	assert.Equal(t, RoleEditor, scoped.Role)
+	assert.Equal(t, RoleViewer, p.InWorkspace(42, RoleViewer).Role)
+
+	// 原对象不受影响
+	assert.Equal(t, uint64(0), p.WorkspaceID)
+
+	assert.True(t, scoped.CanAccess("alice", RoleEditor))
+	assert.False(t, scoped.CanAccess("bob", RoleViewer))
+	assert.True(t, (&Principal{UserID: "root", Role: RoleAdmin}).CanAccess("bob", RoleEditor))
+}
+
+func TestPrincipalManagementRole(t *testing.T) {
+	// 以默认编辑者角色登录的工作空间创建者仍可管理成员
+	creator := (&Principal{UserID: "alice", Role: RoleEditor, AuthType: TypeJWT}).InWorkspace(42, RoleAdmin)
+	assert.Equal(t, RoleEditor, creator.Role)
+	assert.Equal(t, RoleAdmin, creator.ManagementRole())
+
+	// 用户令牌不能超出成员角色
+	member := (&Principal{UserID: "bob", Role: RoleAdmin, AuthType: TypeJWT}).InWorkspace(42, RoleViewer)
+	assert.Equal(t, RoleViewer, member.ManagementRole())
+
+	// API 密钥受自身角色限制
+	key := (&Principal{UserID: "alice", Role: RoleEditor, AuthType: TypeAPIKey}).InWorkspace(42, RoleAdmin)
+	assert.Equal(t, RoleEditor, key.ManagementRole())
+}
@@ -0,0 +1,41 @@
+package auth
+
+// Role 用户角色，权限依次递增：viewer < editor < admin
+type Role string
+
+const (
+	RoleViewer Role = "viewer" // 只读自己的链接
+	RoleEditor Role = "editor" // 创建、修改、删除自己的链接
+	RoleAdmin  Role = "admin"  // 管理所有链接
+)
+
+func (r Role) level() int {
+	switch r {
+	case RoleViewer:
+		return 1
+	case RoleEditor:
+		return 2
+	case RoleAdmin:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// IsValid 检查角色是否合法
+func (r Role) IsValid() bool {
+	return r.level() > 0
+}
+
+// AtLeast 检查角色是否不低于指定角色
+func (r Role) AtLeast(required Role) bool {
+	return r.level() >= required.level()
+}
+
+// MinRole 返回两个角色中权限较低者
+func MinRole(a, b Role) Role {
+	if a.level() <= b.level() {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,331 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	stderrors "errors"
+	"generate-service/internal/model"
+	"generate-service/internal/pkg/errors"
+	domainRepo "generate-service/internal/repository/domain"
+	"generate-service/internal/service/auth"
+	"generate-service/internal/service/idgen"
+	"net"
+	"net/url"
+	"shared/hostname"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Service 自定义域名服务接口，操作范围限定在当前工作空间
+type Service interface {
+	AddDomain(ctx context.Context, req *model.CreateDomainRequest) (*model.DomainResponse, error)
+	ListDomains(ctx context.Context) (*model.ListDomainsResponse, error)
+	RemoveDomain(ctx context.Context, id string) error
+
+	// VerifyDomain 检查 DNS TXT 记录，验证通过后域名才能用于创建链接和跳转
+	VerifyDomain(ctx context.Context, id string) (*model.DomainResponse, error)
+	UpdateAppLinks(ctx context.Context, id string, req *model.UpdateAppLinksRequest) (*model.DomainResponse, error)
+
+	// AppleAppSiteAssociation 和 AssetLinks 按主机名生成 App 关联文档，无需登录
+	AppleAppSiteAssociation(ctx context.Context, host string) (*model.AppleAppSiteAssociation, error)
+	AssetLinks(ctx context.Context, host string) ([]model.AssetLinkStatement, error)
+}
+
+// 所有权验证记录：在 _shorturl-verify.<域名> 发布值为 shorturl-verify=<令牌> 的 TXT 记录
+const (
+	verificationRecordPrefix = "_shorturl-verify."
+	verificationValuePrefix  = "shorturl-verify="
+)
+
+// txtResolver 查询 DNS TXT 记录，*net.Resolver 满足该接口
+type txtResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+type domainService struct {
+	domainRepo  domainRepo.Repository
+	idGenerator idgen.Generator
+	resolver    txtResolver
+	defaultHost string
+}
+
+// NewService 创建域名服务实例，baseURL 对应的默认域名不可被注册
+func NewService(repo domainRepo.Repository, idGenerator idgen.Generator, baseURL string) Service {
+	var defaultHost string
+	if u, err := url.Parse(baseURL); err == nil {
+		defaultHost = hostname.Normalize(u.Host)
+	}
+	return &domainService{
+		domainRepo:  repo,
+		idGenerator: idGenerator,
+		resolver:    net.DefaultResolver,
+		defaultHost: defaultHost,
+	}
+}
+
+// AddDomain 为当前工作空间注册域名，仅管理员可操作。
+// 新域名处于未验证状态，需发布 DNS TXT 记录并调用 VerifyDomain 后才能使用
+func (s *domainService) AddDomain(ctx context.Context, req *model.CreateDomainRequest) (*model.DomainResponse, error) {
+	p, err := requireRole(ctx, auth.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	host := hostname.Normalize(req.Hostname)
+	if !hostname.IsValid(host) || host == s.defaultHost {
+		return nil, errors.ErrInvalidDomain
+	}
+	id, err := s.idGenerator.NextId()
+	if err != nil {
+		return nil, err
+	}
+	token, err := generateVerificationToken()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	domain := &model.Domain{
+		ID:                id,
+		WorkspaceID:       p.WorkspaceID,
+		Hostname:          host,
+		VerificationToken: token,
+		CreatedAt:         now,
+		CreatedBy:         p.UserID,
+		UpdatedAt:         now,
+		UpdatedBy:         p.UserID,
+		DeleteFlag:        "N",
+	}
+	if err := s.domainRepo.Create(ctx, domain); err != nil {
+		return nil, err
+	}
+	resp := toDomainResponse(domain)
+	return &resp, nil
+}
+
+// ListDomains 查询当前工作空间的域名
+func (s *domainService) ListDomains(ctx context.Context) (*model.ListDomainsResponse, error) {
+	p, err := requireRole(ctx, auth.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	domains, err := s.domainRepo.ListByWorkspace(ctx, p.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &model.ListDomainsResponse{Domains: make([]model.DomainResponse, len(domains))}
+	for i := range domains {
+		resp.Domains[i] = toDomainResponse(&domains[i])
+	}
+	return resp, nil
+}
+
+// RemoveDomain 删除域名，域名下仍有链接时拒绝删除
+func (s *domainService) RemoveDomain(ctx context.Context, id string) error {
+	p, err := requireRole(ctx, auth.RoleAdmin)
+	if err != nil {
+		return err
+	}
+	domain, err := s.findDomain(ctx, p, id)
+	if err != nil {
+		return err
+	}
+	return s.domainRepo.Delete(ctx, domain)
+}
+
+// VerifyDomain 验证域名所有权，仅管理员可操作。
+// 同一主机名只能被一个工作空间验证，已被其他工作空间验证时返回 ErrDomainExists
+func (s *domainService) VerifyDomain(ctx context.Context, id string) (*model.DomainResponse, error) {
+	p, err := requireRole(ctx, auth.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	domain, err := s.findDomain(ctx, p, id)
+	if err != nil {
+		return nil, err
+	}
+	if domain.VerifiedAt == nil {
+		records, err := s.resolver.LookupTXT(ctx, verificationRecordPrefix+domain.Hostname)
+		if err != nil {
+			var dnsErr *net.DNSError
+			if !stderrors.As(err, &dnsErr) || !dnsErr.IsNotFound {
+				return nil, err
+			}
+		}
+		if !hasVerificationRecord(records, domain.VerificationToken) {
+			return nil, errors.ErrDomainUnverified
+		}
+		domain.UpdatedBy = p.UserID
+		if err := s.domainRepo.Verify(ctx, domain); err != nil {
+			return nil, err
+		}
+	}
+	resp := toDomainResponse(domain)
+	return &resp, nil
+}
+
+// UpdateAppLinks 设置域名关联的 iOS 和 Android App，仅管理员可操作
+func (s *domainService) UpdateAppLinks(ctx context.Context, id string, req *model.UpdateAppLinksRequest) (*model.DomainResponse, error) {
+	p, err := requireRole(ctx, auth.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	domain, err := s.findDomain(ctx, p, id)
+	if err != nil {
+		return nil, err
+	}
+	appIDs, androidApps, err := normalizeAppLinks(req)
+	if err != nil {
+		return nil, err
+	}
+	domain.AppleAppIDs = appIDs
+	domain.AndroidApps = androidApps
+	domain.UpdatedBy = p.UserID
+	if err := s.domainRepo.Update(ctx, domain); err != nil {
+		return nil, err
+	}
+	resp := toDomainResponse(domain)
+	return &resp, nil
+}
+
+// AppleAppSiteAssociation 生成 apple-app-site-association，关联 App 可处理该域名下的所有路径。
+// 未验证或未关联 iOS App 的域名返回 ErrDomainNotFound
+func (s *domainService) AppleAppSiteAssociation(ctx context.Context, host string) (*model.AppleAppSiteAssociation, error) {
+	domain, err := s.domainRepo.FindByHostname(ctx, hostname.Normalize(host))
+	if err != nil {
+		return nil, err
+	}
+	if len(domain.AppleAppIDs) == 0 {
+		return nil, errors.ErrDomainNotFound
+	}
+	doc := &model.AppleAppSiteAssociation{
+		AppLinks: model.AppleAppLinks{
+			Apps:    []string{},
+			Details: make([]model.AppleAppDetails, len(domain.AppleAppIDs)),
+		},
+	}
+	for i, appID := range domain.AppleAppIDs {
+		doc.AppLinks.Details[i] = model.AppleAppDetails{AppID: appID, Paths: []string{"*"}}
+	}
+	return doc, nil
+}
+
+// AssetLinks 生成 assetlinks.json，未验证或未关联 Android App 的域名返回 ErrDomainNotFound
+func (s *domainService) AssetLinks(ctx context.Context, host string) ([]model.AssetLinkStatement, error) {
+	domain, err := s.domainRepo.FindByHostname(ctx, hostname.Normalize(host))
+	if err != nil {
+		return nil, err
+	}
+	if len(domain.AndroidApps) == 0 {
+		return nil, errors.ErrDomainNotFound
+	}
+	statements := make([]model.AssetLinkStatement, len(domain.AndroidApps))
+	for i, app := range domain.AndroidApps {
+		statements[i] = model.AssetLinkStatement{
+			Relation: []string{"delegate_permission/common.handle_all_urls"},
+			Target: model.AssetLinkTarget{
+				Namespace:              "android_app",
+				PackageName:            app.PackageName,
+				SHA256CertFingerprints: app.Fingerprints,
+			},
+		}
+	}
+	return statements, nil
+}
+
+// 查询当前工作空间的域名，其他工作空间的域名对外表现为不存在
+func (s *domainService) findDomain(ctx context.Context, p *auth.Principal, id string) (*model.Domain, error) {
+	domainID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil, errors.ErrDomainNotFound
+	}
+	domain, err := s.domainRepo.FindByID(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+	if domain.WorkspaceID != p.WorkspaceID {
+		return nil, errors.ErrDomainNotFound
+	}
+	return domain, nil
+}
+
+// 校验 App ID、包名和证书指纹，指纹统一为大写，空列表返回 nil 以清除对应列
+func normalizeAppLinks(req *model.UpdateAppLinksRequest) ([]string, []model.AndroidApp, error) {
+	var appIDs []string
+	for _, appID := range req.AppleAppIDs {
+		appID = strings.TrimSpace(appID)
+		if !model.ValidAppleAppID(appID) {
+			return nil, nil, errors.ErrInvalidAppLinks
+		}
+		appIDs = append(appIDs, appID)
+	}
+	var apps []model.AndroidApp
+	for _, app := range req.AndroidApps {
+		if !model.ValidAndroidPackage(app.PackageName) || len(app.Fingerprints) == 0 {
+			return nil, nil, errors.ErrInvalidAppLinks
+		}
+		fingerprints := make([]string, len(app.Fingerprints))
+		for i, fp := range app.Fingerprints {
+			fingerprints[i] = strings.ToUpper(strings.TrimSpace(fp))
+			if !model.ValidCertFingerprint(fingerprints[i]) {
+				return nil, nil, errors.ErrInvalidAppLinks
+			}
+		}
+		apps = append(apps, model.AndroidApp{PackageName: app.PackageName, Fingerprints: fingerprints})
+	}
+	return appIDs, apps, nil
+}
+
+// 检查 TXT 记录中是否包含验证令牌
+func hasVerificationRecord(records []string, token string) bool {
+	if token == "" {
+		return false
+	}
+	for _, record := range records {
+		if strings.TrimSpace(record) == verificationValuePrefix+token {
+			return true
+		}
+	}
+	return false
+}
+
+func generateVerificationToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func requireRole(ctx context.Context, required auth.Role) (*auth.Principal, error) {
+	p, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, errors.ErrUnauthorized
+	}
+	if p.WorkspaceID == 0 {
+		return nil, errors.ErrWorkspaceRequired
+	}
+	if !p.Role.AtLeast(required) {
+		return nil, errors.ErrForbidden
+	}
+	return p, nil
+}
+
+func toDomainResponse(d *model.Domain) model.DomainResponse {
+	resp := model.DomainResponse{
+		ID:          strconv.FormatUint(d.ID, 10),
+		Hostname:    d.Hostname,
+		Verified:    d.VerifiedAt != nil,
+		AppleAppIDs: d.AppleAppIDs,
+		AndroidApps: d.AndroidApps,
+		CreatedAt:   d.CreatedAt,
+	}
+	if !resp.Verified {
+		resp.Verification = &model.DomainVerification{
+			Type:  "TXT",
+			Name:  verificationRecordPrefix + d.Hostname,
+			Value: verificationValuePrefix + d.VerificationToken,
+		}
+	}
+	return resp
+}
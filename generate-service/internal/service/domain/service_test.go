@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHasVerificationRecord(t *testing.T) {
+	token := "0123456789abcdef0123456789abcdef"
+	assert.True(t, hasVerificationRecord([]string{"v=spf1 -all", "shorturl-verify=" + token}, token))
+	assert.True(t, hasVerificationRecord([]string{" shorturl-verify=" + token + " "}, token))
+
+	assert.False(t, hasVerificationRecord(nil, token))
+	assert.False(t, hasVerificationRecord([]string{token}, token))
+	assert.False(t, hasVerificationRecord([]string{"shorturl-verify=" + token + "0"}, token))
+	// 未生成令牌的域名不能被空记录验证
+	assert.False(t, hasVerificationRecord([]string{"shorturl-verify="}, ""))
+}
@@ -0,0 +1,181 @@
+package folder
+
+import (
+	"context"
+	"generate-service/internal/model"
+	"generate-service/internal/pkg/errors"
+	folderRepo "generate-service/internal/repository/folder"
+	"generate-service/internal/service/auth"
+	"generate-service/internal/service/idgen"
+	"strconv"
+	"time"
+)
+
+// Service 文件夹服务接口，操作范围限定在当前工作空间
+type Service interface {
+	CreateFolder(ctx context.Context, req *model.CreateFolderRequest) (*model.FolderResponse, error)
+	ListFolders(ctx context.Context) (*model.ListFoldersResponse, error)
+	UpdateFolder(ctx context.Context, id string, req *model.UpdateFolderRequest) (*model.FolderResponse, error)
+	DeleteFolder(ctx context.Context, id string) error
+}
+
+type folderService struct {
+	folderRepo  folderRepo.Repository
+	idGenerator idgen.Generator
+}
+
+// NewService 创建文件夹服务实例
+func NewService(repo folderRepo.Repository, idGenerator idgen.Generator) Service {
+	return &folderService{
+		folderRepo:  repo,
+		idGenerator: idGenerator,
+	}
+}
+
+// CreateFolder 在当前工作空间创建文件夹
+func (s *folderService) CreateFolder(ctx context.Context, req *model.CreateFolderRequest) (*model.FolderResponse, error) {
+	p, err := requireRole(ctx, auth.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	var parentID uint64
+	if req.ParentID != nil && *req.ParentID != "" {
+		parent, err := s.findFolder(ctx, p.WorkspaceID, *req.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		parentID = parent.ID
+	}
+	id, err := s.idGenerator.NextId()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	folder := &model.Folder{
+		ID:          id,
+		WorkspaceID: p.WorkspaceID,
+		ParentID:    parentID,
+		Name:        req.Name,
+		CreatedAt:   now,
+		CreatedBy:   p.UserID,
+		UpdatedAt:   now,
+		UpdatedBy:   p.UserID,
+		DeleteFlag:  "N",
+	}
+	if err := s.folderRepo.Create(ctx, folder); err != nil {
+		return nil, err
+	}
+	resp := toFolderResponse(folder)
+	return &resp, nil
+}
+
+// ListFolders 查询当前工作空间的文件夹
+func (s *folderService) ListFolders(ctx context.Context) (*model.ListFoldersResponse, error) {
+	p, err := requireRole(ctx, auth.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	folders, err := s.folderRepo.ListByWorkspace(ctx, p.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &model.ListFoldersResponse{Folders: make([]model.FolderResponse, len(folders))}
+	for i := range folders {
+		resp.Folders[i] = toFolderResponse(&folders[i])
+	}
+	return resp, nil
+}
+
+// UpdateFolder 重命名或移动文件夹，不能移动到自身或子孙文件夹下
+func (s *folderService) UpdateFolder(ctx context.Context, id string, req *model.UpdateFolderRequest) (*model.FolderResponse, error) {
+	p, err := requireRole(ctx, auth.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	folder, err := s.findFolder(ctx, p.WorkspaceID, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Name != nil {
+		folder.Name = *req.Name
+	}
+	if req.ParentID != nil {
+		if *req.ParentID == "" {
+			folder.ParentID = 0
+		} else {
+			parent, err := s.findFolder(ctx, p.WorkspaceID, *req.ParentID)
+			if err != nil {
+				return nil, err
+			}
+			folders, err := s.folderRepo.ListByWorkspace(ctx, p.WorkspaceID)
+			if err != nil {
+				return nil, err
+			}
+			if isDescendant(folders, folder.ID, parent.ID) {
+				return nil, errors.ErrInvalidFolderParent
+			}
+			folder.ParentID = parent.ID
+		}
+	}
+	folder.UpdatedBy = p.UserID
+	if err := s.folderRepo.Update(ctx, folder); err != nil {
+		return nil, err
+	}
+	resp := toFolderResponse(folder)
+	return &resp, nil
+}
+
+// DeleteFolder 删除文件夹，仅管理员可操作，文件夹中仍有链接或子文件夹时拒绝删除
+func (s *folderService) DeleteFolder(ctx context.Context, id string) error {
+	p, err := requireRole(ctx, auth.RoleAdmin)
+	if err != nil {
+		return err
+	}
+	folder, err := s.findFolder(ctx, p.WorkspaceID, id)
+	if err != nil {
+		return err
+	}
+	return s.folderRepo.Delete(ctx, folder)
+}
+
+// 查询当前工作空间的文件夹，其他工作空间的文件夹对外表现为不存在
+func (s *folderService) findFolder(ctx context.Context, workspaceID uint64, id string) (*model.Folder, error) {
+	folderID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil, errors.ErrFolderNotFound
+	}
+	folder, err := s.folderRepo.FindByID(ctx, folderID)
+	if err != nil {
+		return nil, err
+	}
+	if folder.WorkspaceID != workspaceID {
+		return nil, errors.ErrFolderNotFound
+	}
+	return folder, nil
+}
+
+func requireRole(ctx context.Context, required auth.Role) (*auth.Principal, error) {
+	p, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, errors.ErrUnauthorized
+	}
+	if p.WorkspaceID == 0 {
+		return nil, errors.ErrWorkspaceRequired
+	}
+	if !p.Role.AtLeast(required) {
+		return nil, errors.ErrForbidden
+	}
+	return p, nil
+}
+
+func toFolderResponse(f *model.Folder) model.FolderResponse {
+	resp := model.FolderResponse{
+		ID:        strconv.FormatUint(f.ID, 10),
+		Name:      f.Name,
+		CreatedAt: f.CreatedAt,
+	}
+	if f.ParentID != 0 {
+		resp.ParentID = strconv.FormatUint(f.ParentID, 10)
+	}
+	return resp
+}
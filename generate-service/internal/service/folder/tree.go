@@ -0,0 +1,26 @@
+package folder
+
+import "generate-service/internal/model"
+
+// Descendants 返回 rootID 及其所有子孙文件夹的ID
+func Descendants(folders []model.Folder, rootID uint64) []uint64 {
+	children := make(map[uint64][]uint64, len(folders))
+	for _, f := range folders {
+		children[f.ParentID] = append(children[f.ParentID], f.ID)
+	}
+	ids := []uint64{rootID}
+	for i := 0; i < len(ids); i++ {
+		ids = append(ids, children[ids[i]]...)
+	}
+	return ids
+}
+
+// 检查 id 是否是 ancestorID 自身或其子孙文件夹
+func isDescendant(folders []model.Folder, ancestorID, id uint64) bool {
+	for _, d := range Descendants(folders, ancestorID) {
+		if d == id {
+			return true
+		}
+	}
+	return false
+}
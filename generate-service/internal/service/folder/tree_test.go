@@ -0,0 +1,23 @@
+package folder
+
+import (
+	"generate-service/internal/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescendants(t *testing.T) {
+	// 1 -> 2 -> 3, 1 -> 4, 5
+	folders := []model.Folder{
+		{ID: 1}, {ID: 2, ParentID: 1}, {ID: 3, ParentID: 2}, {ID: 4, ParentID: 1}, {ID: 5},
+	}
+	assert.ElementsMatch(t, []uint64{1, 2, 3, 4}, Descendants(folders, 1))
+	assert.ElementsMatch(t, []uint64{2, 3}, Descendants(folders, 2))
+	assert.Equal(t, []uint64{5}, Descendants(folders, 5))
+
+	// 不能移动到自身或子孙文件夹下
+	assert.True(t, isDescendant(folders, 1, 3))
+	assert.True(t, isDescendant(folders, 2, 2))
+	assert.False(t, isDescendant(folders, 2, 4))
+}
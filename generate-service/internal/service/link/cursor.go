@@ -0,0 +1,54 @@
+package link
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"generate-service/internal/model"
+	"generate-service/internal/pkg/errors"
+	linkRepo "generate-service/internal/repository/link"
+	"time"
+)
+
+// 游标内容，编码为 base64 后对客户端不透明
+type cursorToken struct {
+	Sort  linkRepo.SortField `json:"s"`
+	Desc  bool               `json:"d,omitempty"`
+	Time  *time.Time         `json:"t,omitempty"`
+	Count int64              `json:"c,omitempty"`
+	ID    uint64             `json:"i"`
+}
+
+// 根据当前页最后一条记录生成下一页游标
+func encodeCursor(sort linkRepo.ListSort, last *model.Link) string {
+	token := cursorToken{Sort: sort.Field, Desc: sort.Desc, ID: last.ID}
+	switch sort.Field {
+	case linkRepo.SortByClickCount:
+		token.Count = last.ClickCount
+	case linkRepo.SortByExpiresAt:
+		token.Time = last.ExpiresAt
+	default:
+		createdAt := last.CreatedAt
+		token.Time = &createdAt
+	}
+	data, _ := json.Marshal(token)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// 解析游标，游标的排序方式必须与本次请求一致
+func decodeCursor(s string, sort linkRepo.ListSort) (*linkRepo.Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.ErrInvalidCursor
+	}
+	var token cursorToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, errors.ErrInvalidCursor
+	}
+	if token.Sort != sort.Field || token.Desc != sort.Desc || token.ID == 0 {
+		return nil, errors.ErrInvalidCursor
+	}
+	if token.Sort == linkRepo.SortByCreatedAt && token.Time == nil {
+		return nil, errors.ErrInvalidCursor
+	}
+	return &linkRepo.Cursor{Time: token.Time, Count: token.Count, ID: token.ID}, nil
+}
@@ -0,0 +1,47 @@
+package link
+
+import (
+	"generate-service/internal/model"
+	"generate-service/internal/pkg/errors"
+	linkRepo "generate-service/internal/repository/link"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	link := &model.Link{ID: 42, CreatedAt: createdAt, ClickCount: 7}
+
+	sort := linkRepo.ListSort{Field: linkRepo.SortByCreatedAt, Desc: true}
+	cursor, err := decodeCursor(encodeCursor(sort, link), sort)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(42), cursor.ID)
+	require.NotNil(t, cursor.Time)
+	assert.True(t, cursor.Time.Equal(createdAt))
+
+	sort = linkRepo.ListSort{Field: linkRepo.SortByClickCount}
+	cursor, err = decodeCursor(encodeCursor(sort, link), sort)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), cursor.Count)
+
+	// 永不过期的链接游标不带时间
+	sort = linkRepo.ListSort{Field: linkRepo.SortByExpiresAt}
+	cursor, err = decodeCursor(encodeCursor(sort, link), sort)
+	require.NoError(t, err)
+	assert.Nil(t, cursor.Time)
+}
+
+func TestDecodeCursorRejectsMismatch(t *testing.T) {
+	link := &model.Link{ID: 1, CreatedAt: time.Now()}
+	token := encodeCursor(linkRepo.ListSort{Field: linkRepo.SortByCreatedAt, Desc: true}, link)
+
+	_, err := decodeCursor(token, linkRepo.ListSort{Field: linkRepo.SortByCreatedAt})
+	assert.Equal(t, errors.ErrInvalidCursor, err)
+	_, err = decodeCursor(token, linkRepo.ListSort{Field: linkRepo.SortByClickCount, Desc: true})
+	assert.Equal(t, errors.ErrInvalidCursor, err)
+	_, err = decodeCursor("not-a-cursor!", linkRepo.ListSort{Field: linkRepo.SortByCreatedAt})
+	assert.Equal(t, errors.ErrInvalidCursor, err)
+}
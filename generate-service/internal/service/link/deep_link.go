@@ -0,0 +1,59 @@
+package link
+
+import (
+	"generate-service/internal/model"
+	"generate-service/internal/pkg/errors"
+	"net/url"
+	"strings"
+)
+
+const playStoreURL = "https://play.google.com/store/apps/details?id="
+
+// 校验并规范化深度链接：App 地址必须带 scheme，Android App 地址必须指定包名，
+// 未设置 Google Play 地址时按包名生成。未配置任何平台时返回 nil
+func (s *linkService) normalizeDeepLink(deepLink *model.DeepLink) (*model.DeepLink, error) {
+	if deepLink == nil || deepLink.IsEmpty() {
+		return nil, nil
+	}
+	dl := *deepLink
+	dl.IOSURL = strings.TrimSpace(dl.IOSURL)
+	dl.AndroidURL = strings.TrimSpace(dl.AndroidURL)
+	dl.AndroidPackage = strings.TrimSpace(dl.AndroidPackage)
+	if dl.IOSURL != "" && !validAppURL(dl.IOSURL) {
+		return nil, errors.ErrInvalidDeepLink
+	}
+	if dl.AndroidURL != "" && (!validAppURL(dl.AndroidURL) || dl.AndroidPackage == "") {
+		return nil, errors.ErrInvalidDeepLink
+	}
+	if dl.AndroidPackage != "" {
+		if !model.ValidAndroidPackage(dl.AndroidPackage) {
+			return nil, errors.ErrInvalidDeepLink
+		}
+		if dl.AndroidStoreURL == "" {
+			dl.AndroidStoreURL = playStoreURL + dl.AndroidPackage
+		}
+	}
+	for _, store := range []*string{&dl.IOSStoreURL, &dl.AndroidStoreURL} {
+		if *store == "" {
+			continue
+		}
+		if err := s.ValidateURL(*store); err != nil {
+			return nil, err
+		}
+		normalized, err := s.NormalizeURL(*store)
+		if err != nil {
+			return nil, err
+		}
+		*store = normalized
+	}
+	return &dl, nil
+}
+
+// App 地址需为 scheme://path 形式，intent:// 由 redirect-service 生成，不允许直接设置
+func validAppURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" || strings.EqualFold(u.Scheme, "intent") || strings.EqualFold(u.Scheme, "javascript") {
+		return false
+	}
+	return strings.HasPrefix(raw[len(u.Scheme):], "://")
+}
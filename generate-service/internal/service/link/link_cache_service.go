@@ -1,8 +1,10 @@
 package link
 
 import (
+	"context"
 	"generate-service/internal/model"
 	"log"
+	"strconv"
 	"time"
 
 	"shared/constants"
@@ -11,18 +13,43 @@ import (
 // 异步发送缓存预热消息
 func (s *linkService) sendWarmupAsync(link *model.Link) {
 	go func() {
+		workspaceIPAccess, err := s.workspaceIPAccess(context.Background(), link.WorkspaceID)
+		if err != nil {
+			// 缺少工作空间的访问控制会放行本应拒绝的访问，不预热，由 redirect-service 回源
+			log.Printf("Failed to load workspace ip access: %v", err)
+			return
+		}
 		eventID, _ := s.idGenerator.NextId()
 		msg := model.CacheWarmupMessage{
 			BaseMessage: model.BaseMessage{
-				EventID:   string(eventID),
+				EventID:   strconv.FormatUint(eventID, 10),
 				EventType: "cache_warmup",
 				Timestamp: time.Now(),
 				Source:    "generate_service",
 			},
-			ShortCode:   link.ShortCode,
-			OriginalURL: link.LongURL,
-			ExpiredAt:   link.ExpiresAt,
-			LogID:       link.ID,
+			WorkspaceID:   link.WorkspaceID,
+			Domain:        link.Domain,
+			ShortCode:     link.ShortCode,
+			OriginalURL:   link.LongURL,
+			ActivateAt:    link.ActivateAt,
+			ExpiredAt:     link.ExpiresAt,
+			LogID:         link.ID,
+			PasswordHash:  link.PasswordHash,
+			MaxClicks:     link.MaxClicks,
+			Rules:         link.Rules,
+			Variants:      link.Variants,
+			DeepLink:      link.DeepLink,
+			OpenGraph:     link.OpenGraph,
+			IPAccess:      link.IPAccess,
+			ForwardQuery:  link.ForwardQuery,
+			QueryConflict: link.QueryConflict,
+			ForwardPath:   link.ForwardPath,
+			Template:      link.Template,
+			RedirectType:  link.RedirectType,
+			SignedOnly:    link.SignedOnly,
+			Pixels:        retargetingPixels(link.Pixels),
+
+			WorkspaceIPAccess: workspaceIPAccess,
 		}
 		if err := s.kafkaProducer.SendMessage(constants.TopicCacheWarmup, msg); err != nil {
 			log.Printf("Failed to send cache warmup message: %v", err)
@@ -32,20 +59,47 @@ func (s *linkService) sendWarmupAsync(link *model.Link) {
 }
 
 // 异步发送缓存更新消息
-func (s *linkService) sendCacheUpdateAsync(link *model.Link) {
+func (s *linkService) sendCacheUpdateAsync(link *model.Link, resetClicks bool) {
 	go func() {
+		workspaceIPAccess, err := s.workspaceIPAccess(context.Background(), link.WorkspaceID)
+		if err != nil {
+			// 缺少工作空间的访问控制会放行本应拒绝的访问，删除缓存，由 redirect-service 回源
+			log.Printf("Failed to load workspace ip access: %v", err)
+			s.sendCacheDeleteAsync(link, model.DeleteReasonStale, resetClicks)
+			return
+		}
 		eventID, _ := s.idGenerator.NextId()
 		msg := model.CacheUpdateMessage{
 			BaseMessage: model.BaseMessage{
-				EventID:   string(eventID),
+				EventID:   strconv.FormatUint(eventID, 10),
 				EventType: "cache_warmup",
 				Timestamp: time.Now(),
 				Source:    "generate_service",
 			},
-			ShortCode:   link.ShortCode,
-			OriginalURL: link.LongURL,
-			ExpiredAt:   link.ExpiresAt,
-			Status:      link.Status,
+			WorkspaceID:   link.WorkspaceID,
+			Domain:        link.Domain,
+			ShortCode:     link.ShortCode,
+			OriginalURL:   link.LongURL,
+			ActivateAt:    link.ActivateAt,
+			ExpiredAt:     link.ExpiresAt,
+			Status:        link.Status,
+			PasswordHash:  link.PasswordHash,
+			MaxClicks:     link.MaxClicks,
+			Rules:         link.Rules,
+			Variants:      link.Variants,
+			DeepLink:      link.DeepLink,
+			OpenGraph:     link.OpenGraph,
+			IPAccess:      link.IPAccess,
+			ForwardQuery:  link.ForwardQuery,
+			QueryConflict: link.QueryConflict,
+			ForwardPath:   link.ForwardPath,
+			Template:      link.Template,
+			RedirectType:  link.RedirectType,
+			SignedOnly:    link.SignedOnly,
+			Pixels:        retargetingPixels(link.Pixels),
+			ResetClicks:   resetClicks,
+
+			WorkspaceIPAccess: workspaceIPAccess,
 		}
 		if err := s.kafkaProducer.SendMessage(constants.TopicCacheUpdate, msg); err != nil {
 			log.Printf("Failed to send cache warmup message: %v", err)
@@ -54,18 +108,22 @@ func (s *linkService) sendCacheUpdateAsync(link *model.Link) {
 	}()
 }
 
-// 异步发送缓存更新消息
-func (s *linkService) sendCacheDeleteAsync(link *model.Link) {
+// 异步发送缓存删除消息
+func (s *linkService) sendCacheDeleteAsync(link *model.Link, reason string, resetClicks bool) {
 	go func() {
 		eventID, _ := s.idGenerator.NextId()
 		msg := model.CacheDeleteMessage{
 			BaseMessage: model.BaseMessage{
-				EventID:   string(eventID),
+				EventID:   strconv.FormatUint(eventID, 10),
 				EventType: "cache_warmup",
 				Timestamp: time.Now(),
 				Source:    "generate_service",
 			},
-			ShortCode: link.ShortCode,
+			WorkspaceID: link.WorkspaceID,
+			Domain:      link.Domain,
+			ShortCode:   link.ShortCode,
+			Reason:      reason,
+			ResetClicks: resetClicks,
 		}
 		if err := s.kafkaProducer.SendMessage(constants.TopicCacheDelete, msg); err != nil {
 			log.Printf("Failed to send cache warmup message: %v", err)
@@ -73,3 +131,24 @@ func (s *linkService) sendCacheDeleteAsync(link *model.Link) {
 		}
 	}()
 }
+
+// 转换为缓存消息中的像素快照
+func retargetingPixels(pixels []model.Pixel) []model.RetargetingPixel {
+	if len(pixels) == 0 {
+		return nil
+	}
+	snapshot := make([]model.RetargetingPixel, len(pixels))
+	for i, p := range pixels {
+		snapshot[i] = model.RetargetingPixel{Provider: p.Provider, TrackingID: p.TrackingID}
+	}
+	return snapshot
+}
+
+// 查询工作空间的IP访问控制列表，随链接一起下发到 redirect-service
+func (s *linkService) workspaceIPAccess(ctx context.Context, workspaceID uint64) (*model.IPAccess, error) {
+	ws, err := s.workspaceRepo.FindByID(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	return ws.Settings.IPAccess, nil
+}
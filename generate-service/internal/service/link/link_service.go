@@ -2,38 +2,139 @@ package link
 
 import (
 	"context"
-	"fmt"
 	"generate-service/internal/model"
 	"generate-service/internal/pkg/errors"
 	"generate-service/internal/pkg/mq"
+	domainRepo "generate-service/internal/repository/domain"
+	folderRepo "generate-service/internal/repository/folder"
 	linkRepo "generate-service/internal/repository/link"
+	pixelRepo "generate-service/internal/repository/pixel"
+	tagRepo "generate-service/internal/repository/tag"
+	workspaceRepo "generate-service/internal/repository/workspace"
+	"generate-service/internal/service/auth"
+	folderService "generate-service/internal/service/folder"
 	"generate-service/internal/service/idgen"
+	"shared/hostname"
+	"shared/signedlink"
+	"strconv"
+	"strings"
 	"time"
+
+	"golang.org/x/crypto/bcrypt"
 )
 
 type linkService struct {
 	linkRepo      linkRepo.Repository
+	workspaceRepo workspaceRepo.Repository
+	domainRepo    domainRepo.Repository
+	tagRepo       tagRepo.Repository
+	folderRepo    folderRepo.Repository
+	pixelRepo     pixelRepo.Repository
 	idGenerator   idgen.Generator
 	urlValidator  *URLValidator
 	codeGenerator *ShortCodeGenerator
 	baseURL       string
+	reuseExisting bool
+	signer        *signedlink.KeyRing
+	maxSignedTTL  time.Duration
 	kafkaProducer *mq.KafkaProducer
 }
 
 // CreateShortURL 创建短链接
 func (s *linkService) CreateShortURL(ctx context.Context, req *model.CreateShortRequest) (*model.Link, error) {
-	longURL := req.LongURL
-	// 验证URL
-	if err := s.ValidateURL(longURL); err != nil {
+	p, err := s.requireRole(ctx, auth.RoleEditor)
+	if err != nil {
 		return nil, err
 	}
+	user := p.UserID
 
-	// 标准化URL
-	normalizeURL, err := s.NormalizeURL(longURL)
+	// 工作空间配额在创建链接的事务中检查
+	ws, err := s.workspaceRepo.FindByID(ctx, p.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 解析自定义域名，只能使用本工作空间注册的域名
+	domain, err := s.resolveDomain(ctx, ws.ID, req.Domain)
+	if err != nil {
+		return nil, err
+	}
+	// 文件夹、标签和像素同样只能使用本工作空间的
+	folderID, err := s.resolveFolder(ctx, ws.ID, req.FolderID)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := s.resolveTags(ctx, ws.ID, req.TagIDs)
+	if err != nil {
+		return nil, err
+	}
+	pixels, err := s.resolvePixels(ctx, ws.ID, req.PixelIDs)
 	if err != nil {
 		return nil, err
 	}
 
+	// 验证并标准化URL，目标地址模板不做标准化
+	normalizeURL, isTemplate, err := s.prepareLongURL(req.LongURL)
+	if err != nil {
+		return nil, err
+	}
+
+	urlHash := HashURL(normalizeURL)
+
+	passwordHash, err := hashPassword(req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	maxClicks := resolveMaxClicks(req.MaxClicks, req.OneTime)
+
+	rules, err := s.normalizeRules(req.Rules)
+	if err != nil {
+		return nil, err
+	}
+	variants, err := s.normalizeVariants(req.Variants)
+	if err != nil {
+		return nil, err
+	}
+	deepLink, err := s.normalizeDeepLink(req.DeepLink)
+	if err != nil {
+		return nil, err
+	}
+	openGraph, err := s.normalizeOpenGraph(req.OpenGraph)
+	if err != nil {
+		return nil, err
+	}
+	ipAccess, err := req.IPAccess.Normalize()
+	if err != nil {
+		return nil, errors.ErrInvalidIPAccess
+	}
+
+	forwardQuery := req.ForwardQuery != nil && *req.ForwardQuery
+	forwardPath := req.ForwardPath != nil && *req.ForwardPath
+	signedOnly := req.SignedOnly != nil && *req.SignedOnly
+	var queryConflict string
+	if req.QueryConflict != nil && *req.QueryConflict != model.QueryConflictDestination {
+		queryConflict = *req.QueryConflict
+	}
+	var redirectType string
+	if req.RedirectType != nil && *req.RedirectType != model.RedirectTypeFound {
+		redirectType = *req.RedirectType
+	}
+
+	// 复用当前用户已有的有效短链，设置了自定义短码、目标地址模板、密码、访问次数、生效时间、跳转规则、分组、深度链接、转发选项、跳转方式、像素、分享卡片、仅限签名访问或IP访问控制时不复用
+	if req.CustomCode == nil && !isTemplate && passwordHash == "" && maxClicks == 0 && req.ActivateAt == nil &&
+		len(rules) == 0 && len(variants) == 0 && deepLink == nil && !forwardQuery && !forwardPath && queryConflict == "" &&
+		redirectType == "" && len(pixels) == 0 && openGraph == nil && !signedOnly && ipAccess == nil && s.shouldReuse(req.ReuseExisting) {
+		existing, err := s.linkRepo.FindByURLHash(ctx, ws.ID, user, domain, urlHash)
+		if err != nil && err != errors.ErrLinkNotFound {
+			return nil, err
+		}
+		if err == nil && isPlainLink(existing) {
+			existing.Reused = true
+			return existing, nil
+		}
+	}
+
 	var shortCode string
 
 	// 处理自定义短码
@@ -46,7 +147,7 @@ func (s *linkService) CreateShortURL(ctx context.Context, req *model.CreateShort
 		}
 
 		// 检查短码是否已存在
-		exists, err := s.linkRepo.Exists(ctx, shortCode)
+		exists, err := s.linkRepo.Exists(ctx, domain, shortCode)
 		if err != nil {
 			return nil, err
 		}
@@ -68,7 +169,7 @@ func (s *linkService) CreateShortURL(ctx context.Context, req *model.CreateShort
 			shortCode = s.codeGenerator.GenerateFromID(id)
 
 			// 检查短码是否已存在
-			exists, err := s.linkRepo.Exists(ctx, shortCode)
+			exists, err := s.linkRepo.Exists(ctx, domain, shortCode)
 			if err != nil {
 				return nil, err
 			}
@@ -89,23 +190,55 @@ func (s *linkService) CreateShortURL(ctx context.Context, req *model.CreateShort
 
 	// 创建链接记录
 	createTime := time.Now()
-	user := s.getUser(req.CreatedBy)
+	expiresAt := req.ExpiresAt
+	if expiresAt == nil && ws.Settings.DefaultExpireDays > 0 {
+		t := createTime.AddDate(0, 0, ws.Settings.DefaultExpireDays)
+		expiresAt = &t
+	}
+	if err := validateSchedule(req.ActivateAt, expiresAt); err != nil {
+		return nil, err
+	}
 	id, _ := s.idGenerator.NextId()
 	link := &model.Link{
-		ID:          id, // 主键ID由雪花算法生成
-		ShortCode:   shortCode,
-		LongURL:     normalizeURL,
-		ExpiresAt:   req.ExpiresAt,
-		CreatedBy:   user,
-		CreatedAt:   createTime,
-		UpdatedBy:   user,
-		UpdatedAt:   createTime,
-		Status:      model.LinkStatusActive,
-		DeleteFlag:  "N",
-		Description: s.getDescription(req.Description),
+		ID:            id, // 主键ID由雪花算法生成
+		WorkspaceID:   ws.ID,
+		Domain:        domain,
+		ShortCode:     shortCode,
+		LongURL:       normalizeURL,
+		Template:      isTemplate,
+		URLHash:       urlHash,
+		FolderID:      folderID,
+		PasswordHash:  passwordHash,
+		MaxClicks:     maxClicks,
+		Rules:         rules,
+		Variants:      variants,
+		DeepLink:      deepLink,
+		OpenGraph:     openGraph,
+		IPAccess:      ipAccess,
+		ForwardQuery:  forwardQuery,
+		QueryConflict: queryConflict,
+		ForwardPath:   forwardPath,
+		RedirectType:  redirectType,
+		SignedOnly:    signedOnly,
+		ActivateAt:    req.ActivateAt,
+		ExpiresAt:     expiresAt,
+		CreatedBy:     user,
+		CreatedAt:     createTime,
+		UpdatedBy:     user,
+		UpdatedAt:     createTime,
+		Status:        model.LinkStatusActive,
+		DeleteFlag:    "N",
+		Description:   s.getDescription(req.Description),
 	}
 
-	if err := s.linkRepo.Create(ctx, link); err != nil {
+	link.Pixels = pixels
+	snapshot := snapshotOf(link)
+	if err := s.linkRepo.Create(ctx, link, &model.LinkRevision{
+		Action:    model.RevisionActionCreate,
+		Snapshot:  snapshot,
+		Changes:   diffSnapshots(model.LinkSnapshot{}, snapshot),
+		CreatedBy: user,
+	}, linkRepo.CreateOptions{MaxLinks: ws.MaxLinks, TagIDs: tagIDs(tags), PixelIDs: pixelIDs(pixels)}); err != nil {
 		return nil, err
 	}
 	// 异步发送缓存预热消息
@@ -115,161 +248,390 @@ func (s *linkService) CreateShortURL(ctx context.Context, req *model.CreateShort
 }
 
 // GetLink 获取长链接（用于重定向）
-func (s *linkService) GetLink(ctx context.Context, shortCode string) (*model.Link, error) {
+func (s *linkService) GetLink(ctx context.Context, domain, shortCode string) (*model.Link, error) {
 	// 直接从数据库获取
-	link, err := s.linkRepo.FindByShortCode(ctx, shortCode)
+	link, err := s.findPublicLink(ctx, domain, shortCode)
 	if err != nil {
 		return nil, err
 	}
-	// 检查链接状态
-	if !link.IsActive() {
+	// 尚未生效的链接交由调用方按生效时间处理
+	if !link.Pending() && !link.IsActive() {
 		if link.Status == model.LinkStatusExpired {
 			return nil, errors.ErrLinkExpired
 		}
 		return nil, errors.ErrLinkDisabled
 	}
-
+	// 回源结果会写入 redirect-service 缓存，需带上像素和工作空间的访问控制
+	if err := s.loadPixels(ctx, link); err != nil {
+		return nil, err
+	}
+	link.WorkspaceIPAccess, err = s.workspaceIPAccess(ctx, link.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
 	return link, nil
 }
 
+// GetLinkMetadata 获取链接元数据（用于预览）
+func (s *linkService) GetLinkMetadata(ctx context.Context, domain, shortCode string) (*model.Link, error) {
+	return s.findPublicLink(ctx, domain, shortCode)
+}
+
 // GetLinkInfo 获取链接信息
-func (s *linkService) GetLinkInfo(ctx context.Context, shortCode string) (*model.LinkInfoResponse, error) {
-	link, err := s.linkRepo.FindByShortCode(ctx, shortCode)
+func (s *linkService) GetLinkInfo(ctx context.Context, domain, shortCode string) (*model.LinkInfoResponse, error) {
+	link, err := s.linkRepo.FindByShortCode(ctx, hostname.Normalize(domain), shortCode)
 	if err != nil {
 		return nil, err
 	}
-	// TODO 远程调用 统计服务获取最后访问时间
-	getLastAccess := time.Now()
-	lastAccess := &getLastAccess
-
-	linkInfo := &model.LinkInfoResponse{
-		ShortCode:    link.ShortCode,
-		LongURL:      link.LongURL,
-		CreatedAt:    link.CreatedAt,
-		UpdatedAt:    link.UpdatedAt,
-		ExpiresAt:    link.ExpiresAt,
-		ClickCount:   link.ClickCount,
-		LastAccessed: lastAccess,
-		Status:       string(link.Status),
-		Description:  link.Description,
+	if _, err := s.authorize(ctx, link, auth.RoleViewer); err != nil {
+		return nil, err
+	}
+	if err := s.loadPixels(ctx, link); err != nil {
+		return nil, err
 	}
-	return linkInfo, nil
+	tags, err := s.tagRepo.ListByLinks(ctx, []uint64{link.ID})
+	if err != nil {
+		return nil, err
+	}
+	linkInfo := s.toLinkInfo(link, tags[link.ID])
+	return &linkInfo, nil
 }
 
 // UpdateLink 更新链接信息
-func (s *linkService) UpdateLink(ctx context.Context, shortCode string, req *model.UpdateLinkRequest) (*model.LinkInfoResponse, error) {
-	link, err := s.linkRepo.FindByShortCode(ctx, shortCode)
+func (s *linkService) UpdateLink(ctx context.Context, domain, shortCode string, ifMatch *uint, req *model.UpdateLinkRequest) (*model.LinkInfoResponse, error) {
+	link, p, err := s.findForUpdate(ctx, domain, shortCode, ifMatch)
 	if err != nil {
 		return nil, err
 	}
+	return s.applyUpdate(ctx, link, p, snapshotOf(link), req)
+}
+
+// 查询待修改的链接并校验权限和版本，ifMatch 为 nil 时不校验版本
+func (s *linkService) findForUpdate(ctx context.Context, domain, shortCode string, ifMatch *uint) (*model.Link, *auth.Principal, error) {
+	link, err := s.linkRepo.FindByShortCode(ctx, hostname.Normalize(domain), shortCode)
+	if err != nil {
+		return nil, nil, err
+	}
+	p, err := s.authorize(ctx, link, auth.RoleEditor)
+	if err != nil {
+		return nil, nil, err
+	}
+	if ifMatch != nil && *ifMatch != link.Version {
+		return nil, nil, errors.ErrVersionConflict
+	}
+	if err := s.loadPixels(ctx, link); err != nil {
+		return nil, nil, err
+	}
+	return link, p, nil
+}
+
+// 将更新请求应用到链接，before 为修改前的快照
+func (s *linkService) applyUpdate(ctx context.Context, link *model.Link, p *auth.Principal, before model.LinkSnapshot, req *model.UpdateLinkRequest) (*model.LinkInfoResponse, error) {
 	// 更新字段
 	if req.LongURL != nil {
-		if err := s.ValidateURL(*req.LongURL); err != nil {
-			return nil, err
-		}
-		normalizeURL, err := s.NormalizeURL(*req.LongURL)
+		normalizeURL, isTemplate, err := s.prepareLongURL(*req.LongURL)
 		if err != nil {
 			return nil, err
 		}
 		link.LongURL = normalizeURL
+		link.URLHash = HashURL(normalizeURL)
+		link.Template = isTemplate
+	}
+	if req.ActivateAt != nil {
+		link.ActivateAt = req.ActivateAt
 	}
 	if req.ExpiresAt != nil {
 		link.ExpiresAt = req.ExpiresAt
 	}
+	if err := validateSchedule(link.ActivateAt, link.ExpiresAt); err != nil {
+		return nil, err
+	}
 	if req.Status != nil {
-		link.Status = model.LinkStatus(*req.Status)
+		status := model.LinkStatus(*req.Status)
+		// 风险标记由管理员设置和解除，编辑者不能绕过
+		if (status == model.LinkStatusFlagged) != (link.Status == model.LinkStatusFlagged) && !p.IsAdmin() {
+			return nil, errors.ErrForbidden
+		}
+		link.Status = status
 	}
 	if req.Description != nil {
 		link.Description = *req.Description
 	}
-	if err := s.linkRepo.Update(ctx, link); err != nil {
-		return nil, err
+	if req.Password != nil {
+		passwordHash, err := hashPassword(req.Password)
+		if err != nil {
+			return nil, err
+		}
+		link.PasswordHash = passwordHash
 	}
-
-	// 更新缓存
-	if link.Status == model.LinkStatusActive {
-		s.sendCacheUpdateAsync(link)
-	} else {
-		s.sendCacheDeleteAsync(link)
+	if req.MaxClicks != nil {
+		link.MaxClicks = *req.MaxClicks
+	}
+	if req.Rules != nil {
+		rules, err := s.normalizeRules(*req.Rules)
+		if err != nil {
+			return nil, err
+		}
+		link.Rules = rules
+	}
+	if req.Variants != nil {
+		variants, err := s.normalizeVariants(*req.Variants)
+		if err != nil {
+			return nil, err
+		}
+		link.Variants = variants
+	}
+	if req.DeepLink != nil {
+		deepLink, err := s.normalizeDeepLink(req.DeepLink)
+		if err != nil {
+			return nil, err
+		}
+		link.DeepLink = deepLink
+	}
+	if req.OpenGraph != nil {
+		openGraph, err := s.normalizeOpenGraph(req.OpenGraph)
+		if err != nil {
+			return nil, err
+		}
+		link.OpenGraph = openGraph
+	}
+	if req.IPAccess != nil {
+		ipAccess, err := req.IPAccess.Normalize()
+		if err != nil {
+			return nil, errors.ErrInvalidIPAccess
+		}
+		link.IPAccess = ipAccess
+	}
+	if req.ForwardQuery != nil {
+		link.ForwardQuery = *req.ForwardQuery
+	}
+	if req.QueryConflict != nil {
+		link.QueryConflict = *req.QueryConflict
+		if link.QueryConflict == model.QueryConflictDestination {
+			link.QueryConflict = ""
+		}
+	}
+	if req.SignedOnly != nil {
+		link.SignedOnly = *req.SignedOnly
+	}
+	if req.ForwardPath != nil {
+		link.ForwardPath = *req.ForwardPath
+	}
+	if req.RedirectType != nil {
+		link.RedirectType = *req.RedirectType
+		if link.RedirectType == model.RedirectTypeFound {
+			link.RedirectType = ""
+		}
+	}
+	if req.FolderID != nil {
+		folderID, err := s.resolveFolder(ctx, link.WorkspaceID, req.FolderID)
+		if err != nil {
+			return nil, err
+		}
+		link.FolderID = folderID
+	}
+	if req.PixelIDs != nil {
+		pixels, err := s.resolvePixels(ctx, link.WorkspaceID, *req.PixelIDs)
+		if err != nil {
+			return nil, err
+		}
+		link.Pixels = pixels
 	}
-	// TODO 远程调用 统计服务获取最后访问时间
-	getLastAccess := time.Now()
-	lastAccess := &getLastAccess
 
-	linkInfo := &model.LinkInfoResponse{
-		ShortCode:    link.ShortCode,
-		LongURL:      link.LongURL,
-		CreatedAt:    link.CreatedAt,
-		UpdatedAt:    link.UpdatedAt,
-		ExpiresAt:    link.ExpiresAt,
-		ClickCount:   link.ClickCount,
-		LastAccessed: lastAccess,
-		Status:       string(link.Status),
-		Description:  link.Description,
+	// 没有实际变化时不写入新版本
+	after := snapshotOf(link)
+	if changes := diffSnapshots(before, after); len(changes) > 0 {
+		link.UpdatedBy = p.UserID
+		// 请求未设置像素时保持不变
+		var pixels []uint64
+		if req.PixelIDs != nil {
+			pixels = pixelIDs(link.Pixels)
+		}
+		if err := s.linkRepo.Update(ctx, link, &model.LinkRevision{
+			Action:    model.RevisionActionUpdate,
+			Snapshot:  after,
+			Changes:   changes,
+			CreatedBy: p.UserID,
+		}, pixels); err != nil {
+			return nil, err
+		}
+
+		// 更新缓存，风险标记随缓存更新消息下发
+		resetClicks := clicksReset(before, after)
+		if link.Status.Reachable() {
+			s.sendCacheUpdateAsync(link, resetClicks)
+		} else {
+			s.sendCacheDeleteAsync(link, model.DeleteReasonInactive, resetClicks)
+		}
 	}
-	return linkInfo, nil
+	tags, err := s.tagRepo.ListByLinks(ctx, []uint64{link.ID})
+	if err != nil {
+		return nil, err
+	}
+	linkInfo := s.toLinkInfo(link, tags[link.ID])
+	return &linkInfo, nil
 }
 
 // DeleteLink 删除链接
-func (s *linkService) DeleteLink(ctx context.Context, shortCode string) error {
+func (s *linkService) DeleteLink(ctx context.Context, domain, shortCode string, ifMatch *uint) error {
 	// 检查链接是否存在
-	link, err := s.linkRepo.FindByShortCode(ctx, shortCode)
+	link, err := s.linkRepo.FindByShortCode(ctx, hostname.Normalize(domain), shortCode)
+	if err != nil {
+		return err
+	}
+	p, err := s.authorize(ctx, link, auth.RoleEditor)
 	if err != nil {
 		return err
 	}
-	// TODO 从上下文获取当前用户信息
-	link.UpdatedBy = s.getUser(nil)
-	if err := s.linkRepo.Delete(ctx, link); err != nil {
+	if ifMatch != nil && *ifMatch != link.Version {
+		return errors.ErrVersionConflict
+	}
+	if err := s.loadPixels(ctx, link); err != nil {
+		return err
+	}
+	link.UpdatedBy = p.UserID
+	// 删除记录保存删除前的完整状态
+	if err := s.linkRepo.Delete(ctx, link, &model.LinkRevision{
+		Action:    model.RevisionActionDelete,
+		Snapshot:  snapshotOf(link),
+		CreatedBy: p.UserID,
+	}); err != nil {
 		return err
 	}
 	// 发送删除缓存消息
-	s.sendCacheDeleteAsync(link)
+	s.sendCacheDeleteAsync(link, model.DeleteReasonDeleted, false)
+	return nil
+}
+
+// ExpireExhausted 访问次数达到上限后将链接置为过期，由 redirect-service 的事件触发
+func (s *linkService) ExpireExhausted(ctx context.Context, workspaceID uint64, domain, shortCode string) error {
+	link, err := s.linkRepo.FindByShortCode(ctx, hostname.Normalize(domain), shortCode)
+	if err != nil {
+		return err
+	}
+	// 事件可能重复投递，或链接已被重新启用并调整了上限，只处理仍处于上限状态的有效链接
+	if link.WorkspaceID != workspaceID || !link.Status.Reachable() || link.MaxClicks == 0 {
+		return nil
+	}
+	if err := s.loadPixels(ctx, link); err != nil {
+		return err
+	}
+	before := snapshotOf(link)
+	link.Status = model.LinkStatusExpired
+	link.UpdatedBy = "system"
+	after := snapshotOf(link)
+	if err := s.linkRepo.Update(ctx, link, &model.LinkRevision{
+		Action:    model.RevisionActionUpdate,
+		Snapshot:  after,
+		Changes:   diffSnapshots(before, after),
+		CreatedBy: link.UpdatedBy,
+	}, nil); err != nil {
+		return err
+	}
+	s.sendCacheDeleteAsync(link, model.DeleteReasonExhausted, false)
 	return nil
 }
 
 // ListLinks 列表查询链接
 func (s *linkService) ListLinks(ctx context.Context, req *model.ListLinksRequest) (*model.ListLinksResponse, error) {
+	p, err := s.requireRole(ctx, auth.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	// 只在当前工作空间内查询，默认只查询自己的链接，仅管理员可按创建者筛选
 	filter := linkRepo.ListFilter{
-		CreatedBy: s.getUser(req.CreatedBy),
-		Search:    "",
+		WorkspaceID:   p.WorkspaceID,
+		CreatedBy:     p.UserID,
+		CreatedAfter:  req.CreatedAfter,
+		CreatedBefore: req.CreatedBefore,
+		ExpiresAfter:  req.ExpiresAfter,
+		ExpiresBefore: req.ExpiresBefore,
+		NeverExpires:  req.NeverExpires,
+	}
+	if req.CreatedBy != nil && p.IsAdmin() {
+		filter.CreatedBy = *req.CreatedBy
 	}
 	if req.Status != nil {
 		filter.Status = *req.Status
 	}
-	links, total, err := s.linkRepo.List(ctx, filter, req.Page, req.PageSize)
-	if err != nil {
-		return nil, err
+	if req.Search != nil {
+		filter.Search = strings.TrimSpace(*req.Search)
 	}
-	// 转换为响应模型
-	linkInfos := make([]model.LinkInfoResponse, len(links))
-	for i, link := range links {
-		// TODO 这里在for循环中查询数据库了，需要优化
-		// TODO 远程调用 统计服务获取最后访问时间
-		getLastAccess := time.Now()
-		lastAccess := &getLastAccess
-
-		linkInfos[i] = model.LinkInfoResponse{
-			ShortCode:    link.ShortCode,
-			LongURL:      link.LongURL,
-			CreatedAt:    link.CreatedAt,
-			UpdatedAt:    link.UpdatedAt,
-			ExpiresAt:    link.ExpiresAt,
-			ClickCount:   link.ClickCount,
-			LastAccessed: lastAccess,
-			Status:       string(link.Status),
-			Description:  link.Description,
+	if req.Tag != nil {
+		tags, err := s.resolveTags(ctx, p.WorkspaceID, []string{*req.Tag})
+		if err != nil {
+			return nil, err
 		}
+		filter.TagID = tags[0].ID
+	}
+	if req.Folder != nil {
+		folderID, err := s.resolveFolder(ctx, p.WorkspaceID, req.Folder)
+		if err != nil {
+			return nil, err
+		}
+		// 包含子文件夹中的链接
+		folders, err := s.folderRepo.ListByWorkspace(ctx, p.WorkspaceID)
+		if err != nil {
+			return nil, err
+		}
+		filter.FolderIDs = folderService.Descendants(folders, folderID)
+	}
+	sort := linkRepo.ListSort{Field: linkRepo.SortField(req.Sort), Desc: req.Order != "asc"}
+	if sort.Field == "" {
+		sort.Field = linkRepo.SortByCreatedAt
 	}
 
-	pages := (total + int64(req.PageSize) - 1) / int64(req.PageSize)
+	resp := &model.ListLinksResponse{PageSize: req.PageSize}
+	var links []model.Link
+	if req.Cursor != "" {
+		// 游标分页：多取一条判断是否还有下一页，不统计总数
+		after, err := decodeCursor(req.Cursor, sort)
+		if err != nil {
+			return nil, err
+		}
+		links, err = s.linkRepo.ListAfter(ctx, filter, sort, after, req.PageSize+1)
+		if err != nil {
+			return nil, err
+		}
+		if len(links) > req.PageSize {
+			links = links[:req.PageSize]
+			resp.HasMore = true
+		}
+	} else {
+		var total int64
+		links, total, err = s.linkRepo.List(ctx, filter, sort, req.Page, req.PageSize)
+		if err != nil {
+			return nil, err
+		}
+		resp.Total = total
+		resp.Page = req.Page
+		resp.Pages = int((total + int64(req.PageSize) - 1) / int64(req.PageSize))
+		resp.HasMore = int64((req.Page-1)*req.PageSize+len(links)) < total
+	}
+	// 偏移分页同样返回游标，客户端可从任意一页切换为游标分页
+	if resp.HasMore && len(links) > 0 {
+		resp.NextCursor = encodeCursor(sort, &links[len(links)-1])
+	}
 
-	return &model.ListLinksResponse{
-		Links:    linkInfos,
-		Total:    total,
-		Page:     req.Page,
-		PageSize: req.PageSize,
-		Pages:    int(pages),
-	}, nil
+	// 转换为响应模型，标签一次批量查询
+	linkIDs := make([]uint64, len(links))
+	for i := range links {
+		linkIDs[i] = links[i].ID
+	}
+	tagsByLink, err := s.tagRepo.ListByLinks(ctx, linkIDs)
+	if err != nil {
+		return nil, err
+	}
+	pixelsByLink, err := s.pixelRepo.ListByLinks(ctx, linkIDs)
+	if err != nil {
+		return nil, err
+	}
+	resp.Links = make([]model.LinkInfoResponse, len(links))
+	for i := range links {
+		links[i].Pixels = pixelsByLink[links[i].ID]
+		resp.Links[i] = s.toLinkInfo(&links[i], tagsByLink[links[i].ID])
+	}
+	return resp, nil
 }
 
 // BatchCreate 批量创建链接
@@ -279,8 +641,10 @@ func (s *linkService) BatchCreate(ctx context.Context, req *model.BatchCreateReq
 
 	for _, item := range req.URLs {
 		createReq := &model.CreateShortRequest{
-			LongURL:    item.LongURL,
-			CustomCode: item.CustomCode,
+			LongURL:       item.LongURL,
+			CustomCode:    item.CustomCode,
+			Domain:        item.Domain,
+			ReuseExisting: req.ReuseExisting,
 		}
 
 		link, err := s.CreateShortURL(ctx, createReq)
@@ -293,8 +657,9 @@ func (s *linkService) BatchCreate(ctx context.Context, req *model.BatchCreateReq
 		}
 		results = append(results, model.BatchResult{
 			LongURL:   link.LongURL,
-			ShortURL:  s.buildShortURL(link.ShortCode),
+			ShortURL:  link.ShortURL(s.baseURL),
 			ShortCode: link.ShortCode,
+			Reused:    link.Reused,
 		})
 	}
 
@@ -304,43 +669,332 @@ func (s *linkService) BatchCreate(ctx context.Context, req *model.BatchCreateReq
 	}, nil
 }
 
+// TagLinks 批量为链接添加标签，无权限或不存在的短码记录在失败列表中
+func (s *linkService) TagLinks(ctx context.Context, req *model.BulkTagRequest) (*model.BulkTagResponse, error) {
+	return s.bulkTag(ctx, req, s.tagRepo.AddToLinks)
+}
+
+// UntagLinks 批量移除链接上的标签
+func (s *linkService) UntagLinks(ctx context.Context, req *model.BulkTagRequest) (*model.BulkTagResponse, error) {
+	return s.bulkTag(ctx, req, func(ctx context.Context, tagIDs, linkIDs []uint64, _ string) error {
+		return s.tagRepo.RemoveFromLinks(ctx, tagIDs, linkIDs)
+	})
+}
+
+func (s *linkService) bulkTag(
+	ctx context.Context,
+	req *model.BulkTagRequest,
+	apply func(ctx context.Context, tagIDs, linkIDs []uint64, user string) error,
+) (*model.BulkTagResponse, error) {
+	p, err := s.requireRole(ctx, auth.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	domain := ""
+	if req.Domain != nil {
+		domain = hostname.Normalize(*req.Domain)
+	}
+	tags, err := s.resolveTags(ctx, p.WorkspaceID, req.TagIDs)
+	if err != nil {
+		return nil, err
+	}
+	links, err := s.linkRepo.FindByShortCodes(ctx, domain, req.ShortCodes)
+	if err != nil {
+		return nil, err
+	}
+	byCode := make(map[string]*model.Link, len(links))
+	for i := range links {
+		byCode[links[i].ShortCode] = &links[i]
+	}
+
+	resp := &model.BulkTagResponse{Updated: make([]string, 0, len(req.ShortCodes))}
+	linkIDs := make([]uint64, 0, len(links))
+	for _, code := range req.ShortCodes {
+		link, ok := byCode[code]
+		if !ok {
+			resp.Failed = append(resp.Failed, model.BulkTagFailed{ShortCode: code, Error: errors.ErrLinkNotFound.Error()})
+			continue
+		}
+		if _, err := s.authorize(ctx, link, auth.RoleEditor); err != nil {
+			resp.Failed = append(resp.Failed, model.BulkTagFailed{ShortCode: code, Error: err.Error()})
+			continue
+		}
+		linkIDs = append(linkIDs, link.ID)
+		resp.Updated = append(resp.Updated, code)
+	}
+	if err := apply(ctx, tagIDs(tags), linkIDs, p.UserID); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 // ValidateURL 验证URL
 func (s *linkService) ValidateURL(url string) error {
 	return s.urlValidator.Validate(url)
 }
 
+// 校验长链接，带占位符的按目标地址模板校验并原样保存，否则标准化
+func (s *linkService) prepareLongURL(longURL string) (string, bool, error) {
+	if s.urlValidator.IsTemplate(longURL) {
+		if err := s.urlValidator.ValidateTemplate(longURL); err != nil {
+			return "", false, err
+		}
+		return longURL, true, nil
+	}
+	if err := s.ValidateURL(longURL); err != nil {
+		return "", false, err
+	}
+	normalizeURL, err := s.NormalizeURL(longURL)
+	if err != nil {
+		return "", false, err
+	}
+	return normalizeURL, false, nil
+}
+
 // NormalizeURL 标准化URL
 func (s *linkService) NormalizeURL(url string) (string, error) {
 	return s.urlValidator.NormalizeURL(url)
 }
 
 type Config struct {
-	BaseURL string
+	BaseURL       string
+	ReuseExisting bool // 请求未指定时是否默认复用已有短链
+	Signer        *signedlink.KeyRing
+	MaxSignedTTL  time.Duration // 签名地址的最长有效期
 }
 
 // NewService 创建短链服务实例
 func NewService(
 	linkRepo linkRepo.Repository,
+	workspaceRepo workspaceRepo.Repository,
+	domainRepo domainRepo.Repository,
+	tagRepo tagRepo.Repository,
+	folderRepo folderRepo.Repository,
+	pixelRepo pixelRepo.Repository,
 	idGenerator idgen.Generator,
 	cfg Config,
 	kp *mq.KafkaProducer,
 ) Service {
 	return &linkService{
 		linkRepo:      linkRepo,
+		workspaceRepo: workspaceRepo,
+		domainRepo:    domainRepo,
+		tagRepo:       tagRepo,
+		folderRepo:    folderRepo,
+		pixelRepo:     pixelRepo,
 		idGenerator:   idGenerator,
 		urlValidator:  NewURLValidator(),
 		codeGenerator: NewShortCodeGenerator(),
 		baseURL:       cfg.BaseURL,
+		reuseExisting: cfg.ReuseExisting,
+		signer:        cfg.Signer,
+		maxSignedTTL:  cfg.MaxSignedTTL,
 		kafkaProducer: kp,
 	}
 }
 
-// 获取创建者信息
-func (s *linkService) getUser(createdBy *string) string {
-	if createdBy == nil {
-		return "anonymous"
+// 从上下文获取当前认证用户，并检查工作空间与角色
+func (s *linkService) requireRole(ctx context.Context, required auth.Role) (*auth.Principal, error) {
+	p, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, errors.ErrUnauthorized
+	}
+	if p.WorkspaceID == 0 {
+		return nil, errors.ErrWorkspaceRequired
+	}
+	if !p.Role.AtLeast(required) {
+		return nil, errors.ErrForbidden
+	}
+	return p, nil
+}
+
+// 检查当前用户对链接的操作权限：同一工作空间内的所有者或管理员
+func (s *linkService) authorize(ctx context.Context, link *model.Link, required auth.Role) (*auth.Principal, error) {
+	p, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, errors.ErrUnauthorized
+	}
+	if p.WorkspaceID == 0 {
+		return nil, errors.ErrWorkspaceRequired
+	}
+	// 其他工作空间的链接对外表现为不存在
+	if link.WorkspaceID != p.WorkspaceID {
+		return nil, errors.ErrLinkNotFound
+	}
+	if !p.CanAccess(link.CreatedBy, required) {
+		return nil, errors.ErrForbidden
+	}
+	return p, nil
+}
+
+// 链接未设置任何访问限制或跳转选项，只有这样的链接可以复用。查询已按同样条件过滤，
+// 这里再次校验，避免把受保护的链接返回给其他请求
+func isPlainLink(link *model.Link) bool {
+	return link.Status == model.LinkStatusActive && !link.Template && link.PasswordHash == "" && link.MaxClicks == 0 &&
+		len(link.Rules) == 0 && len(link.Variants) == 0 && link.DeepLink == nil && link.OpenGraph == nil &&
+		link.IPAccess == nil && !link.ForwardQuery && !link.ForwardPath && link.QueryConflict == "" &&
+		link.RedirectType == "" && !link.SignedOnly && len(link.Pixels) == 0
+}
+
+// 请求指定了 reuse_existing 时以请求为准，否则使用配置默认值
+func (s *linkService) shouldReuse(reuse *bool) bool {
+	if reuse != nil {
+		return *reuse
 	}
-	return *createdBy
+	return s.reuseExisting
+}
+
+// 解析并校验文件夹，nil 或空字符串表示不归入文件夹
+func (s *linkService) resolveFolder(ctx context.Context, workspaceID uint64, id *string) (uint64, error) {
+	if id == nil || *id == "" {
+		return 0, nil
+	}
+	folderID, err := strconv.ParseUint(*id, 10, 64)
+	if err != nil {
+		return 0, errors.ErrFolderNotFound
+	}
+	folder, err := s.folderRepo.FindByID(ctx, folderID)
+	if err != nil {
+		return 0, err
+	}
+	if folder.WorkspaceID != workspaceID {
+		return 0, errors.ErrFolderNotFound
+	}
+	return folder.ID, nil
+}
+
+// 解析并校验标签，任一标签不属于本工作空间时返回 ErrTagNotFound
+func (s *linkService) resolveTags(ctx context.Context, workspaceID uint64, ids []string) ([]model.Tag, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	seen := make(map[uint64]bool, len(ids))
+	tagIDs := make([]uint64, 0, len(ids))
+	for _, id := range ids {
+		tagID, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			return nil, errors.ErrTagNotFound
+		}
+		if !seen[tagID] {
+			seen[tagID] = true
+			tagIDs = append(tagIDs, tagID)
+		}
+	}
+	tags, err := s.tagRepo.FindByIDs(ctx, workspaceID, tagIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) != len(tagIDs) {
+		return nil, errors.ErrTagNotFound
+	}
+	return tags, nil
+}
+
+func tagIDs(tags []model.Tag) []uint64 {
+	ids := make([]uint64, len(tags))
+	for i := range tags {
+		ids[i] = tags[i].ID
+	}
+	return ids
+}
+
+// 转换为链接信息响应
+func (s *linkService) toLinkInfo(link *model.Link, tags []model.Tag) model.LinkInfoResponse {
+	// TODO 远程调用 统计服务获取最后访问时间
+	getLastAccess := time.Now()
+	lastAccess := &getLastAccess
+
+	info := model.LinkInfoResponse{
+		WorkspaceID:   strconv.FormatUint(link.WorkspaceID, 10),
+		Domain:        link.Domain,
+		ShortCode:     link.ShortCode,
+		ShortURL:      link.ShortURL(s.baseURL),
+		LongURL:       link.LongURL,
+		CreatedAt:     link.CreatedAt,
+		UpdatedAt:     link.UpdatedAt,
+		ActivateAt:    link.ActivateAt,
+		ExpiresAt:     link.ExpiresAt,
+		ClickCount:    link.ClickCount,
+		LastAccessed:  lastAccess,
+		Status:        string(link.Status),
+		Description:   link.Description,
+		Protected:     link.PasswordHash != "",
+		MaxClicks:     link.MaxClicks,
+		Rules:         link.Rules,
+		Variants:      link.Variants,
+		DeepLink:      link.DeepLink,
+		OpenGraph:     link.OpenGraph,
+		IPAccess:      link.IPAccess,
+		ForwardQuery:  link.ForwardQuery,
+		QueryConflict: link.QueryConflict,
+		ForwardPath:   link.ForwardPath,
+		Template:      link.Template,
+		RedirectType:  redirectType(link.RedirectType),
+		SignedOnly:    link.SignedOnly,
+		Tags:          make([]model.LinkTagResponse, len(tags)),
+		Pixels:        make([]model.LinkPixelResponse, len(link.Pixels)),
+		Version:       link.Version,
+	}
+	if link.FolderID != 0 {
+		info.FolderID = strconv.FormatUint(link.FolderID, 10)
+	}
+	for i, tag := range tags {
+		info.Tags[i] = model.LinkTagResponse{
+			ID:    strconv.FormatUint(tag.ID, 10),
+			Name:  tag.Name,
+			Color: tag.Color,
+		}
+	}
+	for i, pixel := range link.Pixels {
+		info.Pixels[i] = model.LinkPixelResponse{
+			ID:       strconv.FormatUint(pixel.ID, 10),
+			Name:     pixel.Name,
+			Provider: pixel.Provider,
+		}
+	}
+	return info
+}
+
+// 响应中展示实际生效的跳转方式
+func redirectType(t string) string {
+	if t == "" {
+		return model.RedirectTypeFound
+	}
+	return t
+}
+
+// 计算访问密码的 bcrypt 哈希，nil 或空字符串表示不设置密码
+func hashPassword(password *string) (string, error) {
+	if password == nil || *password == "" {
+		return "", nil
+	}
+	if len(*password) < 4 {
+		return "", errors.NewBusinessError("password must be at least 4 characters")
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(*password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// 生效时间必须早于过期时间
+func validateSchedule(activateAt, expiresAt *time.Time) error {
+	if activateAt != nil && expiresAt != nil && !activateAt.Before(*expiresAt) {
+		return errors.ErrInvalidSchedule
+	}
+	return nil
+}
+
+// 计算最大访问次数，one_time 优先，0表示不限制
+func resolveMaxClicks(maxClicks *int64, oneTime *bool) int64 {
+	if oneTime != nil && *oneTime {
+		return 1
+	}
+	if maxClicks != nil {
+		return *maxClicks
+	}
+	return 0
 }
 
 // 获取描述信息
@@ -351,7 +1005,40 @@ func (s *linkService) getDescription(description *string) string {
 	return *description
 }
 
-// 构建完整的短链
-func (s *linkService) buildShortURL(shortCode string) string {
-	return fmt.Sprintf("%s/%s", s.baseURL, shortCode)
+// 解析并校验自定义域名，nil 或空字符串表示默认域名，未通过所有权验证的域名不可使用
+func (s *linkService) resolveDomain(ctx context.Context, workspaceID uint64, domain *string) (string, error) {
+	if domain == nil || *domain == "" {
+		return "", nil
+	}
+	d, err := s.domainRepo.FindByWorkspaceHostname(ctx, workspaceID, hostname.Normalize(*domain))
+	if err != nil {
+		return "", err
+	}
+	if d.VerifiedAt == nil {
+		return "", errors.ErrDomainUnverified
+	}
+	return d.Hostname, nil
+}
+
+// 按域名和短码查询对外解析的链接，自定义域名须已由链接所属工作空间验证，否则视为不存在
+func (s *linkService) findPublicLink(ctx context.Context, domain, shortCode string) (*model.Link, error) {
+	domain = hostname.Normalize(domain)
+	link, err := s.linkRepo.FindByShortCode(ctx, domain, shortCode)
+	if err != nil {
+		return nil, err
+	}
+	if domain == "" {
+		return link, nil
+	}
+	d, err := s.domainRepo.FindByHostname(ctx, domain)
+	if err == errors.ErrDomainNotFound {
+		return nil, errors.ErrLinkNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if d.WorkspaceID != link.WorkspaceID {
+		return nil, errors.ErrLinkNotFound
+	}
+	return link, nil
 }
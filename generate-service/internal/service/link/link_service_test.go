@@ -0,0 +1,33 @@
+package link
+
+import (
+	"generate-service/internal/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPlainLink(t *testing.T) {
+	plain := model.Link{LongURL: "https://example.com/a", Status: model.LinkStatusActive}
+	assert.True(t, isPlainLink(&plain))
+
+	// 受保护或带跳转选项的链接即使目标地址相同也不能复用
+	protected := []func(l *model.Link){
+		func(l *model.Link) { l.PasswordHash = "$2a$10$secret" },
+		func(l *model.Link) { l.MaxClicks = 1 },
+		func(l *model.Link) { l.Template = true },
+		func(l *model.Link) { l.SignedOnly = true },
+		func(l *model.Link) {
+			l.Rules = []model.RedirectRule{{Countries: []string{"DE"}, Destination: "https://example.com/b"}}
+		},
+		func(l *model.Link) { l.IPAccess = &model.IPAccess{Deny: []string{"192.0.2.0/24"}} },
+		func(l *model.Link) { l.QueryConflict = model.QueryConflictIncoming },
+		func(l *model.Link) { l.Pixels = []model.Pixel{{ID: 1}} },
+		func(l *model.Link) { l.Status = model.LinkStatusFlagged },
+	}
+	for i, apply := range protected {
+		link := plain
+		apply(&link)
+		assert.False(t, isPlainLink(&link), i)
+	}
+}
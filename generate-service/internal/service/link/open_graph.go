@@ -0,0 +1,32 @@
+package link
+
+import (
+	"generate-service/internal/model"
+	"strings"
+)
+
+// 校验并规范化分享卡片覆盖值，图片地址与长链接使用相同的校验规则。未设置任何字段时返回 nil
+func (s *linkService) normalizeOpenGraph(openGraph *model.OpenGraph) (*model.OpenGraph, error) {
+	if openGraph == nil {
+		return nil, nil
+	}
+	og := model.OpenGraph{
+		Title:       strings.TrimSpace(openGraph.Title),
+		Description: strings.TrimSpace(openGraph.Description),
+		Image:       strings.TrimSpace(openGraph.Image),
+	}
+	if og.IsEmpty() {
+		return nil, nil
+	}
+	if og.Image != "" {
+		if err := s.ValidateURL(og.Image); err != nil {
+			return nil, err
+		}
+		normalized, err := s.NormalizeURL(og.Image)
+		if err != nil {
+			return nil, err
+		}
+		og.Image = normalized
+	}
+	return &og, nil
+}
@@ -0,0 +1,153 @@
+package link
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"generate-service/internal/model"
+	"generate-service/internal/pkg/errors"
+	"generate-service/internal/pkg/mergepatch"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// 与 gin 绑定请求时使用相同的校验规则
+var requestValidator = newRequestValidator()
+
+func newRequestValidator() *validator.Validate {
+	v := validator.New()
+	v.SetTagName("binding")
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		return name
+	})
+	return v
+}
+
+// PatchLink 将合并补丁应用到链接当前的可编辑字段上，合并结果按 PUT 请求的规则校验并更新
+func (s *linkService) PatchLink(ctx context.Context, domain, shortCode string, ifMatch *uint, patch []byte) (*model.LinkInfoResponse, error) {
+	link, p, err := s.findForUpdate(ctx, domain, shortCode, ifMatch)
+	if err != nil {
+		return nil, err
+	}
+	req, err := mergeLinkPatch(link, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	before := snapshotOf(link)
+	// 生效和过期时间为 null 时表示立即生效、永不过期
+	if req.ActivateAt == nil {
+		link.ActivateAt = nil
+	}
+	if req.ExpiresAt == nil {
+		link.ExpiresAt = nil
+	}
+	return s.applyUpdate(ctx, link, p, before, req)
+}
+
+// 将补丁合并到链接的当前文档，返回校验后的完整更新请求
+func mergeLinkPatch(link *model.Link, patch []byte) (*model.UpdateLinkRequest, error) {
+	if !bytes.HasPrefix(bytes.TrimSpace(patch), []byte("{")) {
+		return nil, errors.ErrInvalidPatch
+	}
+	target, err := json.Marshal(patchDocument(link))
+	if err != nil {
+		return nil, err
+	}
+	merged, err := mergepatch.Apply(target, patch)
+	if err != nil {
+		return nil, errors.ErrInvalidPatch
+	}
+	req := clearedRequest()
+	if err := json.Unmarshal(merged, req); err != nil {
+		return nil, errors.ErrInvalidPatch
+	}
+	// 密码只写不读，不在文档中，补丁中为 null 时取消密码
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &fields); err != nil {
+		return nil, errors.ErrInvalidPatch
+	}
+	if raw, ok := fields["password"]; ok && string(raw) == "null" {
+		req.Password = new(string)
+	}
+	if err := requestValidator.Struct(req); err != nil {
+		if verrs, ok := err.(validator.ValidationErrors); ok && len(verrs) > 0 {
+			return nil, &errors.ValidationError{Field: verrs[0].Field(), Message: verrs[0].Tag()}
+		}
+		return nil, errors.ErrInvalidPatch
+	}
+	return req, nil
+}
+
+// 链接当前可编辑字段组成的文档，字段与 PUT 请求一致。已过期等非可编辑状态不放入文档，
+// 补丁未设置状态时保持不变
+func patchDocument(link *model.Link) *model.UpdateLinkRequest {
+	doc := &model.UpdateLinkRequest{
+		LongURL:       &link.LongURL,
+		ActivateAt:    link.ActivateAt,
+		ExpiresAt:     link.ExpiresAt,
+		Description:   &link.Description,
+		MaxClicks:     &link.MaxClicks,
+		Rules:         &link.Rules,
+		Variants:      &link.Variants,
+		DeepLink:      link.DeepLink,
+		OpenGraph:     link.OpenGraph,
+		IPAccess:      link.IPAccess,
+		ForwardQuery:  &link.ForwardQuery,
+		QueryConflict: &link.QueryConflict,
+		ForwardPath:   &link.ForwardPath,
+		RedirectType:  &link.RedirectType,
+		SignedOnly:    &link.SignedOnly,
+	}
+	switch link.Status {
+	case model.LinkStatusActive, model.LinkStatusDisabled, model.LinkStatusFlagged:
+		status := string(link.Status)
+		doc.Status = &status
+	}
+	folderID := ""
+	if link.FolderID != 0 {
+		folderID = strconv.FormatUint(link.FolderID, 10)
+	}
+	doc.FolderID = &folderID
+	if link.QueryConflict == "" {
+		queryConflict := model.QueryConflictDestination
+		doc.QueryConflict = &queryConflict
+	}
+	if link.RedirectType == "" {
+		redirectType := model.RedirectTypeFound
+		doc.RedirectType = &redirectType
+	}
+	pixelIDs := make([]string, len(link.Pixels))
+	for i, p := range link.Pixels {
+		pixelIDs[i] = strconv.FormatUint(p.ID, 10)
+	}
+	doc.PixelIDs = &pixelIDs
+	return doc
+}
+
+// 补丁删除的字段按清除处理，解码合并结果前预先填入清除时的取值。
+// 目标地址和状态不能清除，缺失时分别由校验拒绝和保持不变
+func clearedRequest() *model.UpdateLinkRequest {
+	queryConflict := model.QueryConflictDestination
+	redirectType := model.RedirectTypeFound
+	return &model.UpdateLinkRequest{
+		Description:   new(string),
+		FolderID:      new(string),
+		MaxClicks:     new(int64),
+		Rules:         &[]model.RedirectRule{},
+		Variants:      &[]model.LinkVariant{},
+		DeepLink:      &model.DeepLink{},
+		OpenGraph:     &model.OpenGraph{},
+		IPAccess:      &model.IPAccess{},
+		ForwardQuery:  new(bool),
+		QueryConflict: &queryConflict,
+		ForwardPath:   new(bool),
+		RedirectType:  &redirectType,
+		SignedOnly:    new(bool),
+		PixelIDs:      &[]string{},
+	}
+}
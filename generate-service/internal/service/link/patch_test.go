@@ -0,0 +1,71 @@
+package link
+
+import (
+	"generate-service/internal/model"
+	"generate-service/internal/pkg/errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeLinkPatch(t *testing.T) {
+	expiresAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	link := &model.Link{
+		LongURL:      "https://example.com/a",
+		ExpiresAt:    &expiresAt,
+		FolderID:     7,
+		Description:  "launch",
+		MaxClicks:    100,
+		OpenGraph:    &model.OpenGraph{Title: "Launch", Description: "Spring launch"},
+		RedirectType: model.RedirectTypeMovedPermanently,
+		Pixels:       []model.Pixel{{ID: 3}},
+		Status:       model.LinkStatusExpired,
+	}
+
+	// 空补丁保留当前值，不可编辑的状态不放入文档
+	req, err := mergeLinkPatch(link, []byte(`{}`))
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/a", *req.LongURL)
+	assert.Equal(t, &expiresAt, req.ExpiresAt)
+	assert.Equal(t, "7", *req.FolderID)
+	assert.Equal(t, int64(100), *req.MaxClicks)
+	assert.Equal(t, model.RedirectTypeMovedPermanently, *req.RedirectType)
+	assert.Equal(t, []string{"3"}, *req.PixelIDs)
+	assert.Nil(t, req.Status)
+	assert.Nil(t, req.Password)
+
+	// null 清除字段，嵌套对象逐字段合并
+	req, err = mergeLinkPatch(link, []byte(`{
+		"expires_at": null, "folder_id": null, "max_clicks": null, "redirect_type": null,
+		"pixel_ids": null, "password": null, "open_graph": {"description": null}
+	}`))
+	require.NoError(t, err)
+	assert.Nil(t, req.ExpiresAt)
+	assert.Equal(t, "", *req.FolderID)
+	assert.Equal(t, int64(0), *req.MaxClicks)
+	assert.Equal(t, model.RedirectTypeFound, *req.RedirectType)
+	assert.Empty(t, *req.PixelIDs)
+	assert.Equal(t, "", *req.Password)
+	assert.Equal(t, &model.OpenGraph{Title: "Launch"}, req.OpenGraph)
+
+	req, err = mergeLinkPatch(link, []byte(`{"status": "disabled", "password": "s3cret"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "disabled", *req.Status)
+	assert.Equal(t, "s3cret", *req.Password)
+
+	_, err = mergeLinkPatch(link, []byte(`{"status": "expired"}`))
+	var verr *errors.ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, "status", verr.Field)
+
+	_, err = mergeLinkPatch(link, []byte(`{"long_url": null}`))
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, "long_url", verr.Field)
+
+	for _, bad := range []string{`[]`, `null`, `{"max_clicks": "many"}`, `{`} {
+		_, err = mergeLinkPatch(link, []byte(bad))
+		assert.Equal(t, errors.ErrInvalidPatch, err, bad)
+	}
+}
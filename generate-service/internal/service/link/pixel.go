@@ -0,0 +1,136 @@
+package link
+
+import (
+	"context"
+	"generate-service/internal/model"
+	"generate-service/internal/pkg/errors"
+	"strconv"
+)
+
+// 解析并校验重定向像素，任一像素不属于本工作空间时返回 ErrPixelNotFound
+func (s *linkService) resolvePixels(ctx context.Context, workspaceID uint64, ids []string) ([]model.Pixel, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	seen := make(map[uint64]bool, len(ids))
+	ordered := make([]uint64, 0, len(ids))
+	for _, id := range ids {
+		pixelID, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			return nil, errors.ErrPixelNotFound
+		}
+		if !seen[pixelID] {
+			seen[pixelID] = true
+			ordered = append(ordered, pixelID)
+		}
+	}
+	pixels, err := s.pixelRepo.FindByIDs(ctx, workspaceID, ordered)
+	if err != nil {
+		return nil, err
+	}
+	if len(pixels) != len(ordered) {
+		return nil, errors.ErrPixelNotFound
+	}
+	return pixels, nil
+}
+
+// 加载链接挂载的像素
+func (s *linkService) loadPixels(ctx context.Context, link *model.Link) error {
+	pixelsByLink, err := s.pixelRepo.ListByLinks(ctx, []uint64{link.ID})
+	if err != nil {
+		return err
+	}
+	link.Pixels = pixelsByLink[link.ID]
+	return nil
+}
+
+// PixelRemovalRevisions 生成从链接上移除像素后的修订记录，已删除的链接不在其中
+func (s *linkService) PixelRemovalRevisions(ctx context.Context, pixelID uint64, linkIDs []uint64, userID string) ([]*model.LinkRevision, error) {
+	if len(linkIDs) == 0 {
+		return nil, nil
+	}
+	links, err := s.linkRepo.FindByIDs(ctx, linkIDs)
+	if err != nil {
+		return nil, err
+	}
+	pixelsByLink, err := s.pixelRepo.ListByLinks(ctx, linkIDs)
+	if err != nil {
+		return nil, err
+	}
+	revs := make([]*model.LinkRevision, 0, len(links))
+	for i := range links {
+		link := &links[i]
+		link.Pixels = pixelsByLink[link.ID]
+		before := snapshotOf(link)
+		link.Pixels = withoutPixel(link.Pixels, pixelID)
+		after := snapshotOf(link)
+		revs = append(revs, &model.LinkRevision{
+			LinkID:    link.ID,
+			Version:   link.Version + 1,
+			Action:    model.RevisionActionUpdate,
+			Snapshot:  after,
+			Changes:   diffSnapshots(before, after),
+			CreatedBy: userID,
+		})
+	}
+	return revs, nil
+}
+
+func withoutPixel(pixels []model.Pixel, pixelID uint64) []model.Pixel {
+	var remaining []model.Pixel
+	for _, p := range pixels {
+		if p.ID != pixelID {
+			remaining = append(remaining, p)
+		}
+	}
+	return remaining
+}
+
+// 每批刷新的链接数量
+const refreshBatchSize = 500
+
+// RefreshLinks 重新发送链接的缓存更新消息，只处理可访问的链接，其余链接已不在缓存中
+func (s *linkService) RefreshLinks(ctx context.Context, linkIDs []uint64) error {
+	if len(linkIDs) == 0 {
+		return nil
+	}
+	links, err := s.linkRepo.FindByIDs(ctx, linkIDs)
+	if err != nil {
+		return err
+	}
+	pixelsByLink, err := s.pixelRepo.ListByLinks(ctx, linkIDs)
+	if err != nil {
+		return err
+	}
+	for i := range links {
+		if !links[i].Status.Reachable() {
+			continue
+		}
+		links[i].Pixels = pixelsByLink[links[i].ID]
+		s.sendCacheUpdateAsync(&links[i], false)
+	}
+	return nil
+}
+
+// RefreshWorkspace 工作空间设置变更后分批刷新其所有链接的缓存
+func (s *linkService) RefreshWorkspace(ctx context.Context, workspaceID uint64) error {
+	ids, err := s.linkRepo.ListIDsByWorkspace(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+	for start := 0; start < len(ids); start += refreshBatchSize {
+		end := min(start+refreshBatchSize, len(ids))
+		if err := s.RefreshLinks(ctx, ids[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func pixelIDs(pixels []model.Pixel) []uint64 {
+	ids := make([]uint64, len(pixels))
+	for i := range pixels {
+		ids[i] = pixels[i].ID
+	}
+	return ids
+}
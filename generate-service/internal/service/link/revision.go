@@ -0,0 +1,224 @@
+package link
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"generate-service/internal/model"
+	"generate-service/internal/pkg/errors"
+	"generate-service/internal/service/auth"
+	"reflect"
+	"shared/hostname"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GetLinkHistory 查询链接的修订历史
+func (s *linkService) GetLinkHistory(ctx context.Context, domain, shortCode string) (*model.LinkHistoryResponse, error) {
+	link, err := s.linkRepo.FindByShortCode(ctx, hostname.Normalize(domain), shortCode)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.authorize(ctx, link, auth.RoleViewer); err != nil {
+		return nil, err
+	}
+	revisions, err := s.linkRepo.ListRevisions(ctx, link.ID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &model.LinkHistoryResponse{
+		ShortURL:  link.ShortURL(s.baseURL),
+		Version:   link.Version,
+		Revisions: make([]model.LinkRevisionResponse, len(revisions)),
+	}
+	for i, rev := range revisions {
+		resp.Revisions[i] = model.LinkRevisionResponse{
+			Version:         rev.Version,
+			Action:          rev.Action,
+			RestoredVersion: rev.RestoredVersion,
+			Changes:         rev.Changes,
+			CreatedAt:       rev.CreatedAt,
+			CreatedBy:       rev.CreatedBy,
+		}
+	}
+	return resp, nil
+}
+
+// RollbackLink 将链接恢复到指定版本的状态，恢复操作本身记为一个新版本
+func (s *linkService) RollbackLink(ctx context.Context, domain, shortCode string, version uint) (*model.LinkInfoResponse, error) {
+	link, err := s.linkRepo.FindByShortCode(ctx, hostname.Normalize(domain), shortCode)
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.authorize(ctx, link, auth.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	rev, err := s.linkRepo.FindRevision(ctx, link.ID, version)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.loadPixels(ctx, link); err != nil {
+		return nil, err
+	}
+	target := rev.Snapshot
+	// 风险标记由管理员设置和解除，回滚同样不能绕过
+	if (target.Status == model.LinkStatusFlagged) != (link.Status == model.LinkStatusFlagged) && !p.IsAdmin() {
+		return nil, errors.ErrForbidden
+	}
+	// 快照中的文件夹和像素可能已被删除，恢复时忽略
+	if target.FolderID != 0 {
+		folder, err := s.folderRepo.FindByID(ctx, target.FolderID)
+		if err != nil && err != errors.ErrFolderNotFound {
+			return nil, err
+		}
+		if err != nil || folder.WorkspaceID != link.WorkspaceID {
+			target.FolderID = 0
+		}
+	}
+	pixels, err := s.existingPixels(ctx, link.WorkspaceID, target.PixelIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	before := snapshotOf(link)
+	restoreSnapshot(link, &target)
+	link.Pixels = pixels
+	after := snapshotOf(link)
+	link.UpdatedBy = p.UserID
+	restored := rev.Version
+	if err := s.linkRepo.Update(ctx, link, &model.LinkRevision{
+		Action:          model.RevisionActionRollback,
+		RestoredVersion: &restored,
+		Snapshot:        after,
+		Changes:         diffSnapshots(before, after),
+		CreatedBy:       p.UserID,
+	}, pixelIDs(pixels)); err != nil {
+		return nil, err
+	}
+
+	resetClicks := clicksReset(before, after)
+	if link.Status.Reachable() {
+		s.sendCacheUpdateAsync(link, resetClicks)
+	} else {
+		s.sendCacheDeleteAsync(link, model.DeleteReasonInactive, resetClicks)
+	}
+	tags, err := s.tagRepo.ListByLinks(ctx, []uint64{link.ID})
+	if err != nil {
+		return nil, err
+	}
+	linkInfo := s.toLinkInfo(link, tags[link.ID])
+	return &linkInfo, nil
+}
+
+// 查询仍存在于工作空间中的像素，保持快照中的顺序
+func (s *linkService) existingPixels(ctx context.Context, workspaceID uint64, ids []string) ([]model.Pixel, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	pixelIDs := make([]uint64, 0, len(ids))
+	for _, id := range ids {
+		if pixelID, err := strconv.ParseUint(id, 10, 64); err == nil {
+			pixelIDs = append(pixelIDs, pixelID)
+		}
+	}
+	return s.pixelRepo.FindByIDs(ctx, workspaceID, pixelIDs)
+}
+
+// 生成链接可编辑字段的快照。空列表统一为 nil、时间统一为 UTC、像素ID排序，
+// 避免同一状态因表示方式不同产生差异
+func snapshotOf(link *model.Link) model.LinkSnapshot {
+	snap := model.LinkSnapshot{
+		LongURL:       link.LongURL,
+		Template:      link.Template,
+		PasswordHash:  link.PasswordHash,
+		FolderID:      link.FolderID,
+		ActivateAt:    utcTime(link.ActivateAt),
+		ExpiresAt:     utcTime(link.ExpiresAt),
+		MaxClicks:     link.MaxClicks,
+		DeepLink:      link.DeepLink,
+		OpenGraph:     link.OpenGraph,
+		IPAccess:      link.IPAccess,
+		ForwardQuery:  link.ForwardQuery,
+		QueryConflict: link.QueryConflict,
+		ForwardPath:   link.ForwardPath,
+		RedirectType:  link.RedirectType,
+		SignedOnly:    link.SignedOnly,
+		Status:        link.Status,
+		Description:   link.Description,
+	}
+	if len(link.Rules) > 0 {
+		snap.Rules = link.Rules
+	}
+	if len(link.Variants) > 0 {
+		snap.Variants = link.Variants
+	}
+	for _, p := range link.Pixels {
+		snap.PixelIDs = append(snap.PixelIDs, strconv.FormatUint(p.ID, 10))
+	}
+	sort.Strings(snap.PixelIDs)
+	return snap
+}
+
+// 将快照中的字段写回链接，像素由调用方单独处理
+func restoreSnapshot(link *model.Link, snap *model.LinkSnapshot) {
+	link.LongURL = snap.LongURL
+	link.URLHash = HashURL(snap.LongURL)
+	link.Template = snap.Template
+	link.PasswordHash = snap.PasswordHash
+	link.FolderID = snap.FolderID
+	link.ActivateAt = snap.ActivateAt
+	link.ExpiresAt = snap.ExpiresAt
+	link.MaxClicks = snap.MaxClicks
+	link.Rules = snap.Rules
+	link.Variants = snap.Variants
+	link.DeepLink = snap.DeepLink
+	link.OpenGraph = snap.OpenGraph
+	link.IPAccess = snap.IPAccess
+	link.ForwardQuery = snap.ForwardQuery
+	link.QueryConflict = snap.QueryConflict
+	link.ForwardPath = snap.ForwardPath
+	link.RedirectType = snap.RedirectType
+	link.SignedOnly = snap.SignedOnly
+	link.Status = snap.Status
+	link.Description = snap.Description
+}
+
+// 按快照字段顺序比较两个快照，返回发生变化的字段。密码哈希不对外展示，
+// 只以 password 字段标明变更前后是否设置了密码
+func diffSnapshots(before, after model.LinkSnapshot) []model.FieldChange {
+	bv, av := reflect.ValueOf(before), reflect.ValueOf(after)
+	t := bv.Type()
+	changes := []model.FieldChange{}
+	for i := 0; i < t.NumField(); i++ {
+		b, _ := json.Marshal(bv.Field(i).Interface())
+		a, _ := json.Marshal(av.Field(i).Interface())
+		if bytes.Equal(b, a) {
+			continue
+		}
+		field, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if field == "password_hash" {
+			field = "password"
+			b, _ = json.Marshal(before.PasswordHash != "")
+			a, _ = json.Marshal(after.PasswordHash != "")
+		}
+		changes = append(changes, model.FieldChange{Field: field, Before: b, After: a})
+	}
+	return changes
+}
+
+// 链接从过期恢复为有效，或访问次数上限发生变化时，redirect-service 需清零访问计数
+func clicksReset(before, after model.LinkSnapshot) bool {
+	reactivated := before.Status == model.LinkStatusExpired && after.Status == model.LinkStatusActive
+	return reactivated || before.MaxClicks != after.MaxClicks
+}
+
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
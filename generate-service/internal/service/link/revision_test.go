@@ -0,0 +1,69 @@
+package link
+
+import (
+	"generate-service/internal/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	expiresAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.FixedZone("CST", 8*3600))
+	link := &model.Link{
+		LongURL:   "https://example.com/a",
+		ExpiresAt: &expiresAt,
+		Rules:     []model.RedirectRule{},
+		Pixels:    []model.Pixel{{ID: 20}, {ID: 3}},
+		Status:    model.LinkStatusActive,
+	}
+	before := snapshotOf(link)
+	assert.Equal(t, []string{"20", "3"}, before.PixelIDs)
+
+	// 同一时刻、空列表和 nil 不视为变化
+	utc := expiresAt.UTC()
+	link.ExpiresAt = &utc
+	link.Rules = nil
+	assert.Empty(t, diffSnapshots(before, snapshotOf(link)))
+
+	link.LongURL = "https://example.com/b"
+	link.PasswordHash = "$2a$10$secret"
+	link.Pixels = link.Pixels[:1]
+	changes := diffSnapshots(before, snapshotOf(link))
+	require.Len(t, changes, 3)
+	assert.Equal(t, model.FieldChange{Field: "long_url",
+		Before: []byte(`"https://example.com/a"`), After: []byte(`"https://example.com/b"`)}, changes[0])
+	// 密码哈希不出现在变更记录中
+	assert.Equal(t, model.FieldChange{Field: "password", Before: []byte("false"), After: []byte("true")}, changes[1])
+	assert.Equal(t, "pixel_ids", changes[2].Field)
+	assert.JSONEq(t, `["20"]`, string(changes[2].After))
+}
+
+func TestClicksReset(t *testing.T) {
+	before := model.LinkSnapshot{MaxClicks: 1, Status: model.LinkStatusExpired}
+	assert.True(t, clicksReset(before, model.LinkSnapshot{MaxClicks: 1, Status: model.LinkStatusActive}))
+	assert.True(t, clicksReset(before, model.LinkSnapshot{MaxClicks: 5, Status: model.LinkStatusExpired}))
+	assert.False(t, clicksReset(before, model.LinkSnapshot{MaxClicks: 1, Status: model.LinkStatusDisabled}))
+
+	// 禁用后重新启用不清零，已消耗的次数仍然有效
+	disabled := model.LinkSnapshot{MaxClicks: 1, Status: model.LinkStatusDisabled}
+	assert.False(t, clicksReset(disabled, model.LinkSnapshot{MaxClicks: 1, Status: model.LinkStatusActive}))
+}
+
+func TestRestoreSnapshot(t *testing.T) {
+	original := &model.Link{
+		LongURL:      "https://example.com/a",
+		MaxClicks:    10,
+		ForwardQuery: true,
+		Status:       model.LinkStatusFlagged,
+		Description:  "launch",
+	}
+	snap := snapshotOf(original)
+
+	link := &model.Link{ID: 1, ShortCode: "abc", LongURL: "https://example.com/b", Status: model.LinkStatusDisabled}
+	restoreSnapshot(link, &snap)
+	assert.Empty(t, diffSnapshots(snap, snapshotOf(link)))
+	assert.Equal(t, HashURL("https://example.com/a"), link.URLHash)
+	assert.Equal(t, "abc", link.ShortCode)
+}
@@ -0,0 +1,127 @@
+package link
+
+import (
+	"generate-service/internal/model"
+	"generate-service/internal/pkg/errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	maxRules    = 20 // 单个链接最多允许的跳转规则数
+	maxVariants = 10 // 单个链接最多允许的 A/B 测试分组数
+)
+
+// 校验并规范化条件跳转规则：国家代码转大写，设备、系统和语言转小写，目标地址标准化
+func (s *linkService) normalizeRules(rules []model.RedirectRule) ([]model.RedirectRule, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	if len(rules) > maxRules {
+		return nil, errors.NewBusinessError("too many redirect rules")
+	}
+	normalized := make([]model.RedirectRule, len(rules))
+	for i, rule := range rules {
+		if !rule.HasCondition() {
+			return nil, errors.ErrInvalidRule
+		}
+		for j, c := range rule.Countries {
+			if len(c) != 2 {
+				return nil, errors.ErrInvalidRule
+			}
+			rule.Countries[j] = strings.ToUpper(c)
+		}
+		for j, d := range rule.Devices {
+			rule.Devices[j] = strings.ToLower(d)
+			if !slices.Contains(model.RuleDevices, rule.Devices[j]) {
+				return nil, errors.ErrInvalidRule
+			}
+		}
+		for j, o := range rule.OS {
+			rule.OS[j] = strings.ToLower(o)
+			if !slices.Contains(model.RuleOS, rule.OS[j]) {
+				return nil, errors.ErrInvalidRule
+			}
+		}
+		for j, l := range rule.Languages {
+			rule.Languages[j] = strings.ToLower(strings.TrimSpace(l))
+		}
+		if err := validateTimeWindow(&rule); err != nil {
+			return nil, err
+		}
+		if err := s.ValidateURL(rule.Destination); err != nil {
+			return nil, err
+		}
+		destination, err := s.NormalizeURL(rule.Destination)
+		if err != nil {
+			return nil, err
+		}
+		rule.Destination = destination
+		normalized[i] = rule
+	}
+	return normalized, nil
+}
+
+// 时段起止必须同时设置，时区必须可识别
+func validateTimeWindow(rule *model.RedirectRule) error {
+	if (rule.TimeStart == "") != (rule.TimeEnd == "") {
+		return errors.ErrInvalidRule
+	}
+	if rule.TimeStart == "" {
+		if rule.Timezone != "" {
+			return errors.ErrInvalidRule
+		}
+		return nil
+	}
+	start, err := time.Parse("15:04", rule.TimeStart)
+	if err != nil {
+		return errors.ErrInvalidRule
+	}
+	end, err := time.Parse("15:04", rule.TimeEnd)
+	if err != nil || start.Equal(end) {
+		return errors.ErrInvalidRule
+	}
+	if rule.Timezone != "" {
+		if _, err := time.LoadLocation(rule.Timezone); err != nil {
+			return errors.ErrInvalidRule
+		}
+	}
+	return nil
+}
+
+// 校验并规范化 A/B 测试分组：至少两组，ID 唯一，权重之和为100，目标地址标准化
+func (s *linkService) normalizeVariants(variants []model.LinkVariant) ([]model.LinkVariant, error) {
+	if len(variants) == 0 {
+		return nil, nil
+	}
+	if len(variants) < 2 || len(variants) > maxVariants {
+		return nil, errors.ErrInvalidVariants
+	}
+	normalized := make([]model.LinkVariant, len(variants))
+	seen := make(map[string]struct{}, len(variants))
+	total := 0
+	for i, v := range variants {
+		if v.ID == "" || v.Weight <= 0 {
+			return nil, errors.ErrInvalidVariants
+		}
+		if _, ok := seen[v.ID]; ok {
+			return nil, errors.ErrInvalidVariants
+		}
+		seen[v.ID] = struct{}{}
+		total += v.Weight
+		if err := s.ValidateURL(v.Destination); err != nil {
+			return nil, err
+		}
+		destination, err := s.NormalizeURL(v.Destination)
+		if err != nil {
+			return nil, err
+		}
+		v.Destination = destination
+		normalized[i] = v
+	}
+	if total != 100 {
+		return nil, errors.ErrInvalidVariants
+	}
+	return normalized, nil
+}
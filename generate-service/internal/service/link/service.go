@@ -8,12 +8,34 @@ import (
 // Service 短链服务接口
 type Service interface {
 	CreateShortURL(ctx context.Context, req *model.CreateShortRequest) (*model.Link, error)
-	GetLink(ctx context.Context, shortCode string) (*model.Link, error)
-	GetLinkInfo(ctx context.Context, shortCode string) (*model.LinkInfoResponse, error)
-	UpdateLink(ctx context.Context, shortCode string, req *model.UpdateLinkRequest) (*model.LinkInfoResponse, error)
-	DeleteLink(ctx context.Context, shortCode string) error
+	// 以下方法中 domain 为空表示默认域名
+	GetLink(ctx context.Context, domain, shortCode string) (*model.Link, error)
+	GetLinkInfo(ctx context.Context, domain, shortCode string) (*model.LinkInfoResponse, error)
+	// GetLinkMetadata 获取链接，不校验链接状态和调用者身份，用于公开的预览页
+	GetLinkMetadata(ctx context.Context, domain, shortCode string) (*model.Link, error)
+	// UpdateLink、PatchLink 和 DeleteLink 的 ifMatch 为客户端持有的链接版本，与当前版本不一致时返回 ErrVersionConflict，nil 表示不校验
+	UpdateLink(ctx context.Context, domain, shortCode string, ifMatch *uint, req *model.UpdateLinkRequest) (*model.LinkInfoResponse, error)
+	// PatchLink 按 JSON Merge Patch（RFC 7386）部分更新链接，值为 null 的字段恢复默认值
+	PatchLink(ctx context.Context, domain, shortCode string, ifMatch *uint, patch []byte) (*model.LinkInfoResponse, error)
+	DeleteLink(ctx context.Context, domain, shortCode string, ifMatch *uint) error
+	// GetLinkHistory 查询链接的修订历史，按版本从新到旧排列
+	GetLinkHistory(ctx context.Context, domain, shortCode string) (*model.LinkHistoryResponse, error)
+	// RollbackLink 将链接恢复到指定版本，已删除的文件夹和像素不会恢复
+	RollbackLink(ctx context.Context, domain, shortCode string, version uint) (*model.LinkInfoResponse, error)
 	ListLinks(ctx context.Context, req *model.ListLinksRequest) (*model.ListLinksResponse, error)
 	BatchCreate(ctx context.Context, req *model.BatchCreateRequest) (*model.BatchCreateResponse, error)
+	// ExpireExhausted 由访问次数耗尽事件触发，不校验调用者身份
+	ExpireExhausted(ctx context.Context, workspaceID uint64, domain, shortCode string) error
+	TagLinks(ctx context.Context, req *model.BulkTagRequest) (*model.BulkTagResponse, error)
+	UntagLinks(ctx context.Context, req *model.BulkTagRequest) (*model.BulkTagResponse, error)
+	// SignLink 生成带有效期的签名地址，可绑定访问者IP或邮箱
+	SignLink(ctx context.Context, domain, shortCode string, req *model.SignLinkRequest) (*model.SignedLinkResponse, error)
+	// RefreshLinks 像素等关联数据变更后重新发送链接的缓存更新消息，不校验调用者身份
+	RefreshLinks(ctx context.Context, linkIDs []uint64) error
+	// PixelRemovalRevisions 删除像素前生成受影响链接的修订记录，不校验调用者身份
+	PixelRemovalRevisions(ctx context.Context, pixelID uint64, linkIDs []uint64, userID string) ([]*model.LinkRevision, error)
+	// RefreshWorkspace 工作空间设置变更后刷新其所有链接的缓存，不校验调用者身份
+	RefreshWorkspace(ctx context.Context, workspaceID uint64) error
 	ValidateURL(url string) error
 	NormalizeURL(url string) (string, error)
 }
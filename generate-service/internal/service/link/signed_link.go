@@ -0,0 +1,55 @@
+package link
+
+import (
+	"context"
+	"generate-service/internal/model"
+	"generate-service/internal/pkg/errors"
+	"generate-service/internal/service/auth"
+	"shared/hostname"
+	"shared/signedlink"
+	"time"
+)
+
+// SignLink 使用当前签名密钥生成签名地址，redirect-service 在本地校验签名和有效期
+func (s *linkService) SignLink(ctx context.Context, domain, shortCode string, req *model.SignLinkRequest) (*model.SignedLinkResponse, error) {
+	link, err := s.linkRepo.FindByShortCode(ctx, hostname.Normalize(domain), shortCode)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.authorize(ctx, link, auth.RoleEditor); err != nil {
+		return nil, err
+	}
+	ttl := time.Duration(req.TTL) * time.Second
+	if s.maxSignedTTL > 0 && ttl > s.maxSignedTTL {
+		return nil, errors.ErrInvalidSignedLink
+	}
+	if req.BindIP != nil && req.BindEmail != nil {
+		return nil, errors.ErrInvalidSignedLink
+	}
+	claims := signedlink.Claims{
+		Domain:    link.Domain,
+		ShortCode: link.ShortCode,
+		ExpiresAt: time.Now().Add(ttl).Truncate(time.Second),
+	}
+	switch {
+	case req.BindIP != nil:
+		claims.Binding, claims.BindValue = signedlink.BindIP, *req.BindIP
+	case req.BindEmail != nil:
+		claims.Binding, claims.BindValue = signedlink.BindEmail, *req.BindEmail
+	}
+	if s.signer == nil {
+		return nil, errors.ErrSigningUnavailable
+	}
+	query, err := s.signer.Sign(claims)
+	if err == signedlink.ErrNoSigningKey {
+		return nil, errors.ErrSigningUnavailable
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &model.SignedLinkResponse{
+		URL:       link.ShortURL(s.baseURL) + "?" + query.Encode(),
+		ExpiresAt: claims.ExpiresAt,
+		Binding:   claims.Binding,
+	}, nil
+}
@@ -1,11 +1,20 @@
 package link
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"generate-service/internal/pkg/errors"
 	"net/url"
+	"regexp"
 	"strings"
 )
 
+var (
+	// 模板占位符：{1}-{9} 取短码之后的第N段路径，{name} 取同名查询参数
+	placeholderPattern     = regexp.MustCompile(`\{([^{}]*)\}`)
+	placeholderNamePattern = regexp.MustCompile(`^([1-9]|[A-Za-z_][A-Za-z0-9_]{0,31})$`)
+)
+
 // URLValidator URL 验证器
 type URLValidator struct {
 	allowedSchemes map[string]bool
@@ -53,6 +62,40 @@ func (v *URLValidator) Validate(rawURL string) error {
 	return nil
 }
 
+// IsTemplate 是否为带占位符的目标地址模板
+func (v *URLValidator) IsTemplate(rawURL string) bool {
+	return strings.ContainsAny(rawURL, "{}")
+}
+
+// ValidateTemplate 验证目标地址模板。占位符只能出现在路径和查询参数中，
+// 协议、主机、端口、用户信息和片段必须固定，避免展开后跳转到其他站点
+func (v *URLValidator) ValidateTemplate(rawURL string) error {
+	if err := v.Validate(rawURL); err != nil {
+		return err
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return errors.ErrInvalidTemplate
+	}
+	if strings.ContainsAny(u.Scheme+u.Host+u.User.String()+u.Opaque+u.Fragment, "{}") {
+		return errors.ErrInvalidTemplate
+	}
+	matches := placeholderPattern.FindAllStringSubmatch(rawURL, -1)
+	if len(matches) == 0 {
+		return errors.ErrInvalidTemplate
+	}
+	for _, m := range matches {
+		if !placeholderNamePattern.MatchString(m[1]) {
+			return errors.ErrInvalidTemplate
+		}
+	}
+	// 去掉合法占位符后不能残留花括号
+	if strings.ContainsAny(placeholderPattern.ReplaceAllString(rawURL, ""), "{}") {
+		return errors.ErrInvalidTemplate
+	}
+	return nil
+}
+
 // 检查是否为保留域名
 func (v *URLValidator) isReservedDomain(hostname string) bool {
 	reservedDomains := []string{
@@ -98,3 +141,9 @@ func (v *URLValidator) NormalizeURL(rawURL string) (string, error) {
 
 	return u.String(), nil
 }
+
+// HashURL 计算标准化URL的SHA-256，用于长链接去重的索引查询
+func HashURL(normalizedURL string) string {
+	sum := sha256.Sum256([]byte(normalizedURL))
+	return hex.EncodeToString(sum[:])
+}
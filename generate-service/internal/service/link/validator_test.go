@@ -0,0 +1,42 @@
+package link
+
+import (
+	"generate-service/internal/pkg/errors"
+	"testing"
+)
+
+func TestValidateTemplate(t *testing.T) {
+	v := NewURLValidator()
+	valid := []string{
+		"https://jira.example.com/browse/{1}",
+		"https://search.example.com?q={q}",
+		"https://example.com/{1}/{2}?lang={lang}&page=1#top",
+	}
+	for _, raw := range valid {
+		if err := v.ValidateTemplate(raw); err != nil {
+			t.Errorf("ValidateTemplate(%q) = %v, want nil", raw, err)
+		}
+	}
+	invalid := []string{
+		"https://{1}.example.com/",     // 主机
+		"https://example.com{1}",       // 紧跟主机
+		"https://{user}@example.com/",  // 用户信息
+		"https://example.com:{port}/",  // 端口
+		"{scheme}://example.com/",      // 协议
+		"https://example.com/#{1}",     // 片段
+		"https://example.com/{0}",      // 非法序号
+		"https://example.com/{a-b}",    // 非法名称
+		"https://example.com/{1",       // 未闭合
+		"https://example.com/{{1}}",    // 嵌套
+		"javascript://example.com/{1}", // 协议不允许
+		"https://example.com/plain",    // 无占位符
+	}
+	for _, raw := range invalid {
+		if err := v.ValidateTemplate(raw); err == nil {
+			t.Errorf("ValidateTemplate(%q) = nil, want error", raw)
+		}
+	}
+	if err := v.ValidateTemplate("https://example.com/#{1}"); err != errors.ErrInvalidTemplate {
+		t.Errorf("fragment placeholder error = %v, want ErrInvalidTemplate", err)
+	}
+}
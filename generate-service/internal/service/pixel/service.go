@@ -0,0 +1,209 @@
+package pixel
+
+import (
+	"context"
+	"generate-service/internal/model"
+	"generate-service/internal/pkg/errors"
+	pixelRepo "generate-service/internal/repository/pixel"
+	"generate-service/internal/service/auth"
+	"generate-service/internal/service/idgen"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Service 重定向像素服务接口，操作范围限定在当前工作空间
+type Service interface {
+	CreatePixel(ctx context.Context, req *model.CreatePixelRequest) (*model.PixelResponse, error)
+	ListPixels(ctx context.Context) (*model.ListPixelsResponse, error)
+	UpdatePixel(ctx context.Context, id string, req *model.UpdatePixelRequest) (*model.PixelResponse, error)
+	DeletePixel(ctx context.Context, id string) error
+}
+
+// LinkRefresher 像素变更后刷新引用它的链接缓存，删除像素时生成链接的修订记录，由短链服务实现
+type LinkRefresher interface {
+	RefreshLinks(ctx context.Context, linkIDs []uint64) error
+	PixelRemovalRevisions(ctx context.Context, pixelID uint64, linkIDs []uint64, userID string) ([]*model.LinkRevision, error)
+}
+
+type pixelService struct {
+	pixelRepo   pixelRepo.Repository
+	idGenerator idgen.Generator
+	links       LinkRefresher
+}
+
+// NewService 创建重定向像素服务实例
+func NewService(repo pixelRepo.Repository, idGenerator idgen.Generator, links LinkRefresher) Service {
+	return &pixelService{
+		pixelRepo:   repo,
+		idGenerator: idGenerator,
+		links:       links,
+	}
+}
+
+// CreatePixel 在当前工作空间创建像素，名称在工作空间内唯一
+func (s *pixelService) CreatePixel(ctx context.Context, req *model.CreatePixelRequest) (*model.PixelResponse, error) {
+	p, err := requireRole(ctx, auth.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.NewBusinessError("pixel name is required")
+	}
+	trackingID := strings.TrimSpace(req.TrackingID)
+	if !model.ValidPixelTrackingID(req.Provider, trackingID) {
+		return nil, errors.ErrInvalidPixel
+	}
+	id, err := s.idGenerator.NextId()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	pixel := &model.Pixel{
+		ID:          id,
+		WorkspaceID: p.WorkspaceID,
+		Name:        name,
+		Provider:    req.Provider,
+		TrackingID:  trackingID,
+		CreatedAt:   now,
+		CreatedBy:   p.UserID,
+		UpdatedAt:   now,
+		UpdatedBy:   p.UserID,
+		DeleteFlag:  "N",
+	}
+	if err := s.pixelRepo.Create(ctx, pixel); err != nil {
+		return nil, err
+	}
+	resp := toPixelResponse(pixel)
+	return &resp, nil
+}
+
+// ListPixels 查询当前工作空间的像素
+func (s *pixelService) ListPixels(ctx context.Context) (*model.ListPixelsResponse, error) {
+	p, err := requireRole(ctx, auth.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	pixels, err := s.pixelRepo.ListByWorkspace(ctx, p.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &model.ListPixelsResponse{Pixels: make([]model.PixelResponse, len(pixels))}
+	for i := range pixels {
+		resp.Pixels[i] = toPixelResponse(&pixels[i])
+	}
+	return resp, nil
+}
+
+// UpdatePixel 修改像素名称或跟踪ID，跟踪ID变更后刷新引用它的链接缓存
+func (s *pixelService) UpdatePixel(ctx context.Context, id string, req *model.UpdatePixelRequest) (*model.PixelResponse, error) {
+	p, err := requireRole(ctx, auth.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	pixel, err := s.findPixel(ctx, p.WorkspaceID, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, errors.NewBusinessError("pixel name is required")
+		}
+		pixel.Name = name
+	}
+	trackingChanged := false
+	if req.TrackingID != nil {
+		trackingID := strings.TrimSpace(*req.TrackingID)
+		if !model.ValidPixelTrackingID(pixel.Provider, trackingID) {
+			return nil, errors.ErrInvalidPixel
+		}
+		trackingChanged = trackingID != pixel.TrackingID
+		pixel.TrackingID = trackingID
+	}
+	pixel.UpdatedBy = p.UserID
+	if err := s.pixelRepo.Update(ctx, pixel); err != nil {
+		return nil, err
+	}
+	if trackingChanged {
+		if err := s.refreshLinks(ctx, pixel.ID); err != nil {
+			return nil, err
+		}
+	}
+	resp := toPixelResponse(pixel)
+	return &resp, nil
+}
+
+// DeletePixel 删除像素并从所有链接上移除，仅管理员可操作
+func (s *pixelService) DeletePixel(ctx context.Context, id string) error {
+	p, err := requireRole(ctx, auth.RoleAdmin)
+	if err != nil {
+		return err
+	}
+	pixel, err := s.findPixel(ctx, p.WorkspaceID, id)
+	if err != nil {
+		return err
+	}
+	// 删除会同时移除关联，需先记下受影响的链接，移除像素记为这些链接的一次更新
+	linkIDs, err := s.pixelRepo.ListLinkIDs(ctx, pixel.ID)
+	if err != nil {
+		return err
+	}
+	revs, err := s.links.PixelRemovalRevisions(ctx, pixel.ID, linkIDs, p.UserID)
+	if err != nil {
+		return err
+	}
+	if err := s.pixelRepo.Delete(ctx, pixel, revs); err != nil {
+		return err
+	}
+	return s.links.RefreshLinks(ctx, linkIDs)
+}
+
+func (s *pixelService) refreshLinks(ctx context.Context, pixelID uint64) error {
+	linkIDs, err := s.pixelRepo.ListLinkIDs(ctx, pixelID)
+	if err != nil {
+		return err
+	}
+	return s.links.RefreshLinks(ctx, linkIDs)
+}
+
+// 查询当前工作空间的像素，其他工作空间的像素对外表现为不存在
+func (s *pixelService) findPixel(ctx context.Context, workspaceID uint64, id string) (*model.Pixel, error) {
+	pixelID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil, errors.ErrPixelNotFound
+	}
+	pixel, err := s.pixelRepo.FindByID(ctx, pixelID)
+	if err != nil {
+		return nil, err
+	}
+	if pixel.WorkspaceID != workspaceID {
+		return nil, errors.ErrPixelNotFound
+	}
+	return pixel, nil
+}
+
+func requireRole(ctx context.Context, required auth.Role) (*auth.Principal, error) {
+	p, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, errors.ErrUnauthorized
+	}
+	if p.WorkspaceID == 0 {
+		return nil, errors.ErrWorkspaceRequired
+	}
+	if !p.Role.AtLeast(required) {
+		return nil, errors.ErrForbidden
+	}
+	return p, nil
+}
+
+func toPixelResponse(p *model.Pixel) model.PixelResponse {
+	return model.PixelResponse{
+		ID:         strconv.FormatUint(p.ID, 10),
+		Name:       p.Name,
+		Provider:   p.Provider,
+		TrackingID: p.TrackingID,
+		CreatedAt:  p.CreatedAt,
+	}
+}
@@ -0,0 +1,164 @@
+package tag
+
+import (
+	"context"
+	"generate-service/internal/model"
+	"generate-service/internal/pkg/errors"
+	tagRepo "generate-service/internal/repository/tag"
+	"generate-service/internal/service/auth"
+	"generate-service/internal/service/idgen"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Service 标签服务接口，操作范围限定在当前工作空间
+type Service interface {
+	CreateTag(ctx context.Context, req *model.CreateTagRequest) (*model.TagResponse, error)
+	ListTags(ctx context.Context) (*model.ListTagsResponse, error)
+	UpdateTag(ctx context.Context, id string, req *model.UpdateTagRequest) (*model.TagResponse, error)
+	DeleteTag(ctx context.Context, id string) error
+}
+
+type tagService struct {
+	tagRepo     tagRepo.Repository
+	idGenerator idgen.Generator
+}
+
+// NewService 创建标签服务实例
+func NewService(repo tagRepo.Repository, idGenerator idgen.Generator) Service {
+	return &tagService{
+		tagRepo:     repo,
+		idGenerator: idGenerator,
+	}
+}
+
+// CreateTag 在当前工作空间创建标签，名称在工作空间内唯一
+func (s *tagService) CreateTag(ctx context.Context, req *model.CreateTagRequest) (*model.TagResponse, error) {
+	p, err := requireRole(ctx, auth.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.NewBusinessError("tag name is required")
+	}
+	id, err := s.idGenerator.NextId()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	tag := &model.Tag{
+		ID:          id,
+		WorkspaceID: p.WorkspaceID,
+		Name:        name,
+		Color:       req.Color,
+		CreatedAt:   now,
+		CreatedBy:   p.UserID,
+		UpdatedAt:   now,
+		UpdatedBy:   p.UserID,
+		DeleteFlag:  "N",
+	}
+	if err := s.tagRepo.Create(ctx, tag); err != nil {
+		return nil, err
+	}
+	resp := toTagResponse(tag)
+	return &resp, nil
+}
+
+// ListTags 查询当前工作空间的标签
+func (s *tagService) ListTags(ctx context.Context) (*model.ListTagsResponse, error) {
+	p, err := requireRole(ctx, auth.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := s.tagRepo.ListByWorkspace(ctx, p.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &model.ListTagsResponse{Tags: make([]model.TagResponse, len(tags))}
+	for i := range tags {
+		resp.Tags[i] = toTagResponse(&tags[i])
+	}
+	return resp, nil
+}
+
+// UpdateTag 修改标签名称或颜色
+func (s *tagService) UpdateTag(ctx context.Context, id string, req *model.UpdateTagRequest) (*model.TagResponse, error) {
+	p, err := requireRole(ctx, auth.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	tag, err := s.findTag(ctx, p.WorkspaceID, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, errors.NewBusinessError("tag name is required")
+		}
+		tag.Name = name
+	}
+	if req.Color != nil {
+		tag.Color = *req.Color
+	}
+	tag.UpdatedBy = p.UserID
+	if err := s.tagRepo.Update(ctx, tag); err != nil {
+		return nil, err
+	}
+	resp := toTagResponse(tag)
+	return &resp, nil
+}
+
+// DeleteTag 删除标签并从所有链接上移除，仅管理员可操作
+func (s *tagService) DeleteTag(ctx context.Context, id string) error {
+	p, err := requireRole(ctx, auth.RoleAdmin)
+	if err != nil {
+		return err
+	}
+	tag, err := s.findTag(ctx, p.WorkspaceID, id)
+	if err != nil {
+		return err
+	}
+	return s.tagRepo.Delete(ctx, tag)
+}
+
+// 查询当前工作空间的标签，其他工作空间的标签对外表现为不存在
+func (s *tagService) findTag(ctx context.Context, workspaceID uint64, id string) (*model.Tag, error) {
+	tagID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil, errors.ErrTagNotFound
+	}
+	tag, err := s.tagRepo.FindByID(ctx, tagID)
+	if err != nil {
+		return nil, err
+	}
+	if tag.WorkspaceID != workspaceID {
+		return nil, errors.ErrTagNotFound
+	}
+	return tag, nil
+}
+
+func requireRole(ctx context.Context, required auth.Role) (*auth.Principal, error) {
+	p, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, errors.ErrUnauthorized
+	}
+	if p.WorkspaceID == 0 {
+		return nil, errors.ErrWorkspaceRequired
+	}
+	if !p.Role.AtLeast(required) {
+		return nil, errors.ErrForbidden
+	}
+	return p, nil
+}
+
+func toTagResponse(t *model.Tag) model.TagResponse {
+	return model.TagResponse{
+		ID:        strconv.FormatUint(t.ID, 10),
+		Name:      t.Name,
+		Color:     t.Color,
+		CreatedAt: t.CreatedAt,
+	}
+}
@@ -0,0 +1,298 @@
+package workspace
+
+import (
+	"context"
+	"generate-service/internal/model"
+	"generate-service/internal/pkg/errors"
+	workspaceRepo "generate-service/internal/repository/workspace"
+	"generate-service/internal/service/auth"
+	"generate-service/internal/service/idgen"
+	"strconv"
+	"time"
+)
+
+// Service 工作空间服务接口
+type Service interface {
+	// Enter 校验当前用户的成员身份，返回限定在该工作空间内的上下文
+	Enter(ctx context.Context, workspaceID uint64) (context.Context, error)
+	CreateWorkspace(ctx context.Context, req *model.CreateWorkspaceRequest) (*model.WorkspaceResponse, error)
+	ListWorkspaces(ctx context.Context) (*model.ListWorkspacesResponse, error)
+	GetWorkspace(ctx context.Context, id string) (*model.WorkspaceResponse, error)
+	ListMembers(ctx context.Context, id string) (*model.ListMembersResponse, error)
+	AddMember(ctx context.Context, id string, req *model.AddMemberRequest) (*model.MemberResponse, error)
+	RemoveMember(ctx context.Context, id string, userID string) error
+	UpdateIPAccess(ctx context.Context, id string, req *model.IPAccess) (*model.WorkspaceResponse, error)
+}
+
+// LinkRefresher 工作空间设置变更后刷新其链接的缓存，由短链服务实现
+type LinkRefresher interface {
+	RefreshWorkspace(ctx context.Context, workspaceID uint64) error
+}
+
+type workspaceService struct {
+	workspaceRepo workspaceRepo.Repository
+	idGenerator   idgen.Generator
+	links         LinkRefresher
+}
+
+// NewService 创建工作空间服务实例
+func NewService(repo workspaceRepo.Repository, idGenerator idgen.Generator, links LinkRefresher) Service {
+	return &workspaceService{
+		workspaceRepo: repo,
+		idGenerator:   idGenerator,
+		links:         links,
+	}
+}
+
+// Enter 进入工作空间，非成员统一返回不存在，避免泄露其他租户信息
+func (s *workspaceService) Enter(ctx context.Context, workspaceID uint64) (context.Context, error) {
+	p, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, errors.ErrUnauthorized
+	}
+	member, err := s.workspaceRepo.FindMember(ctx, workspaceID, p.UserID)
+	if err != nil {
+		if err == errors.ErrMemberNotFound {
+			return nil, errors.ErrWorkspaceNotFound
+		}
+		return nil, err
+	}
+	return auth.WithPrincipal(ctx, p.InWorkspace(workspaceID, auth.Role(member.Role))), nil
+}
+
+// CreateWorkspace 创建工作空间，创建者成为管理员
+func (s *workspaceService) CreateWorkspace(ctx context.Context, req *model.CreateWorkspaceRequest) (*model.WorkspaceResponse, error) {
+	p, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, errors.ErrUnauthorized
+	}
+	if !p.Role.AtLeast(auth.RoleEditor) {
+		return nil, errors.ErrForbidden
+	}
+	wsID, err := s.idGenerator.NextId()
+	if err != nil {
+		return nil, err
+	}
+	memberID, err := s.idGenerator.NextId()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	ws := &model.Workspace{
+		ID:         wsID,
+		Name:       req.Name,
+		Slug:       req.Slug,
+		CreatedAt:  now,
+		CreatedBy:  p.UserID,
+		UpdatedAt:  now,
+		UpdatedBy:  p.UserID,
+		DeleteFlag: "N",
+	}
+	if req.MaxLinks != nil {
+		ws.MaxLinks = *req.MaxLinks
+	}
+	if req.Settings != nil {
+		ws.Settings = *req.Settings
+		ws.Settings.IPAccess, err = req.Settings.IPAccess.Normalize()
+		if err != nil {
+			return nil, errors.ErrInvalidIPAccess
+		}
+	}
+	if req.Description != nil {
+		ws.Description = *req.Description
+	}
+	owner := &model.WorkspaceMember{
+		ID:          memberID,
+		WorkspaceID: wsID,
+		UserID:      p.UserID,
+		Role:        string(auth.RoleAdmin),
+		CreatedAt:   now,
+		CreatedBy:   p.UserID,
+		UpdatedAt:   now,
+		UpdatedBy:   p.UserID,
+		DeleteFlag:  "N",
+	}
+	if err := s.workspaceRepo.Create(ctx, ws, owner); err != nil {
+		return nil, err
+	}
+	resp := toWorkspaceResponse(ws)
+	resp.Role = owner.Role
+	return &resp, nil
+}
+
+// ListWorkspaces 查询当前用户所属的工作空间
+func (s *workspaceService) ListWorkspaces(ctx context.Context) (*model.ListWorkspacesResponse, error) {
+	p, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, errors.ErrUnauthorized
+	}
+	workspaces, members, err := s.workspaceRepo.ListByUser(ctx, p.UserID)
+	if err != nil {
+		return nil, err
+	}
+	roles := make(map[uint64]string, len(members))
+	for _, m := range members {
+		roles[m.WorkspaceID] = m.Role
+	}
+	resp := &model.ListWorkspacesResponse{Workspaces: make([]model.WorkspaceResponse, len(workspaces))}
+	for i := range workspaces {
+		resp.Workspaces[i] = toWorkspaceResponse(&workspaces[i])
+		resp.Workspaces[i].Role = roles[workspaces[i].ID]
+	}
+	return resp, nil
+}
+
+// GetWorkspace 查询工作空间详情，仅成员可见
+func (s *workspaceService) GetWorkspace(ctx context.Context, id string) (*model.WorkspaceResponse, error) {
+	p, err := s.enterByID(ctx, id, auth.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	ws, err := s.workspaceRepo.FindByID(ctx, p.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	resp := toWorkspaceResponse(ws)
+	resp.Role = string(p.ManagementRole())
+	return &resp, nil
+}
+
+// ListMembers 查询成员列表，仅成员可见
+func (s *workspaceService) ListMembers(ctx context.Context, id string) (*model.ListMembersResponse, error) {
+	p, err := s.enterByID(ctx, id, auth.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.workspaceRepo.ListMembers(ctx, p.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &model.ListMembersResponse{Members: make([]model.MemberResponse, len(members))}
+	for i := range members {
+		resp.Members[i] = toMemberResponse(&members[i])
+	}
+	return resp, nil
+}
+
+// AddMember 添加成员，仅管理员可操作
+func (s *workspaceService) AddMember(ctx context.Context, id string, req *model.AddMemberRequest) (*model.MemberResponse, error) {
+	p, err := s.enterByID(ctx, id, auth.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	memberID, err := s.idGenerator.NextId()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	member := &model.WorkspaceMember{
+		ID:          memberID,
+		WorkspaceID: p.WorkspaceID,
+		UserID:      req.UserID,
+		Role:        req.Role,
+		CreatedAt:   now,
+		CreatedBy:   p.UserID,
+		UpdatedAt:   now,
+		UpdatedBy:   p.UserID,
+		DeleteFlag:  "N",
+	}
+	if err := s.workspaceRepo.AddMember(ctx, member); err != nil {
+		return nil, err
+	}
+	resp := toMemberResponse(member)
+	return &resp, nil
+}
+
+// RemoveMember 移除成员，仅管理员可操作，且不能移除最后一个管理员
+func (s *workspaceService) RemoveMember(ctx context.Context, id string, userID string) error {
+	p, err := s.enterByID(ctx, id, auth.RoleAdmin)
+	if err != nil {
+		return err
+	}
+	members, err := s.workspaceRepo.ListMembers(ctx, p.WorkspaceID)
+	if err != nil {
+		return err
+	}
+	var target *model.WorkspaceMember
+	admins := 0
+	for i := range members {
+		if members[i].Role == string(auth.RoleAdmin) {
+			admins++
+		}
+		if members[i].UserID == userID {
+			target = &members[i]
+		}
+	}
+	if target == nil {
+		return errors.ErrMemberNotFound
+	}
+	if target.Role == string(auth.RoleAdmin) && admins <= 1 {
+		return errors.NewBusinessError("cannot remove the last admin of a workspace")
+	}
+	return s.workspaceRepo.RemoveMember(ctx, target)
+}
+
+// UpdateIPAccess 整体替换工作空间的IP访问控制列表，仅管理员可操作，空对象表示清除。
+// 变更对工作空间内所有链接生效，保存后刷新链接缓存
+func (s *workspaceService) UpdateIPAccess(ctx context.Context, id string, req *model.IPAccess) (*model.WorkspaceResponse, error) {
+	p, err := s.enterByID(ctx, id, auth.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	ipAccess, err := req.Normalize()
+	if err != nil {
+		return nil, errors.ErrInvalidIPAccess
+	}
+	ws, err := s.workspaceRepo.FindByID(ctx, p.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	ws.Settings.IPAccess = ipAccess
+	ws.UpdatedBy = p.UserID
+	if err := s.workspaceRepo.UpdateSettings(ctx, ws); err != nil {
+		return nil, err
+	}
+	if err := s.links.RefreshWorkspace(ctx, ws.ID); err != nil {
+		return nil, err
+	}
+	resp := toWorkspaceResponse(ws)
+	resp.Role = string(p.ManagementRole())
+	return &resp, nil
+}
+
+// 解析路径中的工作空间ID并按管理角色检查权限
+func (s *workspaceService) enterByID(ctx context.Context, id string, required auth.Role) (*auth.Principal, error) {
+	workspaceID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil, errors.ErrWorkspaceNotFound
+	}
+	scopedCtx, err := s.Enter(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	p, _ := auth.FromContext(scopedCtx)
+	if !p.ManagementRole().AtLeast(required) {
+		return nil, errors.ErrForbidden
+	}
+	return p, nil
+}
+
+func toWorkspaceResponse(ws *model.Workspace) model.WorkspaceResponse {
+	return model.WorkspaceResponse{
+		ID:          strconv.FormatUint(ws.ID, 10),
+		Name:        ws.Name,
+		Slug:        ws.Slug,
+		MaxLinks:    ws.MaxLinks,
+		Settings:    ws.Settings,
+		CreatedAt:   ws.CreatedAt,
+		Description: ws.Description,
+	}
+}
+
+func toMemberResponse(m *model.WorkspaceMember) model.MemberResponse {
+	return model.MemberResponse{
+		UserID:    m.UserID,
+		Role:      m.Role,
+		CreatedAt: m.CreatedAt,
+	}
+}
@@ -5,11 +5,29 @@ USE short_url;
 -- 短链映射表
 CREATE TABLE IF NOT EXISTS links (
     id BIGINT PRIMARY KEY,
-    short_code VARCHAR(10) NOT NULL UNIQUE,
+    workspace_id BIGINT NOT NULL,
+    domain VARCHAR(253) NOT NULL DEFAULT '' COMMENT '自定义域名，空表示默认域名',
+    short_code VARCHAR(10) NOT NULL,
     long_url TEXT NOT NULL,
+    url_hash CHAR(64) NOT NULL DEFAULT '' COMMENT '标准化长链接的SHA-256，用于去重',
+    folder_id BIGINT NOT NULL DEFAULT 0 COMMENT '所属文件夹，0表示未归入文件夹',
+    password_hash VARCHAR(100) NOT NULL DEFAULT '' COMMENT '访问密码的bcrypt哈希，空表示无需密码',
+    activate_at TIMESTAMP NULL COMMENT '生效时间，NULL表示立即生效',
     expires_at TIMESTAMP NULL,
     click_count BIGINT UNSIGNED DEFAULT 0,
-    status ENUM('active', 'disabled', 'expired') DEFAULT 'active',
+    max_clicks BIGINT NOT NULL DEFAULT 0 COMMENT '最大访问次数，0表示不限制',
+    rules JSON NULL COMMENT '条件跳转规则，按顺序匹配',
+    variants JSON NULL COMMENT 'A/B测试分组及权重',
+    deep_link JSON NULL COMMENT '移动端深度链接',
+    open_graph JSON NULL COMMENT '分享卡片覆盖值',
+    ip_access JSON NULL COMMENT 'IP访问控制列表',
+    forward_query TINYINT(1) NOT NULL DEFAULT 0 COMMENT '是否转发查询参数',
+    query_conflict VARCHAR(20) NOT NULL DEFAULT '' COMMENT '查询参数同名时的处理方式',
+    forward_path TINYINT(1) NOT NULL DEFAULT 0 COMMENT '是否转发短码之后的路径',
+    template TINYINT(1) NOT NULL DEFAULT 0 COMMENT '长链接是否为带占位符的目标地址模板',
+    redirect_type VARCHAR(20) NOT NULL DEFAULT '' COMMENT '跳转方式：301/302/307/308/meta_refresh/javascript，空表示302',
+    signed_only TINYINT(1) NOT NULL DEFAULT 0 COMMENT '是否只允许通过签名地址访问',
+    status ENUM('active', 'disabled', 'expired', 'flagged') DEFAULT 'active',
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     created_by VARCHAR(100),
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
@@ -17,7 +35,13 @@ CREATE TABLE IF NOT EXISTS links (
     description VARCHAR(100),
     delete_flag varchar(1) DEFAULT 'N',
     version INT UNSIGNED DEFAULT 0,
-    INDEX idx_short_code (short_code)
+    UNIQUE INDEX uk_domain_code (domain, short_code),
+    INDEX idx_workspace_created (workspace_id, created_by),
+    INDEX idx_owner_url_hash (workspace_id, created_by, url_hash),
+    INDEX idx_workspace_created_at (workspace_id, created_at, id),
+    INDEX idx_workspace_clicks (workspace_id, click_count, id),
+    INDEX idx_workspace_expires (workspace_id, expires_at, id),
+    INDEX idx_folder_id (folder_id)
 ) COMMENT '短链映射表';
 
 -- 缓存预热记录表
@@ -34,4 +58,160 @@ CREATE TABLE cache_warmup_logs (
     delete_flag varchar(1) DEFAULT 'N',
     version INT UNSIGNED DEFAULT 0,
     INDEX idx_short_code (short_code)
-) COMMENT '缓存预热记录';
\ No newline at end of file
+) COMMENT '缓存预热记录';
+
+-- API密钥表（只保存SHA-256摘要）
+CREATE TABLE IF NOT EXISTS api_keys (
+    id BIGINT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    key_prefix VARCHAR(16) NOT NULL,
+    key_hash CHAR(64) NOT NULL,
+    owner_id VARCHAR(100) NOT NULL,
+    role ENUM('viewer', 'editor', 'admin') NOT NULL,
+    status ENUM('active', 'revoked') DEFAULT 'active',
+    expires_at TIMESTAMP NULL,
+    last_used_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    created_by VARCHAR(100),
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    updated_by VARCHAR(100),
+    description VARCHAR(500),
+    delete_flag varchar(1) DEFAULT 'N',
+    version INT UNSIGNED DEFAULT 0,
+    UNIQUE INDEX uk_key_hash (key_hash),
+    INDEX idx_owner_id (owner_id)
+) COMMENT 'API密钥表';
+
+-- 工作空间表（租户）
+CREATE TABLE IF NOT EXISTS workspaces (
+    id BIGINT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    slug VARCHAR(50) NOT NULL,
+    max_links BIGINT DEFAULT 0 COMMENT '链接数量上限，0表示不限制',
+    settings JSON,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    created_by VARCHAR(100),
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    updated_by VARCHAR(100),
+    description VARCHAR(500),
+    delete_flag varchar(1) DEFAULT 'N',
+    version INT UNSIGNED DEFAULT 0,
+    UNIQUE INDEX uk_slug (slug)
+) COMMENT '工作空间表';
+
+-- 工作空间成员表
+CREATE TABLE IF NOT EXISTS workspace_members (
+    id BIGINT PRIMARY KEY,
+    workspace_id BIGINT NOT NULL,
+    user_id VARCHAR(100) NOT NULL,
+    role ENUM('viewer', 'editor', 'admin') NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    created_by VARCHAR(100),
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    updated_by VARCHAR(100),
+    delete_flag varchar(1) DEFAULT 'N',
+    version INT UNSIGNED DEFAULT 0,
+    UNIQUE INDEX uk_workspace_user (workspace_id, user_id),
+    INDEX idx_user_id (user_id)
+) COMMENT '工作空间成员表';
+
+-- 自定义域名表，每个域名拥有独立的短码命名空间
+CREATE TABLE IF NOT EXISTS domains (
+    id BIGINT PRIMARY KEY,
+    workspace_id BIGINT NOT NULL,
+    hostname VARCHAR(253) NOT NULL,
+    verification_token VARCHAR(64) NOT NULL COMMENT '所有权验证令牌，需发布为 DNS TXT 记录',
+    verified_hostname VARCHAR(253) NULL COMMENT '验证通过后写入主机名，保证同一主机名只归属一个工作空间',
+    verified_at TIMESTAMP NULL COMMENT '所有权验证通过时间，未验证的域名不能创建链接',
+    apple_app_ids JSON NULL COMMENT '关联的 iOS App ID',
+    android_apps JSON NULL COMMENT '关联的 Android App 包名及证书指纹',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    created_by VARCHAR(100),
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    updated_by VARCHAR(100),
+    delete_flag varchar(1) DEFAULT 'N',
+    version INT UNSIGNED DEFAULT 0,
+    UNIQUE INDEX uk_workspace_hostname (workspace_id, hostname),
+    UNIQUE INDEX uk_verified_hostname (verified_hostname),
+    INDEX idx_hostname (hostname)
+) COMMENT '自定义域名表';
+
+-- 标签表
+CREATE TABLE IF NOT EXISTS tags (
+    id BIGINT PRIMARY KEY,
+    workspace_id BIGINT NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    color VARCHAR(20),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    created_by VARCHAR(100),
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    updated_by VARCHAR(100),
+    delete_flag varchar(1) DEFAULT 'N',
+    version INT UNSIGNED DEFAULT 0,
+    UNIQUE INDEX uk_workspace_tag (workspace_id, name)
+) COMMENT '标签表';
+
+-- 链接标签关联表
+CREATE TABLE IF NOT EXISTS link_tags (
+    link_id BIGINT NOT NULL,
+    tag_id BIGINT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    created_by VARCHAR(100),
+    PRIMARY KEY (link_id, tag_id),
+    INDEX idx_tag_id (tag_id)
+) COMMENT '链接标签关联表';
+
+-- 重定向像素表
+CREATE TABLE IF NOT EXISTS pixels (
+    id BIGINT PRIMARY KEY,
+    workspace_id BIGINT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    provider VARCHAR(20) NOT NULL,
+    tracking_id VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    created_by VARCHAR(100),
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    updated_by VARCHAR(100),
+    delete_flag varchar(1) DEFAULT 'N',
+    version INT UNSIGNED DEFAULT 0,
+    UNIQUE INDEX uk_workspace_pixel (workspace_id, name)
+) COMMENT '重定向像素表';
+
+-- 链接像素关联表
+CREATE TABLE IF NOT EXISTS link_pixels (
+    link_id BIGINT NOT NULL,
+    pixel_id BIGINT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    created_by VARCHAR(100),
+    PRIMARY KEY (link_id, pixel_id),
+    INDEX idx_pixel_id (pixel_id)
+) COMMENT '链接像素关联表';
+
+-- 文件夹表，parent_id 为0表示顶级文件夹
+CREATE TABLE IF NOT EXISTS folders (
+    id BIGINT PRIMARY KEY,
+    workspace_id BIGINT NOT NULL,
+    parent_id BIGINT NOT NULL DEFAULT 0,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    created_by VARCHAR(100),
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    updated_by VARCHAR(100),
+    delete_flag varchar(1) DEFAULT 'N',
+    version INT UNSIGNED DEFAULT 0,
+    UNIQUE INDEX uk_workspace_parent_name (workspace_id, parent_id, name),
+    INDEX idx_parent_id (parent_id)
+) COMMENT '文件夹表';
+
+-- 链接修订记录表，每次变更写入一条，不修改不删除
+CREATE TABLE IF NOT EXISTS link_revisions (
+    link_id BIGINT NOT NULL,
+    version INT UNSIGNED NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    restored_version INT UNSIGNED NULL,
+    snapshot JSON NOT NULL,
+    changes JSON NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    created_by VARCHAR(100),
+    PRIMARY KEY (link_id, version)
+) COMMENT '链接修订记录表';
@@ -6,10 +6,13 @@ import (
 	"log"
 	"redirect-service/internal/client/etcd"
 	"redirect-service/internal/config"
+	shrErrors "shared/errors"
 	pb "shared/proto/generate"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 )
 
 type Client struct {
@@ -46,17 +49,54 @@ func NewClient(cfg *config.GenerateService, etcdCfg *etcdresolver.EtcdConfig) (*
 	}, nil
 }
 
-func (c *Client) GetOriginalURL(ctx context.Context, shortCode string) (*pb.GetOriginalUrlResponse, error) {
+func (c *Client) GetOriginalURL(ctx context.Context, domain, shortCode string) (*pb.GetOriginalUrlResponse, error) {
 	// 设置超时
 	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
 	defer cancel()
 
 	resp, err := c.client.GetOriginalUrl(ctx, &pb.GetOriginalUrlRequest{
 		ShortCode: shortCode,
+		Domain:    domain,
 	})
 	if err != nil {
-		log.Printf("gRPC call failed for %s: %v", shortCode, err)
-		return nil, err
+		log.Printf("gRPC call failed for %s/%s: %v", domain, shortCode, err)
+		return nil, toBusinessError(err)
 	}
 	return resp, nil
 }
+
+// GetLinkMetadata 获取链接元数据，不区分链接状态
+func (c *Client) GetLinkMetadata(ctx context.Context, domain, shortCode string) (*pb.GetLinkMetadataResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+
+	resp, err := c.client.GetLinkMetadata(ctx, &pb.GetLinkMetadataRequest{
+		ShortCode: shortCode,
+		Domain:    domain,
+	})
+	if err != nil {
+		log.Printf("gRPC call failed for %s/%s: %v", domain, shortCode, err)
+		return nil, toBusinessError(err)
+	}
+	return resp, nil
+}
+
+// 将 generate-service 返回的状态码还原为业务错误
+func toBusinessError(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	switch st.Code() {
+	case codes.NotFound:
+		return shrErrors.ErrLinkNotFound
+	case codes.FailedPrecondition:
+		switch st.Message() {
+		case shrErrors.ErrLinkExpired.Error():
+			return shrErrors.ErrLinkExpired
+		case shrErrors.ErrLinkDisabled.Error():
+			return shrErrors.ErrLinkDisabled
+		}
+	}
+	return err
+}
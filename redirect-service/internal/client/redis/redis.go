@@ -155,6 +155,41 @@ func (c *Client) Del(ctx context.Context, key string) error {
 	return err
 }
 
+// RunScript 执行 Lua 脚本，保证多个键的读写原子性
+func (c *Client) RunScript(ctx context.Context, script *redis.Script, keys []string, args ...interface{}) (interface{}, error) {
+	operation := func() (interface{}, error) {
+		val, err := script.Run(ctx, c.client, keys, args...).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			metrics.RedisErrorsTotal.WithLabelValues("script").Inc()
+			return nil, err
+		}
+		return val, nil
+	}
+	result, err := c.rcb.Execute(operation)
+	if err != nil {
+		log.Printf("%s", err.Error())
+		if errors.Is(err, gobreaker.ErrOpenState) {
+			// 触发熔断，记录熔断次数
+			metrics.RedisErrorsTotal.WithLabelValues("circuit_breaker").Inc()
+			return nil, errors2.ErrBreakerOpen
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
+// RecordLookup 记录一次缓存查询结果，用于脚本读取等不经过 Get 的场景
+func (c *Client) RecordLookup(hit bool) {
+	if hit {
+		atomic.AddUint64(&c.hits, 1)
+		metrics.RedisHitsTotal.Inc()
+	} else {
+		atomic.AddUint64(&c.misses, 1)
+		metrics.RedismissesTotal.Inc()
+	}
+	c.updateHitRatio()
+}
+
 // 更新缓存命中率
 func (c *Client) updateHitRatio() {
 	hits := atomic.LoadUint64(&c.hits)
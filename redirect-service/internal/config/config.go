@@ -4,13 +4,22 @@ import (
 	"redirect-service/internal/client/etcd"
 	"redirect-service/internal/pkg/idgen"
 	"redirect-service/internal/service/circuitbreaker"
+	"shared/signedlink"
 	"time"
 )
 
 type ServerConfig struct {
-	Port int    `mapstructure:"port"`
-	Host string `mapstructure:"host"`
-	Mode string `mapstructure:"mode"`
+	Port         int      `mapstructure:"port"`
+	Host         string   `mapstructure:"host"`
+	Mode         string   `mapstructure:"mode"`
+	DefaultHosts []string `mapstructure:"default_hosts"` // 默认域名的主机名列表，其他主机名按自定义域名解析
+}
+
+// ClientIPConfig 客户端IP解析配置
+type ClientIPConfig struct {
+	TrustedProxies   []string `mapstructure:"trusted_proxies"`    // 可信代理的地址段，只有来自这些地址的请求才读取转发头
+	TrustedHeader    string   `mapstructure:"trusted_header"`     // 可信代理写入客户端地址的请求头：forwarded、x-forwarded-for 或 x-real-ip
+	SimulateRandomIP bool     `mapstructure:"simulate_random_ip"` // 仅开发环境：以随机公网IP模拟访问者，release 模式下忽略
 }
 
 type RedisConfig struct {
@@ -71,8 +80,47 @@ type GeoIPConfig struct {
 	DBPath string `mapstructure:"db_path"`
 }
 
+// PasswordConfig 密码保护链接配置
+type PasswordConfig struct {
+	CookieSecret  string `mapstructure:"cookie_secret"`  // 访问凭证 Cookie 的签名密钥，多实例部署时必须一致
+	CookieTTL     int    `mapstructure:"cookie_ttl"`     // 访问凭证有效期（秒）
+	MaxAttempts   int    `mapstructure:"max_attempts"`   // 窗口内单个IP允许的密码错误次数
+	AttemptWindow int    `mapstructure:"attempt_window"` // 错误次数统计窗口（秒）
+}
+
+// ScheduleConfig 定时生效链接配置
+type ScheduleConfig struct {
+	PendingStatus      int    `mapstructure:"pending_status"`       // 生效前访问返回的状态码
+	PendingRedirectURL string `mapstructure:"pending_redirect_url"` // 生效前跳转的地址，为空时展示"尚未生效"页面
+	ShowActivateTime   bool   `mapstructure:"show_activate_time"`   // 页面是否展示生效时间
+}
+
+// AccessConfig IP访问控制配置
+type AccessConfig struct {
+	DeniedRedirectURL string `mapstructure:"denied_redirect_url"` // 访问被拒绝时跳转的地址，为空时返回 403 和"拒绝访问"页面
+}
+
+// RedirectConfig 跳转响应配置
+type RedirectConfig struct {
+	PermanentMaxAge int `mapstructure:"permanent_max_age"` // 301/308 跳转允许浏览器和 CDN 缓存的时长（秒），0表示不缓存
+}
+
+// UnfurlConfig 分享卡片元数据抓取配置
+type UnfurlConfig struct {
+	Timeout     time.Duration `mapstructure:"timeout"`       // 抓取目标页面的超时时间
+	MaxBodySize int64         `mapstructure:"max_body_size"` // 最多读取的页面字节数
+	CacheTTL    int           `mapstructure:"cache_ttl"`     // 抓取结果的缓存时长（秒）
+	UserAgent   string        `mapstructure:"user_agent"`
+}
+
+// SigningConfig 签名短链的校验密钥，与 generate-service 的密钥环保持一致
+type SigningConfig struct {
+	Keys []signedlink.Key `mapstructure:"keys"`
+}
+
 type Config struct {
 	Server          ServerConfig                             `mapstructure:"server"`
+	ClientIP        ClientIPConfig                           `mapstructure:"client_ip"`
 	Redis           RedisConfig                              `mapstructure:"redis"`
 	Kafka           KafkaConfig                              `mapstructure:"kafka"`
 	Cache           CacheConfig                              `mapstructure:"cache"`
@@ -81,4 +129,10 @@ type Config struct {
 	Generator       idgen.GeneratorConfig                    `mapstructure:"id_generator"`
 	Etcd            etcdresolver.EtcdConfig                  `mapstructure:"etcd"`
 	Breaker         circuitbreaker.RedisCircuitBreakerConfig `mapstructure:"breaker"`
+	Password        PasswordConfig                           `mapstructure:"password"`
+	Schedule        ScheduleConfig                           `mapstructure:"schedule"`
+	Access          AccessConfig                             `mapstructure:"access"`
+	Redirect        RedirectConfig                           `mapstructure:"redirect"`
+	Unfurl          UnfurlConfig                             `mapstructure:"unfurl"`
+	Signing         SigningConfig                            `mapstructure:"signing"`
 }
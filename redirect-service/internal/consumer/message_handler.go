@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"redirect-service/internal/model"
 	"redirect-service/internal/service/cache"
 	"shared/constants"
 	"shared/message"
@@ -49,7 +50,29 @@ func (c *CacheWarmupHandler) Handle(topic string, value []byte) bool {
 	if err := json.Unmarshal(value, &msg); err != nil {
 		return false
 	}
-	err := c.cacheService.SetShortUrl(context.Background(), msg.ShortCode, msg.OriginalURL, msg.ExpiredAt)
+	link := &model.Link{
+		WorkspaceID:   msg.WorkspaceID,
+		URL:           msg.OriginalURL,
+		ActivateAt:    msg.ActivateAt,
+		ExpiresAt:     msg.ExpiredAt,
+		PasswordHash:  msg.PasswordHash,
+		MaxClicks:     msg.MaxClicks,
+		Rules:         msg.Rules,
+		Variants:      msg.Variants,
+		DeepLink:      msg.DeepLink,
+		OpenGraph:     msg.OpenGraph,
+		ForwardQuery:  msg.ForwardQuery,
+		QueryConflict: msg.QueryConflict,
+		ForwardPath:   msg.ForwardPath,
+		Template:      msg.Template,
+		RedirectType:  msg.RedirectType,
+		Pixels:        msg.Pixels,
+		SignedOnly:    msg.SignedOnly,
+		IPAccess:      msg.IPAccess,
+
+		WorkspaceIPAccess: msg.WorkspaceIPAccess,
+	}
+	err := c.cacheService.SetLink(context.Background(), msg.Domain, msg.ShortCode, link)
 	if err != nil {
 		return false
 	}
@@ -69,10 +92,39 @@ func (c *CacheUpdateHandler) Handle(topic string, value []byte) bool {
 	if err := json.Unmarshal(value, &msg); err != nil {
 		return false
 	}
-	err := c.cacheService.SetShortUrl(context.Background(), msg.ShortCode, msg.OriginalURL, msg.ExpiredAt)
+	link := &model.Link{
+		WorkspaceID:   msg.WorkspaceID,
+		URL:           msg.OriginalURL,
+		ActivateAt:    msg.ActivateAt,
+		ExpiresAt:     msg.ExpiredAt,
+		PasswordHash:  msg.PasswordHash,
+		MaxClicks:     msg.MaxClicks,
+		Rules:         msg.Rules,
+		Variants:      msg.Variants,
+		DeepLink:      msg.DeepLink,
+		OpenGraph:     msg.OpenGraph,
+		ForwardQuery:  msg.ForwardQuery,
+		QueryConflict: msg.QueryConflict,
+		ForwardPath:   msg.ForwardPath,
+		Template:      msg.Template,
+		RedirectType:  msg.RedirectType,
+		Pixels:        msg.Pixels,
+		SignedOnly:    msg.SignedOnly,
+		Flagged:       msg.Status == message.LinkStatusFlagged,
+		IPAccess:      msg.IPAccess,
+
+		WorkspaceIPAccess: msg.WorkspaceIPAccess,
+	}
+	err := c.cacheService.SetLink(context.Background(), msg.Domain, msg.ShortCode, link)
 	if err != nil {
 		return false
 	}
+	// 链接重新启用或访问次数上限变化后按新的上限重新计数
+	if msg.ResetClicks {
+		if err := c.cacheService.ResetClicks(context.Background(), msg.WorkspaceID, msg.Domain, msg.ShortCode); err != nil {
+			return false
+		}
+	}
 	return true
 }
 
@@ -89,10 +141,16 @@ func (c *CacheDeleteHandler) Handle(topic string, value []byte) bool {
 	if err := json.Unmarshal(value, &msg); err != nil {
 		return false
 	}
-	err := c.cacheService.DelShortUrl(context.Background(), msg.ShortCode)
+	err := c.cacheService.DelShortUrl(context.Background(), msg.WorkspaceID, msg.Domain, msg.ShortCode)
 	if err != nil {
 		return false
 	}
+	// 链接删除后短码可被重新使用，访问计数需一并清理
+	if msg.Reason == message.DeleteReasonDeleted || msg.ResetClicks {
+		if err := c.cacheService.ResetClicks(context.Background(), msg.WorkspaceID, msg.Domain, msg.ShortCode); err != nil {
+			return false
+		}
+	}
 	return true
 }
 
@@ -0,0 +1,397 @@
+package handler
+
+import (
+	"html/template"
+	"redirect-service/internal/model"
+	"shared/message"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 密码输入页，提交到当前地址
+var passwordPage = template.Must(template.New("password").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>Password required</title>
+<style>
+body{font-family:-apple-system,BlinkMacSystemFont,"Segoe UI",sans-serif;background:#f5f5f5;display:flex;align-items:center;justify-content:center;min-height:100vh;margin:0}
+form{background:#fff;padding:32px;border-radius:8px;box-shadow:0 2px 8px rgba(0,0,0,.1);width:320px}
+h1{font-size:20px;margin:0 0 16px}
+input{width:100%;box-sizing:border-box;padding:10px;margin-bottom:12px;border:1px solid #ccc;border-radius:4px}
+button{width:100%;padding:10px;border:0;border-radius:4px;background:#1a73e8;color:#fff;font-size:15px;cursor:pointer}
+.error{color:#d93025;margin:0 0 12px}
+</style>
+</head>
+<body>
+<form method="post" action="">
+<h1>This link is password protected</h1>
+{{if .Error}}<p class="error">{{.Error}}</p>{{end}}
+<input type="password" name="password" placeholder="Password" autocomplete="current-password" required autofocus>
+<button type="submit">Continue</button>
+</form>
+</body>
+</html>
+`))
+
+type passwordPageData struct {
+	Error string
+}
+
+// 签名地址绑定邮箱时的邮箱确认页，提交到当前地址
+var emailPage = template.Must(template.New("email").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>Confirm your email</title>
+<style>
+body{font-family:-apple-system,BlinkMacSystemFont,"Segoe UI",sans-serif;background:#f5f5f5;display:flex;align-items:center;justify-content:center;min-height:100vh;margin:0}
+form{background:#fff;padding:32px;border-radius:8px;box-shadow:0 2px 8px rgba(0,0,0,.1);width:320px}
+h1{font-size:20px;margin:0 0 8px}
+p{color:#555;margin:0 0 16px}
+input{width:100%;box-sizing:border-box;padding:10px;margin-bottom:12px;border:1px solid #ccc;border-radius:4px}
+button{width:100%;padding:10px;border:0;border-radius:4px;background:#1a73e8;color:#fff;font-size:15px;cursor:pointer}
+.error{color:#d93025}
+</style>
+</head>
+<body>
+<form method="post" action="">
+<h1>Confirm your email</h1>
+<p>This link was shared with a specific recipient. Enter the email address it was sent to.</p>
+{{if .Error}}<p class="error">{{.Error}}</p>{{end}}
+<input type="hidden" name="action" value="verify_email">
+<input type="email" name="email" placeholder="you@example.com" autocomplete="email" required autofocus>
+<button type="submit">Continue</button>
+</form>
+</body>
+</html>
+`))
+
+type emailPageData struct {
+	Error string
+}
+
+// 链接尚未生效页
+var pendingPage = template.Must(template.New("pending").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>Not yet available</title>
+<style>
+body{font-family:-apple-system,BlinkMacSystemFont,"Segoe UI",sans-serif;background:#f5f5f5;display:flex;align-items:center;justify-content:center;min-height:100vh;margin:0}
+main{background:#fff;padding:32px;border-radius:8px;box-shadow:0 2px 8px rgba(0,0,0,.1);width:320px;text-align:center}
+h1{font-size:20px;margin:0 0 12px}
+p{color:#555;margin:0}
+</style>
+</head>
+<body>
+<main>
+<h1>This link is not available yet</h1>
+{{if .ActivateAt}}<p>It will be available from {{.ActivateAt}}.</p>{{else}}<p>Please check back later.</p>{{end}}
+</main>
+</body>
+</html>
+`))
+
+type pendingPageData struct {
+	ActivateAt string
+}
+
+// 访问被IP访问控制拒绝的页面，不透露命中的规则
+var deniedPage = template.Must(template.New("denied").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>Access denied</title>
+<style>
+body{font-family:-apple-system,BlinkMacSystemFont,"Segoe UI",sans-serif;background:#f5f5f5;display:flex;align-items:center;justify-content:center;min-height:100vh;margin:0}
+main{background:#fff;padding:32px;border-radius:8px;box-shadow:0 2px 8px rgba(0,0,0,.1);width:320px;text-align:center}
+h1{font-size:20px;margin:0 0 12px}
+p{color:#555;margin:0}
+</style>
+</head>
+<body>
+<main>
+<h1>Access denied</h1>
+<p>This link is only available from approved networks.</p>
+</main>
+</body>
+</html>
+`))
+
+// 打开 App 中间页：先尝试打开 App，页面未被切到后台时在超时后跳转到备用地址。
+// App 地址已在创建链接时校验 scheme，以 template.URL 输出以保留自定义 scheme
+var appPage = template.Must(template.New("app").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>Opening app</title>
+<style>
+body{font-family:-apple-system,BlinkMacSystemFont,"Segoe UI",sans-serif;background:#f5f5f5;display:flex;align-items:center;justify-content:center;min-height:100vh;margin:0}
+main{background:#fff;padding:32px;border-radius:8px;box-shadow:0 2px 8px rgba(0,0,0,.1);width:320px;text-align:center}
+h1{font-size:20px;margin:0 0 16px}
+a.button{display:block;padding:10px;border-radius:4px;background:#1a73e8;color:#fff;text-decoration:none;margin-bottom:12px}
+a{color:#1a73e8}
+</style>
+</head>
+<body>
+<main>
+<h1>Opening the app…</h1>
+<a class="button" href="{{.AppURL}}">Open in app</a>
+<a href="{{.FallbackURL}}">Continue without the app</a>
+</main>
+<script>
+(function(){
+var fallback={{.FallbackURL}};
+var timer=setTimeout(function(){window.location.replace(fallback)},1500);
+document.addEventListener("visibilitychange",function(){if(document.hidden){clearTimeout(timer)}});
+window.location.href={{.AppURL}};
+})();
+</script>
+</body>
+</html>
+`))
+
+type appPageData struct {
+	AppURL      template.URL
+	FallbackURL template.URL
+}
+
+// 跳转页，meta_refresh 和 javascript 跳转方式使用，页面加载后再跳转到目标地址
+var redirectPage = template.Must(template.New("redirect").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<meta name="referrer" content="no-referrer-when-downgrade">
+{{if .JavaScript}}<noscript><meta http-equiv="refresh" content="0;url={{.URL}}"></noscript>{{else}}<meta http-equiv="refresh" content="0;url={{.URL}}">{{end}}
+<title>Redirecting</title>
+</head>
+<body>
+<p>Redirecting to <a href="{{.URL}}">{{.URL}}</a>…</p>
+{{if .JavaScript}}<script>window.location.replace({{.URL}});</script>{{end}}
+</body>
+</html>
+`))
+
+type redirectPageData struct {
+	URL        string
+	JavaScript bool
+}
+
+// 风险提示页：展示目标域名，继续访问提交到当前地址，返回上一页时无历史记录则关闭页面
+var warningPage = template.Must(template.New("warning").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>Suspicious link</title>
+<style>
+body{font-family:-apple-system,BlinkMacSystemFont,"Segoe UI",sans-serif;background:#f5f5f5;display:flex;align-items:center;justify-content:center;min-height:100vh;margin:0}
+form{background:#fff;padding:32px;border-radius:8px;box-shadow:0 2px 8px rgba(0,0,0,.1);width:360px}
+h1{font-size:20px;margin:0 0 12px;color:#d93025}
+p{color:#555;margin:0 0 16px}
+.host{font-weight:600;color:#202124;word-break:break-all}
+button{width:100%;padding:10px;border-radius:4px;font-size:15px;cursor:pointer;margin-bottom:12px}
+.back{border:0;background:#1a73e8;color:#fff}
+.continue{border:1px solid #ccc;background:#fff;color:#d93025}
+</style>
+</head>
+<body>
+<form method="post" action="">
+<h1>This link may be unsafe</h1>
+<p>It has been flagged as suspicious and leads to:</p>
+<p class="host">{{.Host}}</p>
+<p>Only continue if you trust this site.</p>
+<input type="hidden" name="action" value="continue">
+<button type="button" class="back" onclick="if(history.length>1){history.back()}else{window.close()}">Go back</button>
+<button type="submit" class="continue">Continue to {{.Host}}</button>
+</form>
+</body>
+</html>
+`))
+
+type warningPageData struct {
+	Host string
+}
+
+// 重定向像素跳转页：按平台输出固定的官方代码片段，不支持自定义 HTML。
+// 像素脚本异步加载，页面 load 事件后稍作等待再跳转，给像素请求留出发送时间，最迟 1.5 秒后跳转
+var pixelPage = template.Must(template.New("pixel").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<meta name="referrer" content="no-referrer-when-downgrade">
+<noscript><meta http-equiv="refresh" content="0;url={{.URL}}"></noscript>
+<title>Redirecting</title>
+{{if .Facebook}}<script>
+!function(f,b,e,v,n,t,s){if(f.fbq)return;n=f.fbq=function(){n.callMethod?n.callMethod.apply(n,arguments):n.queue.push(arguments)};if(!f._fbq)f._fbq=n;n.push=n;n.loaded=!0;n.version='2.0';n.queue=[];t=b.createElement(e);t.async=!0;t.src=v;s=b.getElementsByTagName(e)[0];s.parentNode.insertBefore(t,s)}(window,document,'script','https://connect.facebook.net/en_US/fbevents.js');
+{{range .Facebook}}fbq('init',{{.}});
+{{end}}fbq('track','PageView');
+</script>{{end}}
+{{if .Google}}<script async src="https://www.googletagmanager.com/gtag/js?id={{index .Google 0}}"></script>
+<script>
+window.dataLayer=window.dataLayer||[];function gtag(){dataLayer.push(arguments)}gtag('js',new Date());
+{{range .Google}}gtag('config',{{.}});
+{{end}}</script>{{end}}
+{{if .LinkedIn}}<script>
+window._linkedin_partner_id={{index .LinkedIn 0}};window._linkedin_data_partner_ids=window._linkedin_data_partner_ids||[];
+{{range .LinkedIn}}window._linkedin_data_partner_ids.push({{.}});
+{{end}}(function(l){if(!l){window.lintrk=function(a,b){window.lintrk.q.push([a,b])};window.lintrk.q=[]}var s=document.getElementsByTagName("script")[0];var b=document.createElement("script");b.type="text/javascript";b.async=true;b.src="https://snap.licdn.com/li.lms-analytics/insight.min.js";s.parentNode.insertBefore(b,s)})(window.lintrk);
+</script>{{end}}
+</head>
+<body>
+<p>Redirecting to <a href="{{.URL}}">{{.URL}}</a>…</p>
+<script>
+(function(){
+var url={{.URL}},done=false;
+function go(){if(!done){done=true;window.location.replace(url)}}
+window.addEventListener("load",function(){setTimeout(go,300)});
+setTimeout(go,1500);
+})();
+</script>
+</body>
+</html>
+`))
+
+// 跟踪ID按平台分组，同一平台的多个像素共用一份加载脚本
+type pixelPageData struct {
+	URL      string
+	Facebook []string
+	Google   []string
+	LinkedIn []string
+}
+
+func newPixelPageData(pixels []message.RetargetingPixel, destination string) pixelPageData {
+	data := pixelPageData{URL: destination}
+	for _, p := range pixels {
+		switch p.Provider {
+		case model.PixelProviderFacebook:
+			data.Facebook = append(data.Facebook, p.TrackingID)
+		case model.PixelProviderGoogle:
+			data.Google = append(data.Google, p.TrackingID)
+		case model.PixelProviderLinkedIn:
+			data.LinkedIn = append(data.LinkedIn, p.TrackingID)
+		}
+	}
+	return data
+}
+
+// 短链预览页，展示目标地址但不跳转
+var previewPage = template.Must(template.New("preview").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>Link preview</title>
+<style>
+body{font-family:-apple-system,BlinkMacSystemFont,"Segoe UI",sans-serif;background:#f5f5f5;display:flex;align-items:center;justify-content:center;min-height:100vh;margin:0}
+main{background:#fff;padding:32px;border-radius:8px;box-shadow:0 2px 8px rgba(0,0,0,.1);width:420px}
+h1{font-size:20px;margin:0 0 4px}
+.short{color:#555;margin:0 0 16px;word-break:break-all}
+dl{margin:0 0 16px}
+dt{font-size:13px;color:#777;margin-top:12px}
+dd{margin:2px 0 0;word-break:break-all}
+.warning{color:#d93025}
+a{color:#1a73e8}
+a.button{display:block;padding:10px;border-radius:4px;background:#1a73e8;color:#fff;text-decoration:none;text-align:center}
+</style>
+</head>
+<body>
+<main>
+<h1>{{if .Preview.Title}}{{.Preview.Title}}{{else}}Link preview{{end}}</h1>
+<p class="short">{{.Preview.ShortURL}}</p>
+<dl>
+<dt>Destination</dt>
+<dd>{{if .Preview.Destination}}<a href="{{.Preview.Destination}}" rel="nofollow noopener">{{.Preview.Destination}}</a>{{else if .Preview.Protected}}Hidden, this link is password protected{{else if .Preview.SignedOnly}}Hidden, this link can only be opened from a signed URL{{else}}Unavailable{{end}}</dd>
+<dt>Status</dt>
+<dd{{if eq .Preview.Status "flagged"}} class="warning"{{end}}>{{.StatusText}}</dd>
+<dt>Created</dt>
+<dd>{{.CreatedAt}}</dd>
+{{if .ExpiresAt}}<dt>Expires</dt>
+<dd>{{.ExpiresAt}}</dd>
+{{end}}</dl>
+{{if and (or .Preview.Destination .Preview.Protected) (not .Preview.SignedOnly)}}<a class="button" href="{{.Preview.ShortURL}}">Open link</a>{{end}}
+</main>
+</body>
+</html>
+`))
+
+type previewPageData struct {
+	Preview    *model.LinkPreview
+	StatusText string
+	CreatedAt  string // UTC 日期
+	ExpiresAt  string
+}
+
+// 分享卡片页，仅返回给社交平台爬虫，不自动跳转
+var unfurlPage = template.Must(template.New("unfurl").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="robots" content="noindex">
+<title>{{.Title}}</title>
+<meta property="og:type" content="website">
+<meta property="og:url" content="{{.URL}}">
+<meta property="og:title" content="{{.Title}}">
+{{if .Description}}<meta property="og:description" content="{{.Description}}">
+<meta name="description" content="{{.Description}}">
+{{end}}{{if .Image}}<meta property="og:image" content="{{.Image}}">
+<meta name="twitter:card" content="summary_large_image">
+<meta name="twitter:image" content="{{.Image}}">
+{{else}}<meta name="twitter:card" content="summary">
+{{end}}<meta name="twitter:title" content="{{.Title}}">
+{{if .Description}}<meta name="twitter:description" content="{{.Description}}">
+{{end}}</head>
+<body>
+<a href="{{.Destination}}">{{.Title}}</a>
+</body>
+</html>
+`))
+
+type unfurlPageData struct {
+	URL         string // 短链地址，作为卡片的规范地址
+	Destination string
+	Title       string // 覆盖值和目标页面均未提供标题时为目标域名
+	Description string
+	Image       string
+}
+
+func newUnfurlPageData(og *message.OpenGraph, shortURL, destination string) unfurlPageData {
+	data := unfurlPageData{
+		URL:         shortURL,
+		Destination: destination,
+		Title:       og.Title,
+		Description: og.Description,
+		Image:       og.Image,
+	}
+	if data.Title == "" {
+		data.Title = destinationHost(destination)
+	}
+	return data
+}
+
+// 渲染HTML页面，页面内容与访问者相关，禁止缓存
+func renderPage(c *gin.Context, status int, tmpl *template.Template, data any) {
+	c.Header("Cache-Control", "no-store")
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(status)
+	if err := tmpl.Execute(c.Writer, data); err != nil {
+		c.Error(err)
+	}
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"net/http"
+	"redirect-service/internal/middleware"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 预览请求的短码，支持 /<code>+ 和 /<code>/preview 两种形式。
+// 短码不含 +，/<code>/preview 优先于路径转发
+func previewCode(c *gin.Context) (string, bool) {
+	code := c.Param("code")
+	switch path := c.Param("path"); {
+	case path == "/preview":
+		return code, true
+	case path == "":
+		code, ok := strings.CutSuffix(code, "+")
+		return code, ok && code != ""
+	}
+	return "", false
+}
+
+var previewStatusText = map[string]string{
+	"active":   "Active",
+	"flagged":  "Flagged as suspicious",
+	"pending":  "Not active yet",
+	"disabled": "Disabled",
+	"expired":  "Expired",
+}
+
+// 按 Accept 请求头返回预览页或 JSON
+func (h *RedirectHandler) preview(c *gin.Context, shortCode string) {
+	domain := h.resolveDomain(c)
+	preview, err := h.redirectService.GetPreview(c.Request.Context(), domain, shortCode)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	preview.ShortURL = requestScheme(c) + "://" + c.Request.Host + "/" + shortCode
+
+	c.Header("Vary", "Accept")
+	if c.NegotiateFormat(gin.MIMEHTML, gin.MIMEJSON) == gin.MIMEJSON {
+		c.Header("Cache-Control", "no-store")
+		c.JSON(http.StatusOK, preview)
+		return
+	}
+	data := previewPageData{
+		Preview:    preview,
+		StatusText: previewStatusText[preview.Status],
+		CreatedAt:  preview.CreatedAt.UTC().Format("2006-01-02"),
+	}
+	if data.StatusText == "" {
+		data.StatusText = preview.Status
+	}
+	if preview.ExpiresAt != nil {
+		data.ExpiresAt = preview.ExpiresAt.UTC().Format("2006-01-02")
+	}
+	renderPage(c, http.StatusOK, previewPage, data)
+}
+
+// 请求协议，X-Forwarded-Proto 只在直连地址为可信代理时采信，避免客户端伪造
+func requestScheme(c *gin.Context) string {
+	if c.Request.TLS != nil {
+		return "https"
+	}
+	if middleware.IsTrustedPeer(c) && c.GetHeader("X-Forwarded-Proto") == "https" {
+		return "https"
+	}
+	return "http"
+}
@@ -1,23 +1,79 @@
 package handler
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"html/template"
 	"log"
 	"net/http"
+	"net/url"
+	"redirect-service/internal/config"
 	"redirect-service/internal/middleware"
+	"redirect-service/internal/model"
+	"redirect-service/internal/service/deeplink"
+	"redirect-service/internal/service/passthrough"
+	"redirect-service/internal/service/password"
 	"redirect-service/internal/service/redirect"
-	"shared/model"
+	"redirect-service/internal/service/unfurl"
+	"redirect-service/internal/service/urltemplate"
+	shrErrors "shared/errors"
+	"shared/hostname"
+	"shared/ipaccess"
+	shrModel "shared/model"
+	"shared/signedlink"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	visitorCookieName   = "link_vid"
+	visitorCookieMaxAge = 365 * 24 * 3600
+	warningCookieName   = "link_warned" // 已确认风险提示
+	emailCookieName     = "link_email"  // 签名地址绑定邮箱时访问者填写的邮箱
+)
+
+// 签名地址绑定了邮箱，访问者尚未填写
+var errEmailRequired = errors.New("signed link requires email confirmation")
+
 type RedirectHandler struct {
 	redirectService redirect.Service
+	passwordService *password.Service
+	unfurlService   *unfurl.Service
+	signer          *signedlink.KeyRing
+	schedule        *config.ScheduleConfig
+	access          *config.AccessConfig
+	redirect        *config.RedirectConfig
+	defaultHosts    map[string]struct{}
 }
 
-func NewRedirectHandler(redirectService redirect.Service) *RedirectHandler {
+func NewRedirectHandler(
+	redirectService redirect.Service,
+	passwordService *password.Service,
+	unfurlService *unfurl.Service,
+	signer *signedlink.KeyRing,
+	schedule *config.ScheduleConfig,
+	access *config.AccessConfig,
+	redirect *config.RedirectConfig,
+	defaultHosts []string,
+) *RedirectHandler {
+	hosts := make(map[string]struct{}, len(defaultHosts))
+	for _, h := range defaultHosts {
+		hosts[hostname.Normalize(h)] = struct{}{}
+	}
 	return &RedirectHandler{
 		redirectService: redirectService,
+		passwordService: passwordService,
+		unfurlService:   unfurlService,
+		signer:          signer,
+		schedule:        schedule,
+		access:          access,
+		redirect:        redirect,
+		defaultHosts:    hosts,
 	}
 }
 
@@ -26,56 +82,440 @@ func NewRedirectHandler(redirectService redirect.Service) *RedirectHandler {
 func (h *RedirectHandler) Redirect(c *gin.Context) {
 	shortCode := c.Param("code")
 	if shortCode == "" {
-		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+		c.JSON(http.StatusBadRequest, shrModel.ErrorResponse{
 			Error:   "invalid short code",
 			Message: "Short code is required",
 		})
 		return
 	}
-	// 获取原始URL
-	originalUrl, err := h.redirectService.GetOriginalUrl(c, shortCode)
+	// 预览目标地址，不计入点击
+	if code, ok := previewCode(c); ok {
+		h.preview(c, code)
+		return
+	}
+	domain := h.resolveDomain(c)
+	// 签名地址在本地校验，篡改或过期的地址不查询链接直接拒绝
+	signed, err := h.verifySignature(c, domain, shortCode)
+	if err == errEmailRequired {
+		renderPage(c, http.StatusOK, emailPage, emailPageData{})
+		return
+	}
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	// 按 (域名, 短码) 获取短链数据
+	link, err := h.redirectService.GetLink(c, domain, shortCode)
 	if err != nil {
 		c.Error(err)
 		return
 	}
+	if !h.checkIPAccess(c, domain, shortCode, link) {
+		return
+	}
+	if link.SignedOnly && !signed {
+		c.Error(signedlink.ErrSignatureRequired)
+		return
+	}
+	// 签名参数不转发到目标地址
+	query, rawQuery := c.Request.URL.Query(), c.Request.URL.RawQuery
+	if signed {
+		query = signedlink.Strip(query)
+		rawQuery = query.Encode()
+	}
+	// 带路径后缀的访问仅对开启路径转发或使用目标地址模板的链接有效
+	suffix := pathSuffix(c)
+	if suffix != "" && !link.ForwardPath && !link.Template {
+		c.Error(shrErrors.ErrLinkNotFound)
+		return
+	}
+	if link.Pending() {
+		h.renderPending(c, link)
+		return
+	}
+	// 密码保护的链接需持有有效的访问凭证，否则展示密码输入页
+	if link.Protected() && !h.hasAccess(c, domain, shortCode, link) {
+		renderPage(c, http.StatusOK, passwordPage, passwordPageData{})
+		return
+	}
+	_, username := middleware.GetUserFromContext(c)
+	req := &redirect.RedirectRequest{
+		WorkspaceID:    link.WorkspaceID,
+		Domain:         domain,
+		IPAddress:      getClientIP(c),
+		UserAgent:      c.Request.UserAgent(),
+		Referer:        c.Request.Referer(),
+		Username:       username,
+		AcceptLanguage: c.GetHeader("Accept-Language"),
+	}
+	if len(link.Variants) > 0 {
+		req.VisitorID = visitorID(c, req.IPAddress)
+	}
+	// 按条件跳转规则和 A/B 测试分组选择目标地址
+	originalUrl := h.redirectService.ResolveURL(link, shortCode, req)
+	// 目标地址模板用路径段和查询参数展开，路径段已被占位符使用，不再追加
+	forwardPath := link.ForwardPath
+	if link.Template && originalUrl == link.URL {
+		originalUrl, err = urltemplate.Expand(link.URL, urltemplate.Segments(suffix), query)
+		if err != nil {
+			c.Error(shrErrors.ErrInvalidURL)
+			return
+		}
+		forwardPath = false
+	}
+	originalUrl = passthrough.Apply(originalUrl, passthrough.Options{
+		ForwardQuery:  link.ForwardQuery,
+		QueryConflict: link.QueryConflict,
+		ForwardPath:   forwardPath,
+	}, suffix, rawQuery)
+	req.OriginalURL = originalUrl
+
+	// 被标记为可疑的链接先展示风险提示页，访问者选择继续后才计数和跳转
+	if link.Flagged && !hasCookie(c, warningCookieName) {
+		renderPage(c, http.StatusOK, warningPage, warningPageData{Host: destinationHost(originalUrl)})
+		return
+	}
+	// 社交平台爬虫返回分享卡片，不消耗访问次数
+	if h.redirectService.IsLinkPreview(req) {
+		h.renderUnfurl(c, shortCode, link, req)
+		return
+	}
+	// 限次链接在跳转前原子计数，用尽后按过期处理；爬虫访问不计数
+	if link.Limited() && !h.redirectService.IsBot(req) {
+		if err := h.redirectService.ConsumeClick(c.Request.Context(), domain, shortCode, link); err != nil {
+			c.Error(err)
+			return
+		}
+	}
 
 	// 异步记录点击事件
-	ip := getClientIP(c)
+	go func() {
+		err = h.redirectService.RecordClick(c.Request.Context(), shortCode, req)
+		if err != nil {
+			log.Printf("failed to record click on short code: %v", err)
+		}
+	}()
+
+	// 移动端深度链接：Android 跳转 intent:// 地址，iOS 展示尝试打开 App 的中间页
+	if target := h.redirectService.ResolveDeepLink(link, originalUrl, req); target != nil {
+		h.openApp(c, target)
+		return
+	}
+	// 重定向像素：展示触发像素的跳转页，像素发出后再跳转到目标地址
+	if pixels := h.redirectService.ResolvePixels(link, req); len(pixels) > 0 {
+		renderPage(c, http.StatusOK, pixelPage, newPixelPageData(pixels, originalUrl))
+		return
+	}
+
+	h.sendRedirect(c, link, originalUrl)
+}
+
+// 按链接的跳转方式响应，默认 302
+func (h *RedirectHandler) sendRedirect(c *gin.Context, link *model.Link, destination string) {
+	switch link.RedirectType {
+	case model.RedirectTypeMovedPermanently, model.RedirectTypePermanentRedirect:
+		status := http.StatusMovedPermanently
+		if link.RedirectType == model.RedirectTypePermanentRedirect {
+			status = http.StatusPermanentRedirect
+		}
+		c.Header("Cache-Control", h.permanentCacheControl(link))
+		c.Redirect(status, destination)
+	case model.RedirectTypeTemporaryRedirect:
+		c.Header("Cache-Control", "private, no-cache")
+		c.Redirect(http.StatusTemporaryRedirect, destination)
+	case model.RedirectTypeMetaRefresh, model.RedirectTypeJavaScript:
+		renderPage(c, http.StatusOK, redirectPage, redirectPageData{
+			URL:        destination,
+			JavaScript: link.RedirectType == model.RedirectTypeJavaScript,
+		})
+	default:
+		c.Header("Cache-Control", "private, no-cache")
+		c.Redirect(http.StatusFound, destination)
+	}
+}
+
+// 永久跳转允许缓存，缓存期不超过链接剩余有效期；跳转结果因访问者而异时只允许验证后使用
+func (h *RedirectHandler) permanentCacheControl(link *model.Link) string {
+	maxAge := h.redirect.PermanentMaxAge
+	if link.ExpiresAt != nil {
+		maxAge = min(maxAge, int(time.Until(*link.ExpiresAt).Seconds()))
+	}
+	if link.PerVisitor() || maxAge <= 0 {
+		return "private, no-cache"
+	}
+	return "public, max-age=" + strconv.Itoa(maxAge)
+}
+
+// 打开 App，未配置 App 地址时直接跳转到应用商店或网页
+func (h *RedirectHandler) openApp(c *gin.Context, target *deeplink.Target) {
+	switch {
+	case target.AppURL == "":
+		c.Redirect(http.StatusFound, target.FallbackURL)
+	case target.Platform == deeplink.PlatformAndroid:
+		// Chrome 在未安装 App 时自动跳转 intent 中的 browser_fallback_url
+		c.Header("Cache-Control", "no-store")
+		c.Redirect(http.StatusFound, target.AppURL)
+	default:
+		renderPage(c, http.StatusOK, appPage, appPageData{
+			AppURL:      template.URL(target.AppURL),
+			FallbackURL: template.URL(target.FallbackURL),
+		})
+	}
+}
+
+// SubmitForm 处理中间页的表单提交：签名地址的邮箱确认、风险提示页的继续访问和密码输入页的密码校验，
+// 完成后重新访问短链
+// @Router /{code} [post]
+func (h *RedirectHandler) SubmitForm(c *gin.Context) {
+	shortCode := c.Param("code")
+	domain := h.resolveDomain(c)
+	if c.PostForm("action") == "verify_email" {
+		h.confirmEmail(c, domain, shortCode)
+		return
+	}
+	link, err := h.redirectService.GetLink(c, domain, shortCode)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if !h.checkIPAccess(c, domain, shortCode, link) {
+		return
+	}
+	if link.Pending() {
+		h.renderPending(c, link)
+		return
+	}
+	if c.PostForm("action") == "continue" {
+		if link.Flagged {
+			h.acknowledgeWarning(c, domain, shortCode, link)
+		}
+	} else if link.Protected() {
+		err = h.passwordService.Verify(c.Request.Context(), getClientIP(c), link, c.PostForm("password"))
+		switch err {
+		case nil:
+		case password.ErrInvalidPassword:
+			renderPage(c, http.StatusUnauthorized, passwordPage, passwordPageData{Error: "Incorrect password, please try again."})
+			return
+		case password.ErrTooManyAttempts:
+			renderPage(c, http.StatusTooManyRequests, passwordPage, passwordPageData{Error: "Too many attempts, please try again later."})
+			return
+		default:
+			c.Error(err)
+			return
+		}
+		// 凭证 Cookie 仅对当前主机和短码路径有效
+		c.SetSameSite(http.SameSiteLaxMode)
+		c.SetCookie(password.CookieName, h.passwordService.IssueToken(domain, shortCode, link),
+			int(h.passwordService.CookieTTL().Seconds()), "/"+shortCode, "", c.Request.TLS != nil, true)
+	}
+	// 303 使浏览器以 GET 重新访问，由 Redirect 记录点击并跳转
+	c.Redirect(http.StatusSeeOther, c.Request.URL.RequestURI())
+}
+
+// 记录继续访问事件，并写入仅对当前短码路径有效的会话 Cookie，本次会话内不再提示
+func (h *RedirectHandler) acknowledgeWarning(c *gin.Context, domain, shortCode string, link *model.Link) {
 	_, username := middleware.GetUserFromContext(c)
 	req := &redirect.RedirectRequest{
-		OriginalURL: originalUrl,
-		IPAddress:   ip,
+		WorkspaceID: link.WorkspaceID,
+		Domain:      domain,
+		OriginalURL: link.URL,
+		IPAddress:   getClientIP(c),
 		UserAgent:   c.Request.UserAgent(),
 		Referer:     c.Request.Referer(),
 		Username:    username,
 	}
 	go func() {
-		err = h.redirectService.RecordClick(c.Request.Context(), shortCode, req)
-		if err != nil {
-			log.Printf("failed to record click on short code: %v", err)
+		if err := h.redirectService.RecordWarningContinue(context.Background(), shortCode, req); err != nil {
+			log.Printf("failed to record warning continue on short code: %v", err)
 		}
 	}()
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(warningCookieName, "1", 0, "/"+shortCode, "", c.Request.TLS != nil, true)
+}
 
-	// 302 重定向
-	c.Redirect(http.StatusFound, originalUrl)
+// 返回带 Open Graph 标签的分享卡片页，抓取记录为爬虫流量
+func (h *RedirectHandler) renderUnfurl(c *gin.Context, shortCode string, link *model.Link, req *redirect.RedirectRequest) {
+	go func() {
+		if err := h.redirectService.RecordUnfurl(context.Background(), shortCode, req); err != nil {
+			log.Printf("failed to record unfurl on short code: %v", err)
+		}
+	}()
+	og := h.unfurlService.Resolve(c.Request.Context(), link.OpenGraph, req.OriginalURL)
+	shortURL := requestScheme(c) + "://" + c.Request.Host + c.Request.URL.RequestURI()
+	renderPage(c, http.StatusOK, unfurlPage, newUnfurlPageData(og, shortURL, req.OriginalURL))
 }
 
-// 获取客户端IP
-func getClientIP(c *gin.Context) string {
-	// 尝试从 X-Forwarded-For 获取
-	// X-Forwarded-For：包含客户端和所有代理服务器的 IP 链
-	if forwarded := c.Request.Header.Get("X-Forwarded-For"); forwarded != "" {
-		if ips := strings.Split(forwarded, ","); len(ips) > 0 {
-			return strings.TrimSpace(ips[0])
+// 校验请求中的签名，返回是否带有有效签名。绑定IP时使用客户端IP，
+// 绑定邮箱时使用访问者在确认页填写的邮箱，尚未填写时返回 errEmailRequired
+func (h *RedirectHandler) verifySignature(c *gin.Context, domain, shortCode string) (bool, error) {
+	q := c.Request.URL.Query()
+	if !signedlink.Signed(q) {
+		return false, nil
+	}
+	token, err := signedlink.Parse(q)
+	if err != nil {
+		return false, err
+	}
+	claims := signedlink.Claims{Domain: domain, ShortCode: shortCode}
+	switch token.Binding {
+	case signedlink.BindIP:
+		claims.BindValue = getClientIP(c)
+	case signedlink.BindEmail:
+		email, err := c.Cookie(emailCookieName)
+		if err != nil || email == "" {
+			// 已过期的地址不再询问邮箱
+			if !time.Now().Before(token.ExpiresAt) {
+				return false, signedlink.ErrSignatureExpired
+			}
+			return false, errEmailRequired
 		}
+		claims.BindValue = email
+	}
+	err = h.signer.Verify(token, claims, time.Now())
+	if err == signedlink.ErrInvalidSignature && token.Binding == signedlink.BindEmail {
+		// 之前填写的邮箱不匹配，重新询问
+		return false, errEmailRequired
+	}
+	return err == nil, err
+}
+
+// 校验访问者填写的邮箱与签名绑定的邮箱一致，通过后写入仅对当前短码路径有效的会话 Cookie
+func (h *RedirectHandler) confirmEmail(c *gin.Context, domain, shortCode string) {
+	token, err := signedlink.Parse(c.Request.URL.Query())
+	if err != nil || token.Binding != signedlink.BindEmail {
+		c.Error(signedlink.ErrInvalidSignature)
+		return
 	}
+	email := signedlink.NormalizeBindValue(signedlink.BindEmail, c.PostForm("email"))
+	claims := signedlink.Claims{Domain: domain, ShortCode: shortCode, BindValue: email}
+	switch err := h.signer.Verify(token, claims, time.Now()); err {
+	case nil:
+	case signedlink.ErrInvalidSignature:
+		renderPage(c, http.StatusForbidden, emailPage, emailPageData{Error: "This link was not shared with that email address."})
+		return
+	default:
+		c.Error(err)
+		return
+	}
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(emailCookieName, email, 0, "/"+shortCode, "", c.Request.TLS != nil, true)
+	c.Redirect(http.StatusSeeOther, c.Request.URL.RequestURI())
+}
 
-	// 尝试从 X-Real-IP 获取
-	// X-Real-IP：通常由第一个代理设置，直接包含客户端真实 IP
-	if realIP := c.GetHeader("X-Real-IP"); realIP != "" {
-		return realIP
+// 链接尚未生效时按配置跳转到指定地址或展示"尚未生效"页面
+func (h *RedirectHandler) renderPending(c *gin.Context, link *model.Link) {
+	c.Header("Cache-Control", "no-store")
+	if h.schedule.PendingRedirectURL != "" {
+		c.Redirect(http.StatusFound, h.schedule.PendingRedirectURL)
+		return
+	}
+	status := h.schedule.PendingStatus
+	if status == 0 {
+		status = http.StatusForbidden
 	}
+	data := pendingPageData{}
+	if h.schedule.ShowActivateTime {
+		data.ActivateAt = link.ActivateAt.UTC().Format(time.RFC1123)
+	}
+	renderPage(c, status, pendingPage, data)
+}
 
-	// 使用远程地址
-	return c.ClientIP()
+// 按工作空间和链接的IP访问控制列表检查客户端IP，拒绝时异步发出安全审计事件，
+// 并按配置跳转到指定地址或展示"拒绝访问"页面
+func (h *RedirectHandler) checkIPAccess(c *gin.Context, domain, shortCode string, link *model.Link) bool {
+	if !link.IPRestricted() {
+		return true
+	}
+	ip := getClientIP(c)
+	decision := ipaccess.Check(ip, link.WorkspaceIPAccess, link.IPAccess)
+	if decision.Allowed {
+		return true
+	}
+	req := &redirect.RedirectRequest{
+		WorkspaceID: link.WorkspaceID,
+		Domain:      domain,
+		IPAddress:   ip,
+		UserAgent:   c.Request.UserAgent(),
+		Referer:     c.Request.Referer(),
+	}
+	go func() {
+		if err := h.redirectService.RecordAccessDenied(context.Background(), shortCode, req, decision); err != nil {
+			log.Printf("failed to record access denied on short code: %v", err)
+		}
+	}()
+	c.Header("Cache-Control", "no-store")
+	if h.access.DeniedRedirectURL != "" {
+		c.Redirect(http.StatusFound, h.access.DeniedRedirectURL)
+		return false
+	}
+	renderPage(c, http.StatusForbidden, deniedPage, nil)
+	return false
+}
+
+// 检查请求是否携带该链接的有效访问凭证
+func (h *RedirectHandler) hasAccess(c *gin.Context, domain, shortCode string, link *model.Link) bool {
+	token, err := c.Cookie(password.CookieName)
+	if err != nil {
+		return false
+	}
+	return h.passwordService.ValidToken(token, domain, shortCode, link)
+}
+
+func hasCookie(c *gin.Context, name string) bool {
+	v, err := c.Cookie(name)
+	return err == nil && v != ""
+}
+
+// 风险提示页展示的目标域名
+func destinationHost(destination string) string {
+	u, err := url.Parse(destination)
+	if err != nil || u.Hostname() == "" {
+		return destination
+	}
+	return u.Hostname()
+}
+
+// 读取访问者标识 Cookie，不存在时由客户端IP哈希生成并写回，
+// 使不接受 Cookie 的访问者在同一IP下仍落在同一分组
+func visitorID(c *gin.Context, ip string) string {
+	if id, err := c.Cookie(visitorCookieName); err == nil && id != "" {
+		return id
+	}
+	sum := sha256.Sum256([]byte(ip))
+	id := hex.EncodeToString(sum[:16])
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(visitorCookieName, id, visitorCookieMaxAge, "/", "", c.Request.TLS != nil, true)
+	return id
+}
+
+// 返回访问路径中短码之后的部分（保留转义），如 /docs/api/v2 返回 /api/v2。
+// 短码不含 /，最长匹配的短码前缀即第一段路径；仅有末尾斜杠时视为无后缀
+func pathSuffix(c *gin.Context) string {
+	if c.Param("path") == "" {
+		return ""
+	}
+	escaped := c.Request.URL.EscapedPath()
+	i := strings.Index(strings.TrimPrefix(escaped, "/"), "/")
+	if i < 0 {
+		return ""
+	}
+	suffix := escaped[i+1:]
+	if suffix == "/" {
+		return ""
+	}
+	return suffix
+}
+
+// 根据 Host 解析短码命名空间，默认域名返回空字符串
+func (h *RedirectHandler) resolveDomain(c *gin.Context) string {
+	host := hostname.Normalize(c.Request.Host)
+	if _, ok := h.defaultHosts[host]; ok || host == "" {
+		return ""
+	}
+	return host
+}
+
+// 获取客户端IP，由 ClientIP 中间件按可信代理解析
+func getClientIP(c *gin.Context) string {
+	return middleware.GetClientIP(c)
 }
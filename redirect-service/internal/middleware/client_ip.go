@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"redirect-service/internal/pkg/clientip"
+	"redirect-service/internal/pkg/ipgen"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	ClientIPKey    contextKey = "client_ip"
+	TrustedPeerKey contextKey = "trusted_peer"
+)
+
+// ClientIP 按可信代理解析客户端IP并写入上下文，处理器通过 GetClientIP 读取；
+// 同时记录直连地址是否为可信代理，通过 IsTrustedPeer 读取
+func ClientIP(resolver *clientip.Resolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(ClientIPKey, resolver.Resolve(c.Request))
+		c.Set(TrustedPeerKey, resolver.TrustedPeer(c.Request))
+		c.Next()
+	}
+}
+
+// SimulateClientIP 仅用于开发环境：以随机公网IP作为客户端IP，生成地域统计等测试数据
+func SimulateClientIP() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(ClientIPKey, ipgen.IpGenerator.GeneratePublicIP())
+		c.Next()
+	}
+}
+
+// IsTrustedPeer 判断直连地址是否为可信代理，未经过 ClientIP 中间件时视为不可信
+func IsTrustedPeer(c *gin.Context) bool {
+	trusted, _ := c.Get(TrustedPeerKey)
+	ok, _ := trusted.(bool)
+	return ok
+}
+
+// GetClientIP 获取客户端IP，未经过 ClientIP 中间件时使用直连地址
+func GetClientIP(c *gin.Context) string {
+	if ip, exists := c.Get(ClientIPKey); exists {
+		return ip.(string)
+	}
+	return c.RemoteIP()
+}
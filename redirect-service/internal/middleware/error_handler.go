@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"net/http"
+	shrErrors "shared/errors"
+	"shared/model"
+	"shared/signedlink"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorHandler 全局错误处理中间件，处理器已写出响应时只记录错误
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 {
+			return
+		}
+		for _, err := range c.Errors {
+			// 记录错误日志
+			gin.DefaultErrorWriter.Write([]byte(err.Error() + "\n"))
+		}
+		if c.Writer.Written() {
+			return
+		}
+
+		var statusCode int
+		var errorResp model.ErrorResponse
+		switch err := c.Errors.Last().Err.(type) {
+		case *shrErrors.BusinessError:
+			statusCode, errorResp = businessErrorResponse(err)
+		default:
+			statusCode = http.StatusInternalServerError
+			errorResp = model.ErrorResponse{
+				Error:   "internal_error",
+				Message: "Internal server error",
+			}
+		}
+		c.JSON(statusCode, errorResp)
+		c.Abort()
+	}
+}
+
+// 已知业务错误映射为对应的HTTP状态码，其余业务错误统一返回400
+func businessErrorResponse(err *shrErrors.BusinessError) (int, model.ErrorResponse) {
+	switch err {
+	case shrErrors.ErrLinkNotFound:
+		return http.StatusNotFound, model.ErrorResponse{
+			Error:   "link_not_found",
+			Message: "Short link not found",
+		}
+	case shrErrors.ErrLinkExpired:
+		return http.StatusGone, model.ErrorResponse{
+			Error:   "link_expired",
+			Message: "Short link has expired",
+		}
+	case shrErrors.ErrLinkDisabled:
+		return http.StatusForbidden, model.ErrorResponse{
+			Error:   "link_disabled",
+			Message: "Short link is disabled",
+		}
+	case signedlink.ErrInvalidSignature, signedlink.ErrSignatureRequired:
+		return http.StatusForbidden, model.ErrorResponse{
+			Error:   "invalid_signature",
+			Message: "Short link signature is missing or invalid",
+		}
+	case signedlink.ErrSignatureExpired:
+		return http.StatusForbidden, model.ErrorResponse{
+			Error:   "signature_expired",
+			Message: "Signed link has expired",
+		}
+	case shrErrors.ErrBreakerOpen:
+		return http.StatusServiceUnavailable, model.ErrorResponse{
+			Error:   "service_unavailable",
+			Message: "Service temporarily unavailable",
+		}
+	default:
+		return http.StatusBadRequest, model.ErrorResponse{
+			Error:   "business_error",
+			Message: err.Error(),
+		}
+	}
+}
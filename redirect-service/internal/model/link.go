@@ -0,0 +1,79 @@
+package model
+
+import (
+	"shared/message"
+	"time"
+)
+
+// 跳转方式，空表示 302
+const (
+	RedirectTypeMovedPermanently  = "301"
+	RedirectTypeTemporaryRedirect = "307"
+	RedirectTypePermanentRedirect = "308"
+	RedirectTypeMetaRefresh       = "meta_refresh"
+	RedirectTypeJavaScript        = "javascript"
+)
+
+// 重定向像素平台
+const (
+	PixelProviderFacebook = "facebook"
+	PixelProviderGoogle   = "google"
+	PixelProviderLinkedIn = "linkedin"
+)
+
+// Link 缓存在 Redis 中的短链数据，由 generate-service 的缓存消息或 gRPC 回源写入
+type Link struct {
+	WorkspaceID  uint64                 `json:"-"` // 由路由键确定，不重复存储
+	URL          string                 `json:"url"`
+	ActivateAt   *time.Time             `json:"activate_at,omitempty"` // 生效时间，nil表示立即生效
+	ExpiresAt    *time.Time             `json:"expires_at,omitempty"`
+	PasswordHash string                 `json:"password_hash,omitempty"` // 访问密码的 bcrypt 哈希，空表示无需密码
+	MaxClicks    int64                  `json:"max_clicks,omitempty"`    // 最大访问次数，0表示不限制
+	Rules        []message.RedirectRule `json:"rules,omitempty"`         // 条件跳转规则，均未命中时跳转到 URL
+	Variants     []message.LinkVariant  `json:"variants,omitempty"`      // A/B 测试分组，未命中规则时按权重分流
+	DeepLink     *message.DeepLink      `json:"deep_link,omitempty"`     // 移动端深度链接
+	OpenGraph    *message.OpenGraph     `json:"open_graph,omitempty"`    // 分享卡片覆盖值
+	// 查询参数和路径转发选项
+	ForwardQuery  bool   `json:"forward_query,omitempty"`
+	QueryConflict string `json:"query_conflict,omitempty"` // destination / incoming / append，空同 destination
+	ForwardPath   bool   `json:"forward_path,omitempty"`
+	// URL 为带占位符的目标地址模板，跳转时用路径段和查询参数展开
+	Template bool `json:"template,omitempty"`
+	// 跳转方式：301 / 302 / 307 / 308 / meta_refresh / javascript，空表示 302
+	RedirectType string `json:"redirect_type,omitempty"`
+	// 重定向像素，非空时跳转前展示触发像素的中间页
+	Pixels []message.RetargetingPixel `json:"pixels,omitempty"`
+	// 被标记为可疑，访问者确认风险提示后才跳转
+	Flagged bool `json:"flagged,omitempty"`
+	// 只允许通过签名地址访问
+	SignedOnly bool `json:"signed_only,omitempty"`
+	// 链接和所属工作空间的IP访问控制列表，两者都通过才允许访问
+	IPAccess          *message.IPAccess `json:"ip_access,omitempty"`
+	WorkspaceIPAccess *message.IPAccess `json:"workspace_ip_access,omitempty"`
+}
+
+// Pending 是否尚未到生效时间
+func (l *Link) Pending() bool {
+	return l.ActivateAt != nil && l.ActivateAt.After(time.Now())
+}
+
+// Limited 是否限制访问次数
+func (l *Link) Limited() bool {
+	return l.MaxClicks > 0
+}
+
+// PerVisitor 跳转结果是否因访问者而异，此类链接的永久跳转也不允许共享缓存
+func (l *Link) PerVisitor() bool {
+	return len(l.Rules) > 0 || len(l.Variants) > 0 || l.DeepLink != nil || l.Template ||
+		l.ForwardQuery || l.ForwardPath || len(l.Pixels) > 0 || l.Flagged || l.SignedOnly || l.IPRestricted() || l.Limited() || l.Protected()
+}
+
+// IPRestricted 是否按客户端IP限制访问
+func (l *Link) IPRestricted() bool {
+	return l.IPAccess != nil || l.WorkspaceIPAccess != nil
+}
+
+// Protected 是否需要密码才能访问
+func (l *Link) Protected() bool {
+	return l.PasswordHash != ""
+}
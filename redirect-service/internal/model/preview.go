@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// LinkPreview 短链预览，访问预览不计入点击
+type LinkPreview struct {
+	ShortURL    string     `json:"short_url"`
+	Destination string     `json:"destination,omitempty"` // 链接不可访问、需要密码或签名、限制访问IP时不返回
+	Title       string     `json:"title,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	Status      string     `json:"status"` // active / flagged / pending / disabled / expired
+	Protected   bool       `json:"password_protected"`
+	SignedOnly  bool       `json:"signed_only"`
+}
@@ -0,0 +1,163 @@
+package clientip
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// 可信代理写入客户端地址的请求头
+const (
+	HeaderForwarded     = "forwarded"
+	HeaderXForwardedFor = "x-forwarded-for"
+	HeaderXRealIP       = "x-real-ip"
+)
+
+// Resolver 按可信代理解析客户端IP。只有直连地址属于可信代理时才读取转发头，
+// 并从右向左跳过可信代理，第一个不可信的地址即客户端IP，避免客户端伪造转发头
+type Resolver struct {
+	trusted []netip.Prefix
+	header  string
+}
+
+// NewResolver 创建解析器，trustedProxies 为可信代理的地址段或单个IP，header 为代理写入客户端地址的请求头，
+// 为空时使用 X-Forwarded-For。只读取这一个请求头，代理不处理的其他转发头可能由客户端伪造
+func NewResolver(trustedProxies []string, header string) (*Resolver, error) {
+	header = strings.ToLower(strings.TrimSpace(header))
+	switch header {
+	case "":
+		header = HeaderXForwardedFor
+	case HeaderForwarded, HeaderXForwardedFor, HeaderXRealIP:
+	default:
+		return nil, fmt.Errorf("invalid trusted header %q", header)
+	}
+	r := &Resolver{header: header}
+	for _, s := range trustedProxies {
+		prefix, err := parsePrefix(strings.TrimSpace(s))
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", s, err)
+		}
+		r.trusted = append(r.trusted, prefix)
+	}
+	return r, nil
+}
+
+// Resolve 返回请求的客户端IP。转发链取自配置的请求头，X-Real-IP 由代理直接写入客户端地址；
+// 转发链中出现无法解析的地址时停止，返回其右侧最近的代理地址
+func (r *Resolver) Resolve(req *http.Request) string {
+	peer, ok := parseAddr(req.RemoteAddr)
+	if !ok {
+		return req.RemoteAddr
+	}
+	if !r.isTrusted(peer) {
+		return peer.String()
+	}
+	var chain []string
+	switch r.header {
+	case HeaderForwarded:
+		chain = forwardedFor(req.Header)
+	case HeaderXForwardedFor:
+		chain = xForwardedFor(req.Header)
+	case HeaderXRealIP:
+		if realIP, ok := parseAddr(req.Header.Get("X-Real-IP")); ok {
+			return realIP.String()
+		}
+	}
+	client := peer
+	for i := len(chain) - 1; i >= 0; i-- {
+		hop, ok := parseAddr(chain[i])
+		if !ok {
+			break
+		}
+		client = hop
+		if !r.isTrusted(hop) {
+			break
+		}
+	}
+	return client.String()
+}
+
+// TrustedPeer 判断直连地址是否为可信代理，只有可信代理写入的 X-Forwarded-Proto 等转发头才能采信
+func (r *Resolver) TrustedPeer(req *http.Request) bool {
+	peer, ok := parseAddr(req.RemoteAddr)
+	return ok && r.isTrusted(peer)
+}
+
+func (r *Resolver) isTrusted(addr netip.Addr) bool {
+	for _, prefix := range r.trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// 提取所有 Forwarded 头中的 for 参数，按出现顺序排列；未设置该头时返回 nil
+func forwardedFor(h http.Header) []string {
+	values := h.Values("Forwarded")
+	if len(values) == 0 {
+		return nil
+	}
+	var chain []string
+	for _, v := range values {
+		for _, element := range strings.Split(v, ",") {
+			// 缺少 for 参数的元素无法确定来源，按无法解析的地址处理
+			hop := ""
+			for _, pair := range strings.Split(element, ";") {
+				key, value, found := strings.Cut(strings.TrimSpace(pair), "=")
+				if found && strings.EqualFold(key, "for") {
+					hop = strings.Trim(value, `"`)
+				}
+			}
+			chain = append(chain, hop)
+		}
+	}
+	return chain
+}
+
+// 拆分所有 X-Forwarded-For 头的地址；未设置该头时返回 nil
+func xForwardedFor(h http.Header) []string {
+	values := h.Values("X-Forwarded-For")
+	if len(values) == 0 {
+		return nil
+	}
+	var chain []string
+	for _, v := range values {
+		for _, hop := range strings.Split(v, ",") {
+			chain = append(chain, strings.TrimSpace(hop))
+		}
+	}
+	return chain
+}
+
+// 解析地址，兼容带端口、带方括号的 IPv6 和 IPv4 映射地址，unknown 或混淆标识视为无法解析
+func parseAddr(s string) (netip.Addr, bool) {
+	s = strings.TrimSpace(s)
+	if host, _, err := net.SplitHostPort(s); err == nil {
+		s = host
+	}
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "["), "]")
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.WithZone("").Unmap(), true
+}
+
+func parsePrefix(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
@@ -0,0 +1,107 @@
+package clientip
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	proxies := []string{"10.0.0.0/8", "2001:db8:ffff::/48", "127.0.0.1"}
+	resolvers := make(map[string]*Resolver)
+	for _, header := range []string{HeaderForwarded, HeaderXForwardedFor, HeaderXRealIP} {
+		r, err := NewResolver(proxies, header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resolvers[header] = r
+	}
+
+	tests := []struct {
+		name    string
+		header  string
+		remote  string
+		headers map[string][]string
+		want    string
+	}{
+		{name: "direct client", remote: "203.0.113.7:5123", want: "203.0.113.7"},
+		{name: "untrusted peer ignores headers", remote: "203.0.113.7:5123",
+			headers: map[string][]string{"X-Forwarded-For": {"198.51.100.1"}}, want: "203.0.113.7"},
+		{name: "spoofed leftmost entry", remote: "10.0.0.2:80",
+			headers: map[string][]string{"X-Forwarded-For": {"1.2.3.4, 198.51.100.9, 10.0.0.5"}}, want: "198.51.100.9"},
+		{name: "multiple xff headers", remote: "10.0.0.2:80",
+			headers: map[string][]string{"X-Forwarded-For": {"1.2.3.4", "198.51.100.9"}}, want: "198.51.100.9"},
+		{name: "all hops trusted", remote: "10.0.0.2:80",
+			headers: map[string][]string{"X-Forwarded-For": {"10.1.1.1, 10.0.0.5"}}, want: "10.1.1.1"},
+		{name: "invalid hop stops the walk", remote: "10.0.0.2:80",
+			headers: map[string][]string{"X-Forwarded-For": {"198.51.100.9, garbage, 10.0.0.5"}}, want: "10.0.0.5"},
+		// 代理只追加 X-Forwarded-For，客户端自带的 Forwarded 和 X-Real-IP 不能生效
+		{name: "spoofed forwarded behind xff proxy", remote: "10.0.0.2:80",
+			headers: map[string][]string{
+				"Forwarded":       {"for=192.0.2.66"},
+				"X-Real-Ip":       {"192.0.2.67"},
+				"X-Forwarded-For": {"198.51.100.9"},
+			}, want: "198.51.100.9"},
+		{name: "xff missing falls back to peer", remote: "10.0.0.2:80",
+			headers: map[string][]string{"Forwarded": {"for=192.0.2.66"}}, want: "10.0.0.2"},
+		{name: "forwarded ignores xff", header: HeaderForwarded, remote: "127.0.0.1:80",
+			headers: map[string][]string{
+				"Forwarded":       {`for=198.51.100.9;proto=https, for="[2001:db8:ffff::1]:4711"`},
+				"X-Forwarded-For": {"192.0.2.1"},
+			}, want: "198.51.100.9"},
+		{name: "forwarded ipv6 client", header: HeaderForwarded, remote: "[2001:db8:ffff::2]:443",
+			headers: map[string][]string{"Forwarded": {`for="[2001:db8::17]:4711"`}}, want: "2001:db8::17"},
+		{name: "forwarded obfuscated hop", header: HeaderForwarded, remote: "10.0.0.2:80",
+			headers: map[string][]string{"Forwarded": {"for=198.51.100.9, for=_hidden, for=10.0.0.5"}}, want: "10.0.0.5"},
+		{name: "x-real-ip from trusted peer", header: HeaderXRealIP, remote: "10.0.0.2:80",
+			headers: map[string][]string{
+				"X-Real-Ip":       {"198.51.100.9"},
+				"X-Forwarded-For": {"192.0.2.1"},
+			}, want: "198.51.100.9"},
+		{name: "ipv4 mapped peer", remote: "[::ffff:10.0.0.2]:80",
+			headers: map[string][]string{"X-Forwarded-For": {"198.51.100.9"}}, want: "198.51.100.9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "/abc", nil)
+			req.RemoteAddr = tt.remote
+			for k, vs := range tt.headers {
+				for _, v := range vs {
+					req.Header.Add(k, v)
+				}
+			}
+			header := tt.header
+			if header == "" {
+				header = HeaderXForwardedFor
+			}
+			if got := resolvers[header].Resolve(req); got != tt.want {
+				t.Errorf("Resolve = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := NewResolver([]string{"not-a-cidr"}, ""); err == nil {
+		t.Error("NewResolver accepted an invalid proxy")
+	}
+	if _, err := NewResolver(proxies, "x-client-ip"); err == nil {
+		t.Error("NewResolver accepted an unsupported header")
+	}
+}
+
+func TestTrustedPeer(t *testing.T) {
+	r, err := NewResolver([]string{"10.0.0.0/8"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for remote, want := range map[string]bool{
+		"10.0.0.2:80":          true,
+		"[::ffff:10.0.0.2]:80": true,
+		"203.0.113.7:5123":     false,
+		"garbage":              false,
+	} {
+		req, _ := http.NewRequest(http.MethodGet, "/abc", nil)
+		req.RemoteAddr = remote
+		if got := r.TrustedPeer(req); got != want {
+			t.Errorf("TrustedPeer(%q) = %v, want %v", remote, got, want)
+		}
+	}
+}
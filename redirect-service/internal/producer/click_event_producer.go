@@ -103,3 +103,33 @@ func (kp *KafkaProducer) SendClickMessage(topic string, message *message.ClickEv
 	}
 	return nil
 }
+
+// SendMessage 发送通用事件消息
+func (kp *KafkaProducer) SendMessage(topic string, msg message.Message) error {
+	if err := msg.Validate(); err != nil {
+		return fmt.Errorf("message validation failed: %v", err)
+	}
+	messageBytes, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("message marshalling failed: %v", err)
+	}
+	kafkaMsg := &sarama.ProducerMessage{
+		Topic:     topic,
+		Key:       sarama.StringEncoder(msg.GetKey()),
+		Value:     sarama.ByteEncoder(messageBytes),
+		Timestamp: time.Now(),
+		Headers: []sarama.RecordHeader{
+			{
+				Key:   []byte("event_type"),
+				Value: []byte(msg.GetEventType()),
+			},
+		},
+	}
+	select {
+	case kp.producer.Input() <- kafkaMsg:
+		log.Printf("Message added to send queue: topic=%s, key=%s", topic, msg.GetKey())
+	case <-time.After(5 * time.Second):
+		return fmt.Errorf("timeout sending message to input channel")
+	}
+	return nil
+}
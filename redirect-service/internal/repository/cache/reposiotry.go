@@ -2,18 +2,87 @@ package cache
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"redirect-service/internal/client/redis"
 	"redirect-service/internal/config"
+	"redirect-service/internal/model"
 	"shared/constants"
 	shrErrors "shared/errors"
+	"shared/hostname"
+	"shared/message"
+	"strconv"
+	"strings"
 	"time"
 
 	redis9 "github.com/redis/go-redis/v9"
 )
 
+// 缓存键按工作空间隔离：
+//
+//	<prefix>:ws:<workspace_id>:url:<domain>:<short_code>     短链数据（JSON 编码的 model.Link）
+//	<prefix>:route:<domain>:<short_code>                     短码所属工作空间
+//	<prefix>:ws:<workspace_id>:clicks:<domain>:<short_code>  限次链接的已访问次数，不过期
+//	<prefix>:pwd_fail:<ip>                                   密码错误次数
+//	<prefix>:unfurl:<sha256(url)>                            目标页面的分享卡片元数据
+//
+// 默认域名的 <domain> 为 hostname.DefaultKey。(域名, 短码) 全局唯一，
+// 路由键只能由所属工作空间写入和删除，其他租户无法覆盖。
+// 脚本内拼接的键与 KEYS 位于同一实例，当前仅支持单机/哨兵部署。
+var (
+	getScript = redis9.NewScript(`
+local ws = redis.call('GET', KEYS[1])
+if not ws then
+	return false
+end
+local url = redis.call('GET', ARGV[1] .. ':ws:' .. ws .. ':url:' .. ARGV[2])
+if not url then
+	return false
+end
+return {ws, url}
+`)
+
+	setScript = redis9.NewScript(`
+local owner = redis.call('GET', KEYS[1])
+if owner and owner ~= ARGV[1] then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[3])
+redis.call('SET', KEYS[2], ARGV[2], 'PX', ARGV[3])
+return 1
+`)
+
+	delScript = redis9.NewScript(`
+redis.call('DEL', KEYS[2])
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	redis.call('DEL', KEYS[1])
+end
+return 1
+`)
+
+	// 计数并在首次写入时设置过期时间，返回窗口内的累计次数
+	incrScript = redis9.NewScript(`
+local n = redis.call('INCR', KEYS[1])
+if n == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return n
+`)
+
+	// 访问次数未达上限时计数，返回本次访问的序号；已达上限返回 -1
+	consumeScript = redis9.NewScript(`
+local n = tonumber(redis.call('GET', KEYS[1]) or '0')
+if n >= tonumber(ARGV[1]) then
+	return -1
+end
+return redis.call('INCR', KEYS[1])
+`)
+)
+
 type Repository struct {
 	client *redis.Client
 	prefix string
@@ -28,55 +97,182 @@ func NewRepository(client *redis.Client, cfg *config.CacheConfig) *Repository {
 	}
 }
 
-func (r *Repository) getKey(typ string, id string) string {
-	return fmt.Sprintf("%s:%s:%s", r.prefix, typ, id)
+func (r *Repository) getKey(workspaceID uint64, typ string, id string) string {
+	return fmt.Sprintf("%s:ws:%d:%s:%s", r.prefix, workspaceID, typ, id)
+}
+
+func (r *Repository) getRouteKey(domain, shortCode string) string {
+	return fmt.Sprintf("%s:route:%s:%s", r.prefix, hostname.Key(domain), shortCode)
 }
 
-// GetOriginalURL 从 Redis 缓存获取短链映射
-func (r *Repository) GetOriginalURL(ctx context.Context, shortCode string) (string, error) {
-	key := r.getKey("url", shortCode)
-	result, err := r.client.Get(ctx, key)
+// 域名与短码组成的缓存标识
+func linkID(domain, shortCode string) string {
+	return hostname.Key(domain) + ":" + shortCode
+}
+
+// GetLink 从 Redis 缓存获取短链数据及其所属工作空间
+func (r *Repository) GetLink(ctx context.Context, domain, shortCode string) (*model.Link, error) {
+	result, err := r.client.RunScript(ctx, getScript, []string{r.getRouteKey(domain, shortCode)}, r.prefix, linkID(domain, shortCode))
 	if err != nil {
-		if errors.Is(err, redis9.Nil) {
-			return "", shrErrors.ErrLinkNotFound
-		}
-		return "", &shrErrors.RepositoryError{Operation: "GetShortURL", Err: err}
+		return nil, &shrErrors.RepositoryError{Operation: "GetLink", Err: err}
 	}
-	return result, nil
+	values, ok := result.([]interface{})
+	if !ok || len(values) != 2 {
+		r.client.RecordLookup(false)
+		return nil, shrErrors.ErrLinkNotFound
+	}
+	r.client.RecordLookup(true)
+	workspaceID, err := strconv.ParseUint(fmt.Sprint(values[0]), 10, 64)
+	if err != nil {
+		return nil, &shrErrors.RepositoryError{Operation: "GetLink", Err: err}
+	}
+	link, err := decodeLink(fmt.Sprint(values[1]))
+	if err != nil {
+		return nil, &shrErrors.RepositoryError{Operation: "GetLink", Err: err}
+	}
+	link.WorkspaceID = workspaceID
+	return link, nil
 }
 
-// SetShortURL 设置短链映射到缓存
-func (r *Repository) SetShortURL(ctx context.Context, shortCode string, longURL string, expiredAt *time.Time) error {
+// SetLink 写入短链数据到缓存，短码已属于其他工作空间时拒绝写入
+func (r *Repository) SetLink(ctx context.Context, domain, shortCode string, link *model.Link) error {
 	ttl := r.ttl
-	if expiredAt != nil {
+	if link.ExpiresAt != nil {
 		now := time.Now()
-		ttl = expiredAt.Sub(now)
-		// 已过期
-		if ttl <= 0 {
+		ttl = link.ExpiresAt.Sub(now)
+		// 已过期，不足1毫秒按已过期处理，避免 PX 取整为 0
+		if ttl < time.Millisecond {
 			// 已过期，设置为24小时
 			log.Printf("⚠️ expiredAt %v is too close (ttl: %v), using ExpirationToleranceCacheTTL: %v",
-				*expiredAt, ttl, constants.ExpirationToleranceCacheTTL)
+				*link.ExpiresAt, ttl, constants.ExpirationToleranceCacheTTL)
 			ttl = constants.ExpirationToleranceCacheTTL
 		} else if ttl > constants.MaxCacheTTL {
 			// 超过30天，设置为TTL上线30天
 			ttl = constants.MaxCacheTTL
 		}
 	}
-	key := r.getKey("url", shortCode)
-	err := r.client.Set(ctx, key, longURL, ttl)
+	// 尚未生效的链接只缓存到生效时间，之后回源获取生效后的数据。
+	// 只读取一次时钟，距生效不足1毫秒时不缓存，避免 PX 为 0 或负数被 Redis 拒绝
+	if link.ActivateAt != nil {
+		if d := time.Until(*link.ActivateAt); d > 0 {
+			if d <= time.Millisecond {
+				return nil
+			}
+			if d < ttl {
+				ttl = d
+			}
+		}
+	}
+	value, err := json.Marshal(link)
+	if err != nil {
+		return &shrErrors.RepositoryError{Operation: "SetLink", Err: err}
+	}
+	keys := []string{r.getRouteKey(domain, shortCode), r.getKey(link.WorkspaceID, "url", linkID(domain, shortCode))}
+	result, err := r.client.RunScript(ctx, setScript, keys, link.WorkspaceID, string(value), ttl.Milliseconds())
 	if err != nil {
-		return &shrErrors.RepositoryError{Operation: "SetShortURL", Err: err}
+		return &shrErrors.RepositoryError{Operation: "SetLink", Err: err}
+	}
+	if n, ok := result.(int64); ok && n == 0 {
+		return shrErrors.ErrNamespaceClash
 	}
 	return nil
 }
 
-// DeleteShortURL 删除缓存中的短链接
-func (r *Repository) DeleteShortURL(ctx context.Context, shortCode string) error {
-	key := r.getKey("url", shortCode)
-
-	err := r.client.Del(ctx, key)
+// DeleteShortURL 删除缓存中的短链接，只清理所属工作空间的路由
+func (r *Repository) DeleteShortURL(ctx context.Context, workspaceID uint64, domain, shortCode string) error {
+	keys := []string{r.getRouteKey(domain, shortCode), r.getKey(workspaceID, "url", linkID(domain, shortCode))}
+	_, err := r.client.RunScript(ctx, delScript, keys, workspaceID)
 	if err != nil {
 		return &shrErrors.RepositoryError{Operation: "DeleteShortURL", Err: err}
 	}
 	return nil
 }
+
+// ConsumeClick 原子地消耗一次访问次数，返回本次访问的序号，已达上限时返回 -1
+func (r *Repository) ConsumeClick(ctx context.Context, workspaceID uint64, domain, shortCode string, maxClicks int64) (int64, error) {
+	result, err := r.client.RunScript(ctx, consumeScript, []string{r.getKey(workspaceID, "clicks", linkID(domain, shortCode))}, maxClicks)
+	if err != nil {
+		return 0, &shrErrors.RepositoryError{Operation: "ConsumeClick", Err: err}
+	}
+	n, _ := result.(int64)
+	return n, nil
+}
+
+// ResetClicks 清除限次链接的访问计数
+func (r *Repository) ResetClicks(ctx context.Context, workspaceID uint64, domain, shortCode string) error {
+	if err := r.client.Del(ctx, r.getKey(workspaceID, "clicks", linkID(domain, shortCode))); err != nil {
+		return &shrErrors.RepositoryError{Operation: "ResetClicks", Err: err}
+	}
+	return nil
+}
+
+// IncrPasswordFailures 记录一次密码错误，返回窗口内该IP的累计错误次数
+func (r *Repository) IncrPasswordFailures(ctx context.Context, ip string, window time.Duration) (int64, error) {
+	result, err := r.client.RunScript(ctx, incrScript, []string{r.getPasswordFailKey(ip)}, window.Milliseconds())
+	if err != nil {
+		return 0, &shrErrors.RepositoryError{Operation: "IncrPasswordFailures", Err: err}
+	}
+	n, _ := result.(int64)
+	return n, nil
+}
+
+// GetPasswordFailures 查询窗口内该IP的密码错误次数
+func (r *Repository) GetPasswordFailures(ctx context.Context, ip string) (int64, error) {
+	val, err := r.client.Get(ctx, r.getPasswordFailKey(ip))
+	if err != nil {
+		if errors.Is(err, redis9.Nil) {
+			return 0, nil
+		}
+		return 0, &shrErrors.RepositoryError{Operation: "GetPasswordFailures", Err: err}
+	}
+	return strconv.ParseInt(val, 10, 64)
+}
+
+func (r *Repository) getPasswordFailKey(ip string) string {
+	return fmt.Sprintf("%s:pwd_fail:%s", r.prefix, ip)
+}
+
+// GetOpenGraph 查询目标页面的分享卡片元数据，未缓存时返回 nil
+func (r *Repository) GetOpenGraph(ctx context.Context, pageURL string) (*message.OpenGraph, error) {
+	val, err := r.client.Get(ctx, r.getUnfurlKey(pageURL))
+	if err != nil {
+		if errors.Is(err, redis9.Nil) {
+			return nil, nil
+		}
+		return nil, &shrErrors.RepositoryError{Operation: "GetOpenGraph", Err: err}
+	}
+	var og message.OpenGraph
+	if err := json.Unmarshal([]byte(val), &og); err != nil {
+		return nil, &shrErrors.RepositoryError{Operation: "GetOpenGraph", Err: err}
+	}
+	return &og, nil
+}
+
+// SetOpenGraph 缓存目标页面的分享卡片元数据，抓取失败时缓存空值，避免爬虫反复触发抓取
+func (r *Repository) SetOpenGraph(ctx context.Context, pageURL string, og *message.OpenGraph, ttl time.Duration) error {
+	data, err := json.Marshal(og)
+	if err != nil {
+		return &shrErrors.RepositoryError{Operation: "SetOpenGraph", Err: err}
+	}
+	if err := r.client.Set(ctx, r.getUnfurlKey(pageURL), string(data), ttl); err != nil {
+		return &shrErrors.RepositoryError{Operation: "SetOpenGraph", Err: err}
+	}
+	return nil
+}
+
+func (r *Repository) getUnfurlKey(pageURL string) string {
+	sum := sha256.Sum256([]byte(pageURL))
+	return fmt.Sprintf("%s:unfurl:%s", r.prefix, hex.EncodeToString(sum[:]))
+}
+
+// 解析缓存值，兼容升级前直接存储长链接的旧格式
+func decodeLink(value string) (*model.Link, error) {
+	if !strings.HasPrefix(value, "{") {
+		return &model.Link{URL: value}, nil
+	}
+	var link model.Link
+	if err := json.Unmarshal([]byte(value), &link); err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
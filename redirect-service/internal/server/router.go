@@ -1,6 +1,7 @@
 package server
 
 import (
+	"log"
 	"net/http"
 	"redirect-service/internal/config"
 	"redirect-service/internal/handler"
@@ -16,14 +17,23 @@ func setupRouter(config *config.Config, srv *Server) {
 	// 设置Gin模式
 	gin.SetMode(config.Server.Mode)
 	router := gin.New()
+	// gin 日志中的客户端IP与 ClientIP 中间件使用相同的可信代理
+	if err := router.SetTrustedProxies(config.ClientIP.TrustedProxies); err != nil {
+		log.Printf("set trusted proxies failed: %v", err)
+	}
 
 	// 全局中间件
 	router.Use(gin.Logger())
 	router.Use(gin.Recovery())
+	router.Use(middleware.ErrorHandler())
+	router.Use(middleware.ClientIP(srv.clientIP))
+	if config.ClientIP.SimulateRandomIP {
+		router.Use(middleware.SimulateClientIP())
+	}
 	router.Use(middleware.AuthMiddleware())
 
 	// 初始化处理器
-	redirectHandler := handler.NewRedirectHandler(*srv.redirectSvc)
+	redirectHandler := handler.NewRedirectHandler(*srv.redirectSvc, srv.passwordSvc, srv.unfurlSvc, srv.signer, &config.Schedule, &config.Access, &config.Redirect, config.Server.DefaultHosts)
 
 	// 健康检查点
 	router.GET("/health", func(c *gin.Context) {
@@ -54,8 +64,11 @@ func setupRouter(config *config.Config, srv *Server) {
 		c.Status(http.StatusNoContent)
 	})
 
-	// 重定向路由
+	// 重定向路由，/:code/*path 用于开启路径转发的链接
 	router.GET("/:code", redirectHandler.Redirect)
+	router.GET("/:code/*path", redirectHandler.Redirect)
+	router.POST("/:code", redirectHandler.SubmitForm)
+	router.POST("/:code/*path", redirectHandler.SubmitForm)
 
 	api := router.Group("/api/v1")
 
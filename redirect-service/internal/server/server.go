@@ -12,15 +12,22 @@ import (
 	"redirect-service/internal/client/redis"
 	"redirect-service/internal/config"
 	"redirect-service/internal/consumer"
+	"redirect-service/internal/pkg/clientip"
 	"redirect-service/internal/pkg/idgen"
 	"redirect-service/internal/pkg/ipgen"
 	"redirect-service/internal/producer"
 	"redirect-service/internal/repository/cache"
 	cacheService "redirect-service/internal/service/cache"
+	detector "redirect-service/internal/service/device_detector"
 	"redirect-service/internal/service/geoip"
+	passwordService "redirect-service/internal/service/password"
 	redirectService "redirect-service/internal/service/redirect"
+	unfurlService "redirect-service/internal/service/unfurl"
+	"shared/signedlink"
 	"syscall"
 	"time"
+
+	"github.com/gin-gonic/gin"
 )
 
 type Server struct {
@@ -30,6 +37,10 @@ type Server struct {
 	redisClient   *redis.Client
 	cacheRepo     *cache.Repository
 	redirectSvc   *redirectService.Service
+	passwordSvc   *passwordService.Service
+	unfurlSvc     *unfurlService.Service
+	signer        *signedlink.KeyRing
+	clientIP      *clientip.Resolver
 	CacheSvc      *cacheService.Service
 	genClient     *generate.Client
 	kafkaConsumer *consumer.KafkaConsumer
@@ -45,8 +56,20 @@ func New(cfg *config.Config) *Server {
 }
 
 func (s *Server) Start() error {
-	// 初始化IP随机生成器
-	ipgen.Init()
+	// 初始化客户端IP解析，随机IP模拟只在非 release 模式下生效
+	clientIP, err := clientip.NewResolver(s.config.ClientIP.TrustedProxies, s.config.ClientIP.TrustedHeader)
+	if err != nil {
+		return fmt.Errorf("init client ip resolver failed: %w", err)
+	}
+	s.clientIP = clientIP
+	if s.config.ClientIP.SimulateRandomIP {
+		if s.config.Server.Mode == gin.ReleaseMode {
+			log.Printf("client_ip.simulate_random_ip is ignored in release mode")
+			s.config.ClientIP.SimulateRandomIP = false
+		} else {
+			ipgen.Init()
+		}
+	}
 
 	// 初始化Redis
 	redisClient, err := redis.NewRedis(&s.config.Redis, &s.config.Breaker)
@@ -112,9 +135,23 @@ func (s *Server) Start() error {
 		s.cacheRepo,
 		s.kafkaProducer,
 		s.geoIPSvc,
+		detector.NewDefaultDeviceDetector(),
 		s.generator,
 	)
 
+	// 初始化密码校验服务
+	s.passwordSvc = passwordService.NewService(s.cacheRepo, &s.config.Password)
+
+	// 初始化签名密钥环，只用于校验
+	signer, err := signedlink.NewKeyRing(s.config.Signing.Keys, "")
+	if err != nil {
+		return fmt.Errorf("init signing keys failed: %w", err)
+	}
+	s.signer = signer
+
+	// 初始化分享卡片服务
+	s.unfurlSvc = unfurlService.NewService(s.cacheRepo, &s.config.Unfurl)
+
 	// 设置路由
 	setupRouter(s.config, s)
 
@@ -2,8 +2,8 @@ package cache
 
 import (
 	"context"
+	"redirect-service/internal/model"
 	"redirect-service/internal/repository/cache"
-	"time"
 )
 
 type Service struct {
@@ -14,14 +14,18 @@ func NewService(cacheRepo *cache.Repository) *Service {
 	return &Service{cacheRepo: cacheRepo}
 }
 
-func (s *Service) GetOriginalUrl(ctx context.Context, shortCode string) (string, error) {
-	return s.cacheRepo.GetOriginalURL(ctx, shortCode)
+func (s *Service) GetLink(ctx context.Context, domain, shortCode string) (*model.Link, error) {
+	return s.cacheRepo.GetLink(ctx, domain, shortCode)
 }
 
-func (s *Service) SetShortUrl(ctx context.Context, shortCode string, originalUrl string, expiredAt *time.Time) error {
-	return s.cacheRepo.SetShortURL(ctx, shortCode, originalUrl, expiredAt)
+func (s *Service) SetLink(ctx context.Context, domain, shortCode string, link *model.Link) error {
+	return s.cacheRepo.SetLink(ctx, domain, shortCode, link)
 }
 
-func (s *Service) DelShortUrl(ctx context.Context, shortCode string) error {
-	return s.cacheRepo.DeleteShortURL(ctx, shortCode)
+func (s *Service) DelShortUrl(ctx context.Context, workspaceID uint64, domain, shortCode string) error {
+	return s.cacheRepo.DeleteShortURL(ctx, workspaceID, domain, shortCode)
+}
+
+func (s *Service) ResetClicks(ctx context.Context, workspaceID uint64, domain, shortCode string) error {
+	return s.cacheRepo.ResetClicks(ctx, workspaceID, domain, shortCode)
 }
@@ -0,0 +1,86 @@
+package deeplink
+
+import (
+	"net/url"
+	"shared/message"
+	"strings"
+)
+
+// 平台取值与设备识别结果中的操作系统一致
+const (
+	PlatformIOS     = "ios"
+	PlatformAndroid = "android"
+)
+
+// Target 深度链接跳转目标
+type Target struct {
+	Platform    string
+	AppURL      string // 打开 App 的地址，Android 为 intent:// 地址；为空表示直接跳转 FallbackURL
+	FallbackURL string // 未安装 App 时的地址：应用商店，未配置时为网页地址
+}
+
+// Resolve 按访问者操作系统选择深度链接目标，非 iOS/Android 或未配置对应平台时返回 nil
+func Resolve(dl *message.DeepLink, os, webURL string) *Target {
+	if dl == nil {
+		return nil
+	}
+	switch os {
+	case PlatformIOS:
+		if dl.IOSURL == "" && dl.IOSStoreURL == "" {
+			return nil
+		}
+		return &Target{
+			Platform:    PlatformIOS,
+			AppURL:      dl.IOSURL,
+			FallbackURL: firstNonEmpty(dl.IOSStoreURL, webURL),
+		}
+	case PlatformAndroid:
+		if dl.AndroidURL == "" && dl.AndroidStoreURL == "" {
+			return nil
+		}
+		target := &Target{
+			Platform:    PlatformAndroid,
+			FallbackURL: firstNonEmpty(dl.AndroidStoreURL, webURL),
+		}
+		if dl.AndroidURL != "" {
+			target.AppURL = IntentURL(dl.AndroidURL, dl.AndroidPackage, target.FallbackURL)
+		}
+		return target
+	}
+	return nil
+}
+
+// IntentURL 将 scheme://path 形式的 App 地址转换为 Chrome 可识别的 intent:// 地址，
+// 未安装 App 时浏览器跳转到 fallback
+func IntentURL(appURL, pkg, fallback string) string {
+	scheme, rest, ok := strings.Cut(appURL, "://")
+	if !ok {
+		return ""
+	}
+	// 片段标识与 #Intent 冲突，需去掉
+	rest, _, _ = strings.Cut(rest, "#")
+	var b strings.Builder
+	b.WriteString("intent://")
+	b.WriteString(rest)
+	b.WriteString("#Intent;scheme=")
+	b.WriteString(scheme)
+	if pkg != "" {
+		b.WriteString(";package=")
+		b.WriteString(pkg)
+	}
+	if fallback != "" {
+		b.WriteString(";S.browser_fallback_url=")
+		b.WriteString(url.QueryEscape(fallback))
+	}
+	b.WriteString(";end")
+	return b.String()
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,60 @@
+package deeplink
+
+import (
+	"shared/message"
+	"testing"
+)
+
+var deepLink = &message.DeepLink{
+	IOSURL:          "myapp://item/42",
+	IOSStoreURL:     "https://apps.apple.com/app/id123",
+	AndroidURL:      "myapp://item/42?ref=short#top",
+	AndroidPackage:  "com.example.app",
+	AndroidStoreURL: "https://play.google.com/store/apps/details?id=com.example.app",
+}
+
+func TestIntentURL(t *testing.T) {
+	got := IntentURL(deepLink.AndroidURL, deepLink.AndroidPackage, deepLink.AndroidStoreURL)
+	want := "intent://item/42?ref=short#Intent;scheme=myapp;package=com.example.app;" +
+		"S.browser_fallback_url=https%3A%2F%2Fplay.google.com%2Fstore%2Fapps%2Fdetails%3Fid%3Dcom.example.app;end"
+	if got != want {
+		t.Fatalf("IntentURL() = %q, want %q", got, want)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	const web = "https://example.com/item/42"
+	tests := []struct {
+		name     string
+		dl       *message.DeepLink
+		os       string
+		app      string
+		fallback string
+		isNil    bool
+	}{
+		{name: "ios", dl: deepLink, os: "ios", app: "myapp://item/42", fallback: deepLink.IOSStoreURL},
+		{name: "android", dl: deepLink, os: "android", fallback: deepLink.AndroidStoreURL},
+		{name: "desktop", dl: deepLink, os: "windows", isNil: true},
+		{name: "ios without store", dl: &message.DeepLink{IOSURL: "myapp://x"}, os: "ios", app: "myapp://x", fallback: web},
+		{name: "android store only", dl: &message.DeepLink{AndroidStoreURL: "https://play.google.com/x"}, os: "android", fallback: "https://play.google.com/x"},
+		{name: "platform not configured", dl: &message.DeepLink{IOSURL: "myapp://x"}, os: "android", isNil: true},
+		{name: "no deep link", os: "ios", isNil: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Resolve(tt.dl, tt.os, web)
+			if tt.isNil {
+				if got != nil {
+					t.Fatalf("Resolve() = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil || got.FallbackURL != tt.fallback {
+				t.Fatalf("Resolve() = %+v, want fallback %q", got, tt.fallback)
+			}
+			if tt.os == "ios" && got.AppURL != tt.app {
+				t.Fatalf("AppURL = %q, want %q", got.AppURL, tt.app)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package detector
+
+import (
+	"strings"
+
+	"github.com/ua-parser/uap-go/uaparser"
+)
+
+type DeviceDetector interface {
+	Parse(userAgent string) *DeviceInfo
+}
+
+type DefaultDeviceDetector struct {
+	parser *uaparser.Parser
+}
+
+func NewDefaultDeviceDetector() DeviceDetector {
+	parser := uaparser.NewFromSaved() // 使用内置规则
+	return &DefaultDeviceDetector{parser: parser}
+}
+
+// 社交平台和即时通讯工具的链接预览爬虫，抓取页面的 Open Graph 标签生成分享卡片。
+// 部分 UA 不含 bot 特征（如 facebookexternalhit、WhatsApp），需单独识别
+var linkPreviewAgents = []string{
+	"facebookexternalhit", "facebot", "twitterbot", "slackbot", "slack-imgproxy",
+	"linkedinbot", "discordbot", "telegrambot", "whatsapp", "skypeuripreview",
+	"pinterestbot", "redditbot", "embedly",
+}
+
+// DeviceInfo 设备信息，取值与跳转规则中的设备类型和操作系统一致
+type DeviceInfo struct {
+	DeviceType  string `json:"device_type"`  // mobile / tablet / desktop / bot / other
+	OS          string `json:"os"`           // ios / android / windows / macos / linux / other
+	LinkPreview bool   `json:"link_preview"` // 链接预览爬虫，DeviceType 同时为 bot
+}
+
+func (d *DefaultDeviceDetector) Parse(userAgent string) *DeviceInfo {
+	if userAgent == "" {
+		return &DeviceInfo{DeviceType: "other", OS: "other"}
+	}
+	if isLinkPreviewAgent(userAgent) {
+		return &DeviceInfo{DeviceType: "bot", OS: "other", LinkPreview: true}
+	}
+	client := d.parser.Parse(userAgent)
+	return &DeviceInfo{
+		DeviceType: mapDeviceType(client.Device.Family, userAgent),
+		OS:         normalizeOS(client.Os.Family),
+	}
+}
+
+func isLinkPreviewAgent(userAgent string) bool {
+	userAgent = strings.ToLower(userAgent)
+	for _, agent := range linkPreviewAgents {
+		if strings.Contains(userAgent, agent) {
+			return true
+		}
+	}
+	return false
+}
+
+// 设备类型映射，平板需先于手机判断（iPad 和安卓平板的 UA 同样包含 mobile 特征）
+func mapDeviceType(deviceFamily, userAgent string) string {
+	deviceFamily = strings.ToLower(deviceFamily)
+	userAgent = strings.ToLower(userAgent)
+
+	// 机器人检测
+	if strings.Contains(userAgent, "bot") ||
+		strings.Contains(userAgent, "crawler") ||
+		strings.Contains(userAgent, "spider") {
+		return "bot"
+	}
+
+	switch {
+	case strings.Contains(deviceFamily, "tablet") ||
+		strings.Contains(userAgent, "tablet") ||
+		strings.Contains(userAgent, "ipad"):
+		return "tablet"
+	case strings.Contains(deviceFamily, "mobile") ||
+		strings.Contains(userAgent, "mobile") ||
+		strings.Contains(userAgent, "android") ||
+		strings.Contains(userAgent, "iphone"):
+		return "mobile"
+	case strings.Contains(userAgent, "windows") ||
+		strings.Contains(userAgent, "macintosh") ||
+		strings.Contains(userAgent, "linux"):
+		return "desktop"
+	default:
+		return "other"
+	}
+}
+
+// 操作系统规范化
+func normalizeOS(os string) string {
+	os = strings.ToLower(os)
+
+	switch {
+	case strings.Contains(os, "windows"):
+		return "windows"
+	case strings.Contains(os, "ios"):
+		return "ios"
+	case strings.Contains(os, "mac"):
+		return "macos"
+	case strings.Contains(os, "android"):
+		return "android"
+	case strings.Contains(os, "linux"), strings.Contains(os, "ubuntu"):
+		return "linux"
+	default:
+		return "other"
+	}
+}
@@ -0,0 +1,96 @@
+package passthrough
+
+import (
+	"net/url"
+	"strings"
+)
+
+// 转发查询参数与目标地址参数同名时的处理方式
+const (
+	ConflictDestination = "destination" // 保留目标地址的参数，默认
+	ConflictIncoming    = "incoming"    // 使用访问地址的参数
+	ConflictAppend      = "append"      // 两者都保留
+)
+
+// Options 链接的转发选项
+type Options struct {
+	ForwardQuery  bool
+	QueryConflict string
+	ForwardPath   bool
+}
+
+// Apply 将访问地址中短码之后的路径和查询参数按选项转发到目标地址。
+// suffix 为转义后的路径后缀（以 / 开头），rawQuery 为访问地址的原始查询串；
+// 目标地址无法解析时原样返回
+func Apply(destination string, opts Options, suffix, rawQuery string) string {
+	if (!opts.ForwardPath || suffix == "") && (!opts.ForwardQuery || rawQuery == "") {
+		return destination
+	}
+	u, err := url.Parse(destination)
+	if err != nil {
+		return destination
+	}
+	if opts.ForwardPath && suffix != "" {
+		if path, err := url.PathUnescape(suffix); err == nil {
+			u.RawPath = strings.TrimSuffix(u.EscapedPath(), "/") + suffix
+			u.Path = strings.TrimSuffix(u.Path, "/") + path
+		}
+	}
+	if opts.ForwardQuery && rawQuery != "" {
+		u.RawQuery = MergeQuery(u.RawQuery, rawQuery, opts.QueryConflict)
+	}
+	return u.String()
+}
+
+// MergeQuery 合并目标地址和访问地址的查询串，保留两者原有的参数顺序和编码
+func MergeQuery(destination, incoming, conflict string) string {
+	dst := splitQuery(destination)
+	in := splitQuery(incoming)
+	switch conflict {
+	case ConflictAppend:
+	case ConflictIncoming:
+		keys := queryKeys(in)
+		dst = filterQuery(dst, func(key string) bool { return !keys[key] })
+	default:
+		keys := queryKeys(dst)
+		in = filterQuery(in, func(key string) bool { return !keys[key] })
+	}
+	return strings.Join(append(dst, in...), "&")
+}
+
+func splitQuery(raw string) []string {
+	var pairs []string
+	for _, pair := range strings.Split(raw, "&") {
+		if pair != "" {
+			pairs = append(pairs, pair)
+		}
+	}
+	return pairs
+}
+
+// 参数名解码后比较，a%20b 与 a+b 视为同名
+func queryKey(pair string) string {
+	key, _, _ := strings.Cut(pair, "=")
+	if unescaped, err := url.QueryUnescape(key); err == nil {
+		return unescaped
+	}
+	return key
+}
+
+func queryKeys(pairs []string) map[string]bool {
+	keys := make(map[string]bool, len(pairs))
+	for _, pair := range pairs {
+		keys[queryKey(pair)] = true
+	}
+	return keys
+}
+
+func filterQuery(pairs []string, keep func(key string) bool) []string {
+	var kept []string
+	for _, pair := range pairs {
+		if keep(queryKey(pair)) {
+			kept = append(kept, pair)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,72 @@
+package passthrough
+
+import "testing"
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name        string
+		destination string
+		opts        Options
+		suffix      string
+		query       string
+		want        string
+	}{
+		{
+			name:        "disabled",
+			destination: "https://example.com/docs",
+			suffix:      "/api",
+			query:       "a=1",
+			want:        "https://example.com/docs",
+		},
+		{
+			name:        "path suffix",
+			destination: "https://example.com/docs/?v=1",
+			opts:        Options{ForwardPath: true},
+			suffix:      "/api/v2",
+			want:        "https://example.com/docs/api/v2?v=1",
+		},
+		{
+			name:        "escaped path suffix",
+			destination: "https://example.com",
+			opts:        Options{ForwardPath: true},
+			suffix:      "/a%20b/c%2Fd",
+			want:        "https://example.com/a%20b/c%2Fd",
+		},
+		{
+			name:        "destination wins",
+			destination: "https://example.com/?utm_source=mail&x=1",
+			opts:        Options{ForwardQuery: true},
+			query:       "utm_source=ads&ref=abc",
+			want:        "https://example.com/?utm_source=mail&x=1&ref=abc",
+		},
+		{
+			name:        "incoming wins",
+			destination: "https://example.com/?utm_source=mail&x=1",
+			opts:        Options{ForwardQuery: true, QueryConflict: ConflictIncoming},
+			query:       "utm_source=ads&ref=abc",
+			want:        "https://example.com/?x=1&utm_source=ads&ref=abc",
+		},
+		{
+			name:        "append",
+			destination: "https://example.com/?tag=a",
+			opts:        Options{ForwardQuery: true, QueryConflict: ConflictAppend},
+			query:       "tag=b",
+			want:        "https://example.com/?tag=a&tag=b",
+		},
+		{
+			name:        "path and query",
+			destination: "https://example.com/docs",
+			opts:        Options{ForwardPath: true, ForwardQuery: true},
+			suffix:      "/search",
+			query:       "q=go",
+			want:        "https://example.com/docs/search?q=go",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Apply(tt.destination, tt.opts, tt.suffix, tt.query); got != tt.want {
+				t.Fatalf("Apply() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
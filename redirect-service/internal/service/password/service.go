@@ -0,0 +1,121 @@
+package password
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"log"
+	"redirect-service/internal/config"
+	"redirect-service/internal/model"
+	"redirect-service/internal/repository/cache"
+	shrErrors "shared/errors"
+	"shared/hostname"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// CookieName 访问凭证 Cookie 名称，Cookie 按主机名和短码路径隔离
+const CookieName = "link_access"
+
+var (
+	ErrInvalidPassword = shrErrors.NewBusinessError("invalid password")
+	ErrTooManyAttempts = shrErrors.NewBusinessError("too many password attempts")
+)
+
+const (
+	defaultCookieTTL     = time.Hour
+	defaultMaxAttempts   = 5
+	defaultAttemptWindow = 15 * time.Minute
+)
+
+// Service 密码保护链接的校验服务：密码在本地按 bcrypt 哈希校验，
+// 通过后签发绑定 (域名, 短码, 密码哈希) 的短期凭证，修改密码后旧凭证自动失效
+type Service struct {
+	cacheRepo     *cache.Repository
+	secret        []byte
+	cookieTTL     time.Duration
+	maxAttempts   int
+	attemptWindow time.Duration
+}
+
+func NewService(cacheRepo *cache.Repository, cfg *config.PasswordConfig) *Service {
+	s := &Service{
+		cacheRepo:     cacheRepo,
+		secret:        []byte(cfg.CookieSecret),
+		cookieTTL:     time.Duration(cfg.CookieTTL) * time.Second,
+		maxAttempts:   cfg.MaxAttempts,
+		attemptWindow: time.Duration(cfg.AttemptWindow) * time.Second,
+	}
+	if len(s.secret) == 0 {
+		log.Printf("⚠️ password.cookie_secret is empty, using a random secret")
+		s.secret = make([]byte, 32)
+		_, _ = rand.Read(s.secret)
+	}
+	if s.cookieTTL <= 0 {
+		s.cookieTTL = defaultCookieTTL
+	}
+	if s.maxAttempts <= 0 {
+		s.maxAttempts = defaultMaxAttempts
+	}
+	if s.attemptWindow <= 0 {
+		s.attemptWindow = defaultAttemptWindow
+	}
+	return s
+}
+
+// CookieTTL 访问凭证有效期
+func (s *Service) CookieTTL() time.Duration {
+	return s.cookieTTL
+}
+
+// Verify 校验密码，窗口内错误次数超限时直接拒绝，不再比对密码
+func (s *Service) Verify(ctx context.Context, ip string, link *model.Link, password string) error {
+	failures, err := s.cacheRepo.GetPasswordFailures(ctx, ip)
+	if err != nil {
+		return err
+	}
+	if failures >= int64(s.maxAttempts) {
+		return ErrTooManyAttempts
+	}
+	if bcrypt.CompareHashAndPassword([]byte(link.PasswordHash), []byte(password)) == nil {
+		return nil
+	}
+	failures, err = s.cacheRepo.IncrPasswordFailures(ctx, ip, s.attemptWindow)
+	if err != nil {
+		return err
+	}
+	if failures >= int64(s.maxAttempts) {
+		return ErrTooManyAttempts
+	}
+	return ErrInvalidPassword
+}
+
+// IssueToken 签发访问凭证，格式为 <过期时间戳>.<签名>
+func (s *Service) IssueToken(domain, shortCode string, link *model.Link) string {
+	exp := strconv.FormatInt(time.Now().Add(s.cookieTTL).Unix(), 10)
+	return exp + "." + s.sign(exp, domain, shortCode, link)
+}
+
+// ValidToken 检查访问凭证是否有效
+func (s *Service) ValidToken(token, domain, shortCode string, link *model.Link) bool {
+	exp, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+	expUnix, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil || time.Now().Unix() > expUnix {
+		return false
+	}
+	return hmac.Equal([]byte(sig), []byte(s.sign(exp, domain, shortCode, link)))
+}
+
+func (s *Service) sign(exp, domain, shortCode string, link *model.Link) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(strings.Join([]string{exp, hostname.Key(domain), shortCode, link.PasswordHash}, "|")))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,30 @@
+package password
+
+import (
+	"redirect-service/internal/config"
+	"redirect-service/internal/model"
+	"testing"
+)
+
+func TestTokenBinding(t *testing.T) {
+	s := NewService(nil, &config.PasswordConfig{CookieSecret: "secret", CookieTTL: 60})
+	link := &model.Link{URL: "https://example.com", PasswordHash: "hash-v1"}
+
+	token := s.IssueToken("go.brand.com", "abc", link)
+	if !s.ValidToken(token, "go.brand.com", "abc", link) {
+		t.Fatal("expected token to be valid")
+	}
+	// 凭证绑定域名、短码和密码哈希
+	if s.ValidToken(token, "", "abc", link) {
+		t.Error("token must not be valid on another domain")
+	}
+	if s.ValidToken(token, "go.brand.com", "abd", link) {
+		t.Error("token must not be valid for another short code")
+	}
+	if s.ValidToken(token, "go.brand.com", "abc", &model.Link{PasswordHash: "hash-v2"}) {
+		t.Error("token must be invalidated by a password change")
+	}
+	if s.ValidToken("1."+token[len(token)-43:], "go.brand.com", "abc", link) {
+		t.Error("expired token must be rejected")
+	}
+}
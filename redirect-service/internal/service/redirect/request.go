@@ -2,9 +2,17 @@ package redirect
 
 // RedirectRequest 重定向请求
 type RedirectRequest struct {
+	WorkspaceID uint64
+	Domain      string
 	OriginalURL string
 	IPAddress   string
 	UserAgent   string
-	Referer     string
-	Username    string
+	// Accept-Language 请求头，用于匹配语言条件
+	AcceptLanguage string
+	// 访问者标识，用于 A/B 测试分组的稳定分配
+	VisitorID string
+	// 命中的 A/B 测试分组，由 ResolveURL 填充
+	VariantID string
+	Referer   string
+	Username  string
 }
@@ -4,11 +4,19 @@ import (
 	"context"
 	"log"
 	"redirect-service/internal/client/grpc/generate"
+	"redirect-service/internal/model"
 	"redirect-service/internal/pkg/idgen"
 	"redirect-service/internal/producer"
 	"redirect-service/internal/repository/cache"
+	"redirect-service/internal/service/deeplink"
+	detector "redirect-service/internal/service/device_detector"
 	"redirect-service/internal/service/geoip"
+	"redirect-service/internal/service/rules"
+	"redirect-service/internal/service/variant"
 	"shared/constants"
+	shrErrors "shared/errors"
+	"shared/hostname"
+	"shared/ipaccess"
 	"shared/message"
 	"strconv"
 	"time"
@@ -19,6 +27,7 @@ type Service struct {
 	cacheRepo     *cache.Repository
 	kafkaProducer *producer.KafkaProducer
 	geoIPSvc      geoip.Service
+	detector      detector.DeviceDetector
 	generator     idgen.Generator
 }
 
@@ -27,6 +36,7 @@ func NewService(
 	cacheRepo *cache.Repository,
 	kafkaProducer *producer.KafkaProducer,
 	geoIpSvc geoip.Service,
+	deviceDetector detector.DeviceDetector,
 	generator idgen.Generator,
 ) *Service {
 	return &Service{
@@ -34,43 +44,288 @@ func NewService(
 		cacheRepo:     cacheRepo,
 		kafkaProducer: kafkaProducer,
 		geoIPSvc:      geoIpSvc,
+		detector:      deviceDetector,
 		generator:     generator,
 	}
 }
 
-func (s *Service) GetOriginalUrl(ctx context.Context, shortCode string) (string, error) {
-	// 从缓存获取长链接
-	longUrl, err := s.cacheRepo.GetOriginalURL(ctx, shortCode)
+// GetLink 按 (域名, 短码) 获取短链数据及其所属工作空间，domain 为空表示默认域名
+func (s *Service) GetLink(ctx context.Context, domain, shortCode string) (*model.Link, error) {
+	// 从缓存获取短链数据
+	link, err := s.cacheRepo.GetLink(ctx, domain, shortCode)
 	if err == nil {
-		return longUrl, nil
+		return link, nil
 	}
 	// 缓存未命中，回溯到generate-service服务
-	resp, err := s.genClient.GetOriginalURL(ctx, shortCode)
+	resp, err := s.genClient.GetOriginalURL(ctx, domain, shortCode)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	link = &model.Link{
+		WorkspaceID:   resp.WorkspaceId,
+		URL:           resp.OriginalUrl,
+		PasswordHash:  resp.PasswordHash,
+		MaxClicks:     resp.MaxClicks,
+		ForwardQuery:  resp.ForwardQuery,
+		QueryConflict: resp.QueryConflict,
+		ForwardPath:   resp.ForwardPath,
+		Template:      resp.Template,
+		RedirectType:  resp.RedirectType,
+		Flagged:       resp.Flagged,
+		SignedOnly:    resp.SignedOnly,
+	}
+	for _, v := range resp.Variants {
+		link.Variants = append(link.Variants, message.LinkVariant{
+			ID:          v.Id,
+			Destination: v.Destination,
+			Weight:      int(v.Weight),
+		})
+	}
+	for _, rule := range resp.Rules {
+		link.Rules = append(link.Rules, message.RedirectRule{
+			Countries:   rule.Countries,
+			Devices:     rule.Devices,
+			OS:          rule.Os,
+			Languages:   rule.Languages,
+			TimeStart:   rule.TimeStart,
+			TimeEnd:     rule.TimeEnd,
+			Timezone:    rule.Timezone,
+			Destination: rule.Destination,
+		})
+	}
+	if dl := resp.DeepLink; dl != nil {
+		link.DeepLink = &message.DeepLink{
+			IOSURL:          dl.IosUrl,
+			IOSStoreURL:     dl.IosStoreUrl,
+			AndroidURL:      dl.AndroidUrl,
+			AndroidPackage:  dl.AndroidPackage,
+			AndroidStoreURL: dl.AndroidStoreUrl,
+		}
+	}
+	if a := resp.IpAccess; a != nil {
+		link.IPAccess = &message.IPAccess{Allow: a.Allow, Deny: a.Deny}
+	}
+	if a := resp.WorkspaceIpAccess; a != nil {
+		link.WorkspaceIPAccess = &message.IPAccess{Allow: a.Allow, Deny: a.Deny}
+	}
+	if og := resp.OpenGraph; og != nil {
+		link.OpenGraph = &message.OpenGraph{
+			Title:       og.Title,
+			Description: og.Description,
+			Image:       og.Image,
+		}
+	}
+	for _, px := range resp.Pixels {
+		link.Pixels = append(link.Pixels, message.RetargetingPixel{
+			Provider:   px.Provider,
+			TrackingID: px.TrackingId,
+		})
+	}
+	if resp.ActivateTime != nil {
+		t := resp.ActivateTime.AsTime()
+		link.ActivateAt = &t
 	}
-	// 加入缓存
-	var expiredAt *time.Time
 	if resp.ExpireTime != nil {
 		t := resp.ExpireTime.AsTime()
-		expiredAt = &t
+		link.ExpiresAt = &t
 	}
+	// 加入缓存
 	go func() {
-		if err = s.cacheRepo.SetShortURL(ctx, shortCode, resp.OriginalUrl, expiredAt); err != nil {
+		if err := s.cacheRepo.SetLink(context.Background(), domain, shortCode, link); err != nil {
 			log.Printf("failed to cache short url: %v", err)
 		}
 	}()
-	return resp.OriginalUrl, nil
+	return link, nil
+}
+
+// GetPreview 组合链接元数据和缓存中的目标地址，只有可访问、无需密码、不要求签名且不限制IP的链接返回目标地址
+func (s *Service) GetPreview(ctx context.Context, domain, shortCode string) (*model.LinkPreview, error) {
+	meta, err := s.genClient.GetLinkMetadata(ctx, domain, shortCode)
+	if err != nil {
+		return nil, err
+	}
+	preview := &model.LinkPreview{
+		Title:  meta.Title,
+		Status: meta.Status,
+	}
+	if meta.CreateTime != nil {
+		preview.CreatedAt = meta.CreateTime.AsTime()
+	}
+	if meta.ExpireTime != nil {
+		t := meta.ExpireTime.AsTime()
+		preview.ExpiresAt = &t
+	}
+	if meta.Status != string(message.LinkStatusActive) && meta.Status != string(message.LinkStatusFlagged) {
+		return preview, nil
+	}
+	link, err := s.GetLink(ctx, domain, shortCode)
+	if err != nil {
+		return nil, err
+	}
+	preview.Protected = link.Protected()
+	preview.SignedOnly = link.SignedOnly
+	if !preview.Protected && !preview.SignedOnly && !link.IPRestricted() {
+		preview.Destination = link.URL
+	}
+	return preview, nil
+}
+
+// ResolveURL 选择跳转目标：先按顺序匹配条件跳转规则，未命中时按 A/B 测试分组分流，
+// 均未设置时返回默认长链接。命中的分组记录在 req.VariantID
+func (s *Service) ResolveURL(link *model.Link, shortCode string, req *RedirectRequest) string {
+	if destination, ok := s.matchRules(link, req); ok {
+		return destination
+	}
+	if len(link.Variants) > 0 {
+		visitor := req.VisitorID
+		if visitor == "" {
+			visitor = req.IPAddress
+		}
+		if v := variant.Pick(link.Variants, hostname.Key(req.Domain)+":"+shortCode, visitor); v != nil {
+			req.VariantID = v.ID
+			return v.Destination
+		}
+	}
+	return link.URL
+}
+
+// ResolveDeepLink 为 iOS 和 Android 访问者选择打开 App 的方式，webURL 为未安装 App 且未配置
+// 应用商店时的跳转地址。其他平台、爬虫或未配置深度链接时返回 nil
+func (s *Service) ResolveDeepLink(link *model.Link, webURL string, req *RedirectRequest) *deeplink.Target {
+	if link.DeepLink == nil {
+		return nil
+	}
+	info := s.detector.Parse(req.UserAgent)
+	if info.DeviceType == "bot" {
+		return nil
+	}
+	return deeplink.Resolve(link.DeepLink, info.OS, webURL)
+}
+
+// ResolvePixels 返回跳转前需要触发的重定向像素，爬虫不执行脚本，直接跳转
+func (s *Service) ResolvePixels(link *model.Link, req *RedirectRequest) []message.RetargetingPixel {
+	if len(link.Pixels) == 0 {
+		return nil
+	}
+	if s.detector.Parse(req.UserAgent).DeviceType == "bot" {
+		return nil
+	}
+	return link.Pixels
+}
+
+// IsLinkPreview 是否为社交平台的链接预览爬虫，此类请求返回分享卡片而不是跳转
+func (s *Service) IsLinkPreview(req *RedirectRequest) bool {
+	return s.detector.Parse(req.UserAgent).LinkPreview
+}
+
+// IsBot 是否为爬虫访问，爬虫不消耗访问次数，点击事件按爬虫流量记录
+func (s *Service) IsBot(req *RedirectRequest) bool {
+	return s.detector.Parse(req.UserAgent).DeviceType == "bot"
+}
+
+// 按顺序匹配条件跳转规则
+func (s *Service) matchRules(link *model.Link, req *RedirectRequest) (string, bool) {
+	if len(link.Rules) == 0 {
+		return "", false
+	}
+	visitor := &rules.Visitor{
+		Languages: rules.ParseAcceptLanguage(req.AcceptLanguage),
+		Time:      time.Now(),
+	}
+	needs := rules.Requirements(link.Rules)
+	if needs.Country {
+		if geo, err := s.geoIPSvc.GetGeoInfo(req.IPAddress); err == nil {
+			visitor.Country = geo.Country
+		}
+	}
+	if needs.Device {
+		info := s.detector.Parse(req.UserAgent)
+		visitor.DeviceType = info.DeviceType
+		visitor.OS = info.OS
+	}
+	return rules.Match(link.Rules, visitor)
+}
+
+// ConsumeClick 消耗限次链接的一次访问次数，已达上限时返回 ErrLinkExpired；
+// 恰好用尽时通知 generate-service 将链接置为过期
+func (s *Service) ConsumeClick(ctx context.Context, domain, shortCode string, link *model.Link) error {
+	n, err := s.cacheRepo.ConsumeClick(ctx, link.WorkspaceID, domain, shortCode, link.MaxClicks)
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return shrErrors.ErrLinkExpired
+	}
+	if n == link.MaxClicks {
+		msg := &message.LinkExhaustedMessage{
+			BaseMessage: message.BaseMessage{
+				EventType: "link_exhausted",
+				Timestamp: time.Now(),
+				Source:    "redirect-service",
+			},
+			WorkspaceID: link.WorkspaceID,
+			Domain:      domain,
+			ShortCode:   shortCode,
+			Clicks:      n,
+		}
+		if eventID, err := s.generator.NextId(); err == nil {
+			msg.EventID = strconv.FormatUint(eventID, 10)
+		}
+		if err := s.kafkaProducer.SendMessage(constants.TopicLinkExhausted, msg); err != nil {
+			log.Printf("failed to send link exhausted message: %v", err)
+		}
+	}
+	return nil
+}
+
+// RecordAccessDenied 发出访问被IP访问控制拒绝的安全审计事件，不计入点击量
+func (s *Service) RecordAccessDenied(ctx context.Context, shortCode string, req *RedirectRequest, decision ipaccess.Decision) error {
+	msg := &message.AccessDeniedMessage{
+		BaseMessage: message.BaseMessage{
+			EventType: "access_denied",
+			Timestamp: time.Now(),
+			Source:    "redirect-service",
+		},
+		WorkspaceID: req.WorkspaceID,
+		Domain:      req.Domain,
+		ShortCode:   shortCode,
+		IP:          req.IPAddress,
+		UserAgent:   req.UserAgent,
+		Referer:     req.Referer,
+		Scope:       decision.Scope,
+		Reason:      decision.Reason,
+		Rule:        decision.Rule,
+	}
+	if eventID, err := s.generator.NextId(); err == nil {
+		msg.EventID = strconv.FormatUint(eventID, 10)
+	}
+	return s.kafkaProducer.SendMessage(constants.TopicAccessDenied, msg)
 }
 
 func (s *Service) RecordClick(ctx context.Context, shortCode string, req *RedirectRequest) error {
+	return s.recordEvent(message.EventTypeClick, shortCode, req)
+}
+
+// RecordWarningContinue 记录访问者在风险提示页选择继续访问，与点击事件分开统计
+func (s *Service) RecordWarningContinue(ctx context.Context, shortCode string, req *RedirectRequest) error {
+	return s.recordEvent(message.EventTypeWarningContinue, shortCode, req)
+}
+
+// RecordUnfurl 记录社交平台爬虫抓取分享卡片，按爬虫流量记录，不计入点击量
+func (s *Service) RecordUnfurl(ctx context.Context, shortCode string, req *RedirectRequest) error {
+	return s.recordEvent(message.EventTypeLinkUnfurl, shortCode, req)
+}
+
+func (s *Service) recordEvent(eventType, shortCode string, req *RedirectRequest) error {
 	now := time.Now()
 	msg := &message.ClickEventMessage{
 		BaseMessage: message.BaseMessage{
-			EventType: "click_event",
+			EventType: eventType,
 			Timestamp: now,
 			Source:    "redirect-service",
 		},
+		WorkspaceID: req.WorkspaceID,
+		Domain:      req.Domain,
 		ShortCode:   shortCode,
 		OriginalURL: req.OriginalURL,
 		IP:          req.IPAddress,
@@ -78,6 +333,8 @@ func (s *Service) RecordClick(ctx context.Context, shortCode string, req *Redire
 		Referer:     req.Referer,
 		ClickTime:   now,
 		ClickBy:     req.Username,
+		VariantID:   req.VariantID,
+		Bot:         s.IsBot(req),
 	}
 	eventId, err := s.generator.NextId()
 	if err == nil {
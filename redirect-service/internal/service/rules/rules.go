@@ -0,0 +1,165 @@
+package rules
+
+import (
+	"shared/message"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Visitor 访问者属性，按规则需要延迟填充
+type Visitor struct {
+	Country    string   // ISO 3166-1 alpha-2 国家代码
+	DeviceType string   // mobile / tablet / desktop / bot / other
+	OS         string   // ios / android / windows / macos / linux / other
+	Languages  []string // 按偏好排序的语言标签（小写）
+	Time       time.Time
+}
+
+// Needs 规则用到的访问者属性，避免为不需要的条件查询 GeoIP 或解析 UA
+type Needs struct {
+	Country bool
+	Device  bool
+}
+
+// Requirements 汇总规则集需要的访问者属性
+func Requirements(rules []message.RedirectRule) Needs {
+	var n Needs
+	for i := range rules {
+		n.Country = n.Country || len(rules[i].Countries) > 0
+		n.Device = n.Device || len(rules[i].Devices) > 0 || len(rules[i].OS) > 0
+	}
+	return n
+}
+
+// Match 按顺序返回第一条命中规则的目标地址
+func Match(rules []message.RedirectRule, v *Visitor) (string, bool) {
+	for i := range rules {
+		if matches(&rules[i], v) {
+			return rules[i].Destination, true
+		}
+	}
+	return "", false
+}
+
+func matches(rule *message.RedirectRule, v *Visitor) bool {
+	if len(rule.Countries) > 0 && !containsFold(rule.Countries, v.Country) {
+		return false
+	}
+	if len(rule.Devices) > 0 && !containsFold(rule.Devices, v.DeviceType) {
+		return false
+	}
+	if len(rule.OS) > 0 && !containsFold(rule.OS, v.OS) {
+		return false
+	}
+	if len(rule.Languages) > 0 && !matchLanguage(rule.Languages, v.Languages) {
+		return false
+	}
+	if rule.TimeStart != "" && !inWindow(rule, v.Time) {
+		return false
+	}
+	return true
+}
+
+func containsFold(values []string, target string) bool {
+	if target == "" {
+		return false
+	}
+	return slices.ContainsFunc(values, func(v string) bool {
+		return strings.EqualFold(v, target)
+	})
+}
+
+// 只匹配访问者最偏好的语言：规则 de 匹配 de、de-AT，规则 en-US 只匹配 en-US
+func matchLanguage(ruleLangs, visitorLangs []string) bool {
+	if len(visitorLangs) == 0 {
+		return false
+	}
+	preferred := visitorLangs[0]
+	for _, l := range ruleLangs {
+		l = strings.ToLower(l)
+		if preferred == l || strings.HasPrefix(preferred, l+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+// 判断时间是否落在每日时段 [start, end) 内，start 晚于 end 时表示跨零点
+func inWindow(rule *message.RedirectRule, t time.Time) bool {
+	start, ok1 := parseClock(rule.TimeStart)
+	end, ok2 := parseClock(rule.TimeEnd)
+	if !ok1 || !ok2 {
+		return false
+	}
+	t = t.In(location(rule.Timezone))
+	now := t.Hour()*60 + t.Minute()
+	if start <= end {
+		return now >= start && now < end
+	}
+	return now >= start || now < end
+}
+
+// 解析 HH:MM 为当天的分钟数
+func parseClock(s string) (int, bool) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
+
+var locations sync.Map
+
+// 加载并缓存时区，无法识别时使用 UTC
+func location(name string) *time.Location {
+	if name == "" {
+		return time.UTC
+	}
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location)
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		loc = time.UTC
+	}
+	locations.Store(name, loc)
+	return loc
+}
+
+// ParseAcceptLanguage 解析 Accept-Language 请求头，按权重从高到低返回小写语言标签
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+	var langs []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+		langs = append(langs, weighted{tag: tag, q: q})
+	}
+	sort.SliceStable(langs, func(i, j int) bool { return langs[i].q > langs[j].q })
+	tags := make([]string, len(langs))
+	for i, l := range langs {
+		tags[i] = l.tag
+	}
+	return tags
+}
@@ -0,0 +1,90 @@
+package rules
+
+import (
+	"reflect"
+	"shared/message"
+	"testing"
+	"time"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	got := ParseAcceptLanguage("en-US;q=0.8, de-AT, fr;q=0, *;q=0.1, en;q=0.7")
+	want := []string{"de-at", "en-us", "en"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ParseAcceptLanguage = %v, want %v", got, want)
+	}
+	if got := ParseAcceptLanguage(""); len(got) != 0 {
+		t.Fatalf("empty header = %v, want none", got)
+	}
+}
+
+func TestMatchFirstRuleWins(t *testing.T) {
+	rules := []message.RedirectRule{
+		{OS: []string{"ios"}, Destination: "https://apps.apple.com/app"},
+		{Countries: []string{"DE"}, Destination: "https://example.de"},
+		{Languages: []string{"de"}, Destination: "https://example.com/de"},
+	}
+	cases := []struct {
+		name    string
+		visitor Visitor
+		want    string
+		ok      bool
+	}{
+		{"ios in germany", Visitor{Country: "DE", OS: "ios"}, "https://apps.apple.com/app", true},
+		{"android in germany", Visitor{Country: "DE", OS: "android"}, "https://example.de", true},
+		{"austrian speaker abroad", Visitor{Country: "US", Languages: []string{"de-at", "en"}}, "https://example.com/de", true},
+		{"secondary language ignored", Visitor{Country: "US", Languages: []string{"en", "de"}}, "", false},
+		{"unknown visitor", Visitor{}, "", false},
+	}
+	for _, tc := range cases {
+		got, ok := Match(rules, &tc.visitor)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("%s: Match = (%q, %v), want (%q, %v)", tc.name, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestMatchAllConditions(t *testing.T) {
+	rules := []message.RedirectRule{
+		{Countries: []string{"DE"}, Devices: []string{"mobile"}, Destination: "https://m.example.de"},
+	}
+	if _, ok := Match(rules, &Visitor{Country: "DE", DeviceType: "desktop"}); ok {
+		t.Fatal("rule matched with only one of two conditions satisfied")
+	}
+	if got, _ := Match(rules, &Visitor{Country: "de", DeviceType: "mobile"}); got != "https://m.example.de" {
+		t.Fatalf("Match = %q, want mobile destination", got)
+	}
+}
+
+func TestMatchTimeWindow(t *testing.T) {
+	night := message.RedirectRule{TimeStart: "22:00", TimeEnd: "06:00", Timezone: "Europe/Berlin", Destination: "https://night"}
+	day := message.RedirectRule{TimeStart: "09:00", TimeEnd: "17:00", Destination: "https://day"}
+	rules := []message.RedirectRule{night, day}
+
+	cases := []struct {
+		at   time.Time
+		want string
+	}{
+		// 23:30 柏林时间（UTC+1）
+		{time.Date(2026, 1, 10, 22, 30, 0, 0, time.UTC), "https://night"},
+		// 05:59 柏林时间
+		{time.Date(2026, 1, 10, 4, 59, 0, 0, time.UTC), "https://night"},
+		// 06:00 柏林时间，夜间时段已结束，UTC 05:00 不在白天时段
+		{time.Date(2026, 1, 10, 5, 0, 0, 0, time.UTC), ""},
+		{time.Date(2026, 1, 10, 16, 59, 0, 0, time.UTC), "https://day"},
+		{time.Date(2026, 1, 10, 17, 0, 0, 0, time.UTC), ""},
+	}
+	for _, tc := range cases {
+		got, _ := Match(rules, &Visitor{Time: tc.at})
+		if got != tc.want {
+			t.Errorf("Match at %s = %q, want %q", tc.at, got, tc.want)
+		}
+	}
+}
+
+func TestRequirements(t *testing.T) {
+	n := Requirements([]message.RedirectRule{{Languages: []string{"de"}}, {OS: []string{"ios"}}})
+	if n.Country || !n.Device {
+		t.Fatalf("Requirements = %+v, want device only", n)
+	}
+}
@@ -0,0 +1,106 @@
+package unfurl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"shared/message"
+	"syscall"
+	"time"
+)
+
+const maxRedirects = 5
+
+var (
+	ErrPrivateAddress  = errors.New("unfurl: destination resolves to a non-public address")
+	ErrUnsupportedPage = errors.New("unfurl: destination is not an HTML page")
+)
+
+// Fetcher 抓取目标页面并解析分享卡片元数据。只连接公网地址，
+// 避免通过短链让服务端访问内网（包括跳转后的地址）
+type Fetcher struct {
+	client      *http.Client
+	maxBodySize int64
+	userAgent   string
+}
+
+func NewFetcher(timeout time.Duration, maxBodySize int64, userAgent string) *Fetcher {
+	dialer := &net.Dialer{Timeout: timeout, Control: denyNonPublic}
+	transport := &http.Transport{
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   timeout,
+		ResponseHeaderTimeout: timeout,
+		MaxIdleConns:          20,
+		IdleConnTimeout:       90 * time.Second,
+	}
+	return &Fetcher{
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   timeout,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= maxRedirects {
+					return fmt.Errorf("unfurl: stopped after %d redirects", maxRedirects)
+				}
+				if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+					return fmt.Errorf("unfurl: unsupported redirect scheme %q", req.URL.Scheme)
+				}
+				return nil
+			},
+		},
+		maxBodySize: maxBodySize,
+		userAgent:   userAgent,
+	}
+}
+
+// Fetch 抓取页面，只读取前 maxBodySize 字节
+func (f *Fetcher) Fetch(ctx context.Context, pageURL string) (*message.OpenGraph, error) {
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, ErrUnsupportedPage
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	if f.userAgent != "" {
+		req.Header.Set("User-Agent", f.userAgent)
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unfurl: unexpected status %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "" {
+		mediaType, _, _ := mime.ParseMediaType(ct)
+		if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+			return nil, ErrUnsupportedPage
+		}
+	}
+	// 跳转后的地址作为相对图片地址的基准
+	return Parse(io.LimitReader(resp.Body, f.maxBodySize), resp.Request.URL), nil
+}
+
+// 在建立连接前检查解析后的IP，拒绝回环、内网、链路本地等非公网地址
+func denyNonPublic(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return ErrPrivateAddress
+	}
+	return nil
+}
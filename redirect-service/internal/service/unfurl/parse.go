@@ -0,0 +1,149 @@
+package unfurl
+
+import (
+	"io"
+	"net/url"
+	"shared/message"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	maxTitleLen       = 200
+	maxDescriptionLen = 500
+)
+
+// 元数据来源的优先级，数值越小越优先
+const (
+	sourceOpenGraph = iota // og:*
+	sourceTwitter          // twitter:*
+	sourceDocument         // <title> 和 meta description
+	sourceCount
+)
+
+// Parse 从页面的 <head> 中提取分享卡片元数据：优先使用 og:* 标签，
+// 缺失时依次回退到 twitter:* 标签、<title> 和 meta description。
+// 图片的相对地址按 base 解析，非 http(s) 地址被忽略
+func Parse(r io.Reader, base *url.URL) *message.OpenGraph {
+	var found [sourceCount]message.OpenGraph
+	z := html.NewTokenizer(r)
+	inTitle := false
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			return pick(found[:], base)
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := z.TagName()
+			switch string(name) {
+			case "meta":
+				if hasAttr {
+					collectMeta(z, &found)
+				}
+			case "title":
+				inTitle = tt == html.StartTagToken
+			case "body":
+				return pick(found[:], base)
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			switch string(name) {
+			case "title":
+				inTitle = false
+			case "head":
+				return pick(found[:], base)
+			}
+		case html.TextToken:
+			if inTitle && found[sourceDocument].Title == "" {
+				found[sourceDocument].Title = string(z.Text())
+			}
+		}
+	}
+}
+
+// 读取 <meta property|name=... content=...>，同一来源重复出现时保留第一个
+func collectMeta(z *html.Tokenizer, found *[sourceCount]message.OpenGraph) {
+	var key, content string
+	for {
+		k, v, more := z.TagAttr()
+		switch string(k) {
+		case "property", "name":
+			if key == "" {
+				key = strings.ToLower(strings.TrimSpace(string(v)))
+			}
+		case "content":
+			content = string(v)
+		}
+		if !more {
+			break
+		}
+	}
+	var field *string
+	switch key {
+	case "og:title":
+		field = &found[sourceOpenGraph].Title
+	case "og:description":
+		field = &found[sourceOpenGraph].Description
+	case "og:image", "og:image:url", "og:image:secure_url":
+		field = &found[sourceOpenGraph].Image
+	case "twitter:title":
+		field = &found[sourceTwitter].Title
+	case "twitter:description":
+		field = &found[sourceTwitter].Description
+	case "twitter:image", "twitter:image:src":
+		field = &found[sourceTwitter].Image
+	case "description":
+		field = &found[sourceDocument].Description
+	default:
+		return
+	}
+	if *field == "" {
+		*field = content
+	}
+}
+
+// 按来源优先级选取每个字段，并规范化空白和长度
+func pick(found []message.OpenGraph, base *url.URL) *message.OpenGraph {
+	og := &message.OpenGraph{}
+	for _, src := range found {
+		if og.Title == "" {
+			og.Title = clean(src.Title, maxTitleLen)
+		}
+		if og.Description == "" {
+			og.Description = clean(src.Description, maxDescriptionLen)
+		}
+		if og.Image == "" {
+			og.Image = resolveImage(src.Image, base)
+		}
+	}
+	return og
+}
+
+// 合并连续空白，超长时按字符截断
+func clean(s string, maxLen int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if utf8.RuneCountInString(s) <= maxLen {
+		return s
+	}
+	return string([]rune(s)[:maxLen-1]) + "…"
+}
+
+func resolveImage(raw string, base *url.URL) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	if base != nil {
+		u = base.ResolveReference(u)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return ""
+	}
+	return u.String()
+}
@@ -0,0 +1,89 @@
+package unfurl
+
+import (
+	"context"
+	"log"
+	"redirect-service/internal/config"
+	"redirect-service/internal/repository/cache"
+	"shared/message"
+	"time"
+)
+
+const (
+	defaultTimeout     = 3 * time.Second
+	defaultMaxBodySize = 512 << 10
+	defaultCacheTTL    = 24 * time.Hour
+	// 抓取失败时缓存空结果的时长，期间只使用覆盖值
+	failureCacheTTL = 10 * time.Minute
+)
+
+// Service 分享卡片服务：链接的覆盖值优先，未设置的字段使用从目标页面抓取的元数据，
+// 抓取结果按目标地址缓存
+type Service struct {
+	cacheRepo *cache.Repository
+	fetcher   *Fetcher
+	cacheTTL  time.Duration
+}
+
+func NewService(cacheRepo *cache.Repository, cfg *config.UnfurlConfig) *Service {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	maxBodySize := cfg.MaxBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = defaultMaxBodySize
+	}
+	cacheTTL := time.Duration(cfg.CacheTTL) * time.Second
+	if cacheTTL <= 0 {
+		cacheTTL = defaultCacheTTL
+	}
+	return &Service{
+		cacheRepo: cacheRepo,
+		fetcher:   NewFetcher(timeout, maxBodySize, cfg.UserAgent),
+		cacheTTL:  cacheTTL,
+	}
+}
+
+// Resolve 返回链接的分享卡片，覆盖值已包含全部字段时不抓取目标页面
+func (s *Service) Resolve(ctx context.Context, override *message.OpenGraph, destination string) *message.OpenGraph {
+	og := &message.OpenGraph{}
+	if override != nil {
+		*og = *override
+	}
+	if og.Title != "" && og.Description != "" && og.Image != "" {
+		return og
+	}
+	fetched := s.lookup(ctx, destination)
+	if og.Title == "" {
+		og.Title = fetched.Title
+	}
+	if og.Description == "" {
+		og.Description = fetched.Description
+	}
+	if og.Image == "" {
+		og.Image = fetched.Image
+	}
+	return og
+}
+
+// 优先读取缓存，未命中时抓取目标页面并写回缓存
+func (s *Service) lookup(ctx context.Context, destination string) *message.OpenGraph {
+	if cached, err := s.cacheRepo.GetOpenGraph(ctx, destination); err != nil {
+		log.Printf("failed to get cached open graph: %v", err)
+	} else if cached != nil {
+		return cached
+	}
+	ttl := s.cacheTTL
+	fetched, err := s.fetcher.Fetch(ctx, destination)
+	if err != nil {
+		log.Printf("failed to unfurl destination: %v", err)
+		fetched, ttl = &message.OpenGraph{}, failureCacheTTL
+	}
+	go func() {
+		if err := s.cacheRepo.SetOpenGraph(context.Background(), destination, fetched, ttl); err != nil {
+			log.Printf("failed to cache open graph: %v", err)
+		}
+	}()
+	return fetched
+}
@@ -0,0 +1,65 @@
+package unfurl
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"shared/message"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	base, _ := url.Parse("https://example.com/blog/post")
+	tests := []struct {
+		name string
+		page string
+		want message.OpenGraph
+	}{
+		{
+			name: "open graph",
+			page: `<html><head><title>Fallback</title>
+<meta property="og:title" content="Post &amp; more">
+<meta property="og:description" content="  A   short
+description ">
+<meta property="og:image" content="/img/cover.png">
+</head><body><meta property="og:title" content="ignored"></body></html>`,
+			want: message.OpenGraph{Title: "Post & more", Description: "A short description", Image: "https://example.com/img/cover.png"},
+		},
+		{
+			name: "twitter then document",
+			page: `<head><meta name="twitter:title" content="Tweet title"><title>Doc title</title>
+<meta name="description" content="Doc description"><meta name="twitter:image" content="javascript:alert(1)"></head>`,
+			want: message.OpenGraph{Title: "Tweet title", Description: "Doc description"},
+		},
+		{
+			name: "document only",
+			page: `<!DOCTYPE html><html><head><title> Plain page </title></head></html>`,
+			want: message.OpenGraph{Title: "Plain page"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Parse(strings.NewReader(tt.page), base)
+			if *got != tt.want {
+				t.Fatalf("Parse() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchRejectsLoopback(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<title>internal</title>`))
+	}))
+	defer srv.Close()
+
+	_, err := NewFetcher(time.Second, 1024, "").Fetch(context.Background(), srv.URL)
+	if !errors.Is(err, ErrPrivateAddress) {
+		t.Fatalf("Fetch() error = %v, want %v", err, ErrPrivateAddress)
+	}
+}
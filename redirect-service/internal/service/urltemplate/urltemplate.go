@@ -0,0 +1,78 @@
+package urltemplate
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidExpansion 展开后的地址无效或指向模板以外的站点
+var ErrInvalidExpansion = errors.New("invalid template expansion")
+
+// 占位符：{1}-{9} 取短码之后的第N段路径，{name} 取同名查询参数
+var placeholderPattern = regexp.MustCompile(`\{([1-9]|[A-Za-z_][A-Za-z0-9_]{0,31})\}`)
+
+// Expand 用路径段和查询参数填充目标地址模板。路径中的值按路径段转义（/ 编码为 %2F），
+// 查询参数中的值按查询参数转义；缺失的值填充为空。展开结果必须是协议和主机与模板一致的
+// http(s) 地址，防止借助占位符跳转到其他站点
+func Expand(tmpl string, segments []string, query url.Values) (string, error) {
+	base, err := url.Parse(placeholderPattern.ReplaceAllString(tmpl, ""))
+	if err != nil {
+		return "", ErrInvalidExpansion
+	}
+	path, rest, hasQuery := strings.Cut(tmpl, "?")
+	expanded := fill(path, segments, query, escapePathSegment)
+	if hasQuery {
+		expanded += "?" + fill(rest, segments, query, url.QueryEscape)
+	}
+	u, err := url.Parse(expanded)
+	if err != nil {
+		return "", ErrInvalidExpansion
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Scheme != base.Scheme ||
+		u.Host != base.Host || u.User.String() != base.User.String() {
+		return "", ErrInvalidExpansion
+	}
+	return u.String(), nil
+}
+
+func fill(s string, segments []string, query url.Values, escape func(string) string) string {
+	return placeholderPattern.ReplaceAllStringFunc(s, func(m string) string {
+		name := m[1 : len(m)-1]
+		if i, err := strconv.Atoi(name); err == nil {
+			if i <= len(segments) {
+				return escape(segments[i-1])
+			}
+			return ""
+		}
+		return escape(query.Get(name))
+	})
+}
+
+// 路径段转义，. 和 .. 会被浏览器解析为相对路径，一并编码
+func escapePathSegment(s string) string {
+	switch s {
+	case ".":
+		return "%2E"
+	case "..":
+		return "%2E%2E"
+	}
+	return url.PathEscape(s)
+}
+
+// Segments 将转义后的路径后缀（如 /PROJ-1/comments）拆分为解码后的路径段
+func Segments(suffix string) []string {
+	var segments []string
+	for _, s := range strings.Split(strings.Trim(suffix, "/"), "/") {
+		if s == "" {
+			continue
+		}
+		if unescaped, err := url.PathUnescape(s); err == nil {
+			s = unescaped
+		}
+		segments = append(segments, s)
+	}
+	return segments
+}
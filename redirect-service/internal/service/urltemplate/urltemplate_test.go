@@ -0,0 +1,43 @@
+package urltemplate
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestExpand(t *testing.T) {
+	tests := []struct {
+		name     string
+		tmpl     string
+		segments []string
+		query    string
+		want     string
+	}{
+		{name: "path segment", tmpl: "https://jira.example.com/browse/{1}", segments: []string{"PROJ-1"}, want: "https://jira.example.com/browse/PROJ-1"},
+		{name: "query param", tmpl: "https://search.example.com?q={q}", query: "q=go+lang", want: "https://search.example.com?q=go+lang"},
+		{name: "missing value", tmpl: "https://example.com/{1}/{2}", segments: []string{"a"}, want: "https://example.com/a/"},
+		{name: "slash is escaped", tmpl: "https://example.com/{1}", segments: []string{"/evil.com"}, want: "https://example.com/%2Fevil.com"},
+		{name: "dot segment", tmpl: "https://example.com/docs/{1}", segments: []string{".."}, want: "https://example.com/docs/%2E%2E"},
+		{name: "query injection", tmpl: "https://example.com/?q={q}&x=1", query: "q=a%26x%3D2", want: "https://example.com/?q=a%26x%3D2&x=1"},
+		{name: "segment in query", tmpl: "https://example.com/search?id={1}", segments: []string{"a b"}, want: "https://example.com/search?id=a+b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, _ := url.ParseQuery(tt.query)
+			got, err := Expand(tt.tmpl, tt.segments, query)
+			if err != nil {
+				t.Fatalf("Expand() error = %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("Expand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSegments(t *testing.T) {
+	got := Segments("/PROJ-1/a%20b/")
+	if len(got) != 2 || got[0] != "PROJ-1" || got[1] != "a b" {
+		t.Fatalf("Segments() = %q", got)
+	}
+}
@@ -0,0 +1,34 @@
+package variant
+
+import (
+	"hash/fnv"
+	"shared/message"
+)
+
+// Pick 按访问者标识稳定地选择 A/B 测试分组，同一链接下同一访问者始终落在同一分组
+func Pick(variants []message.LinkVariant, linkKey, visitorKey string) *message.LinkVariant {
+	total := 0
+	for _, v := range variants {
+		if v.Weight > 0 {
+			total += v.Weight
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+	h := fnv.New64a()
+	h.Write([]byte(linkKey))
+	h.Write([]byte{0})
+	h.Write([]byte(visitorKey))
+	n := int(h.Sum64() % uint64(total))
+	for i := range variants {
+		if variants[i].Weight <= 0 {
+			continue
+		}
+		if n < variants[i].Weight {
+			return &variants[i]
+		}
+		n -= variants[i].Weight
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package variant
+
+import (
+	"math"
+	"shared/message"
+	"strconv"
+	"testing"
+)
+
+var variants = []message.LinkVariant{
+	{ID: "a", Destination: "https://example.com/a", Weight: 70},
+	{ID: "b", Destination: "https://example.com/b", Weight: 30},
+}
+
+func TestPickSticky(t *testing.T) {
+	first := Pick(variants, "example.com:abc", "visitor-1")
+	for i := 0; i < 10; i++ {
+		if got := Pick(variants, "example.com:abc", "visitor-1"); got.ID != first.ID {
+			t.Fatalf("repeat pick = %s, want %s", got.ID, first.ID)
+		}
+	}
+}
+
+func TestPickFollowsWeights(t *testing.T) {
+	const n = 20000
+	counts := map[string]int{}
+	for i := 0; i < n; i++ {
+		counts[Pick(variants, "example.com:abc", strconv.Itoa(i)).ID]++
+	}
+	share := float64(counts["a"]) / n
+	if math.Abs(share-0.7) > 0.02 {
+		t.Fatalf("variant a share = %.3f, want about 0.70", share)
+	}
+}
+
+func TestPickNoVariants(t *testing.T) {
+	if got := Pick(nil, "k", "v"); got != nil {
+		t.Fatalf("Pick(nil) = %+v, want nil", got)
+	}
+}
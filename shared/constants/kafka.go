@@ -9,6 +9,12 @@ const (
 	// 记录点击事件
 	TopicRecordClickEvent = "short-link-click-events"
 
+	// 访问次数达到上限，generate-service 据此将链接置为过期
+	TopicLinkExhausted = "short-link-exhausted"
+
+	// 访问被IP访问控制拒绝，供安全审计消费
+	TopicAccessDenied = "short-link-access-denied"
+
 	// statistics-service 消费者组
 	StatsGroupDetail = "record-detail"
 	StatsGroupTotal  = "record-total"
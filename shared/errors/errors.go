@@ -11,6 +11,7 @@ var (
 	ErrShortCodeExists  = NewBusinessError("short code already exists")
 	ErrInvalidShortCode = NewBusinessError("invalid short code")
 	ErrBreakerOpen      = NewBusinessError("circuit breaker is open")
+	ErrNamespaceClash   = NewBusinessError("short code belongs to another workspace")
 )
 
 type BusinessError struct {
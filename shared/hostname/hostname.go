@@ -0,0 +1,46 @@
+package hostname
+
+import (
+	"net"
+	"regexp"
+	"strings"
+)
+
+// DefaultKey 默认域名（base_url）在缓存键等场景中的占位标识
+const DefaultKey = "default"
+
+var labelRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// Normalize 规范化主机名：去掉端口和末尾的点，转为小写
+func Normalize(host string) string {
+	host = strings.TrimSpace(host)
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.ToLower(strings.TrimSuffix(host, "."))
+}
+
+// IsValid 检查是否为可注册的自定义域名（至少两级，且不是IP地址）
+func IsValid(host string) bool {
+	if len(host) == 0 || len(host) > 253 || net.ParseIP(host) != nil {
+		return false
+	}
+	labels := strings.Split(host, ".")
+	if len(labels) < 2 {
+		return false
+	}
+	for _, label := range labels {
+		if !labelRegex.MatchString(label) {
+			return false
+		}
+	}
+	return true
+}
+
+// Key 返回用于缓存键的域名标识，空域名表示默认域名
+func Key(domain string) string {
+	if domain == "" {
+		return DefaultKey
+	}
+	return domain
+}
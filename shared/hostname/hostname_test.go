@@ -0,0 +1,32 @@
+package hostname
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	cases := map[string]string{
+		"Go.Brand.com":      "go.brand.com",
+		"go.brand.com:8443": "go.brand.com",
+		"go.brand.com.":     "go.brand.com",
+		"localhost:8081":    "localhost",
+	}
+	for in, want := range cases {
+		if got := Normalize(in); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestIsValid(t *testing.T) {
+	valid := []string{"go.brand.com", "a.io", "links.my-brand.co.uk"}
+	invalid := []string{"", "localhost", "127.0.0.1", "-bad.com", "bad_.com", "go..com"}
+	for _, h := range valid {
+		if !IsValid(h) {
+			t.Errorf("IsValid(%q) = false, want true", h)
+		}
+	}
+	for _, h := range invalid {
+		if IsValid(h) {
+			t.Errorf("IsValid(%q) = true, want false", h)
+		}
+	}
+}
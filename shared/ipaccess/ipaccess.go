@@ -0,0 +1,107 @@
+package ipaccess
+
+import (
+	"fmt"
+	"net/netip"
+	"shared/message"
+	"strings"
+)
+
+// MaxEntries 单个列表允许的地址段数量上限
+const MaxEntries = 100
+
+// 拒绝访问的规则来源
+const (
+	ScopeLink      = "link"
+	ScopeWorkspace = "workspace"
+)
+
+// Decision 访问控制的判定结果
+type Decision struct {
+	Allowed bool
+	Scope   string // 拒绝访问的规则来源
+	Reason  string // message.AccessDeniedReason*
+	Rule    string // 命中的拒绝地址段
+}
+
+// Normalize 校验并规范化地址段列表：单个IP转换为 /32 或 /128，地址段取网络地址，
+// 去重后保持原有顺序。列表为空时返回 nil
+func Normalize(entries []string) ([]string, error) {
+	if len(entries) > MaxEntries {
+		return nil, fmt.Errorf("at most %d entries are allowed", MaxEntries)
+	}
+	seen := make(map[string]bool, len(entries))
+	var out []string
+	for _, entry := range entries {
+		prefix, err := parsePrefix(strings.TrimSpace(entry))
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q", entry)
+		}
+		s := prefix.String()
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+// Check 依次检查工作空间和链接的访问控制列表，两者都通过才允许访问。
+// 拒绝列表优先于允许列表；客户端IP无法解析时，只要配置了任一列表即拒绝
+func Check(ip string, workspace, link *message.IPAccess) Decision {
+	addr, err := netip.ParseAddr(ip)
+	valid := err == nil
+	addr = addr.Unmap()
+	if d := check(addr, valid, ScopeWorkspace, workspace); !d.Allowed {
+		return d
+	}
+	return check(addr, valid, ScopeLink, link)
+}
+
+func check(addr netip.Addr, valid bool, scope string, access *message.IPAccess) Decision {
+	if access == nil || len(access.Allow) == 0 && len(access.Deny) == 0 {
+		return Decision{Allowed: true}
+	}
+	if !valid {
+		return Decision{Scope: scope, Reason: message.AccessDeniedReasonNotAllowed}
+	}
+	for _, cidr := range access.Deny {
+		if contains(cidr, addr) {
+			return Decision{Scope: scope, Reason: message.AccessDeniedReasonDenyList, Rule: cidr}
+		}
+	}
+	if len(access.Allow) == 0 {
+		return Decision{Allowed: true}
+	}
+	for _, cidr := range access.Allow {
+		if contains(cidr, addr) {
+			return Decision{Allowed: true}
+		}
+	}
+	return Decision{Scope: scope, Reason: message.AccessDeniedReasonNotAllowed}
+}
+
+// 无法解析的地址段不匹配任何地址，允许列表中只有此类地址段时拒绝所有访问
+func contains(cidr string, addr netip.Addr) bool {
+	prefix, err := parsePrefix(cidr)
+	return err == nil && prefix.Contains(addr)
+}
+
+func parsePrefix(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	if addr.Zone() != "" {
+		return netip.Prefix{}, fmt.Errorf("zoned address %q", s)
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
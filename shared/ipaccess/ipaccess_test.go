@@ -0,0 +1,59 @@
+package ipaccess
+
+import (
+	"reflect"
+	"shared/message"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	got, err := Normalize([]string{" 10.1.2.3/8 ", "203.0.113.7", "2001:db8::1/32", "10.0.0.0/8", "::ffff:198.51.100.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"10.0.0.0/8", "203.0.113.7/32", "2001:db8::/32", "198.51.100.1/32"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Normalize = %v, want %v", got, want)
+	}
+	for _, bad := range []string{"", "10.0.0.0/33", "office", "fe80::1%eth0"} {
+		if _, err := Normalize([]string{bad}); err == nil {
+			t.Errorf("Normalize(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	office := &message.IPAccess{Allow: []string{"10.0.0.0/8", "2001:db8::/32"}, Deny: []string{"10.9.0.0/16"}}
+	blocked := &message.IPAccess{Deny: []string{"203.0.113.0/24"}}
+
+	tests := []struct {
+		name      string
+		ip        string
+		workspace *message.IPAccess
+		link      *message.IPAccess
+		want      Decision
+	}{
+		{name: "no lists", ip: "198.51.100.1", want: Decision{Allowed: true}},
+		{name: "allowed range", ip: "10.1.2.3", link: office, want: Decision{Allowed: true}},
+		{name: "allowed ipv6", ip: "2001:db8::42", link: office, want: Decision{Allowed: true}},
+		{name: "ipv4 mapped", ip: "::ffff:10.1.2.3", link: office, want: Decision{Allowed: true}},
+		{name: "outside allow list", ip: "198.51.100.1", link: office,
+			want: Decision{Scope: ScopeLink, Reason: message.AccessDeniedReasonNotAllowed}},
+		{name: "deny wins over allow", ip: "10.9.1.1", link: office,
+			want: Decision{Scope: ScopeLink, Reason: message.AccessDeniedReasonDenyList, Rule: "10.9.0.0/16"}},
+		{name: "deny only", ip: "198.51.100.1", link: blocked, want: Decision{Allowed: true}},
+		{name: "workspace checked first", ip: "203.0.113.9", workspace: blocked, link: office,
+			want: Decision{Scope: ScopeWorkspace, Reason: message.AccessDeniedReasonDenyList, Rule: "203.0.113.0/24"}},
+		{name: "both must pass", ip: "10.1.2.3", workspace: office, link: &message.IPAccess{Allow: []string{"10.2.0.0/16"}},
+			want: Decision{Scope: ScopeLink, Reason: message.AccessDeniedReasonNotAllowed}},
+		{name: "unparsable ip", ip: "unknown", link: blocked,
+			want: Decision{Scope: ScopeLink, Reason: message.AccessDeniedReasonNotAllowed}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Check(tt.ip, tt.workspace, tt.link); got != tt.want {
+				t.Errorf("Check = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -11,6 +11,7 @@ const (
 	LinkStatusActive   LinkStatus = "active"
 	LinkStatusDisabled LinkStatus = "disabled"
 	LinkStatusExpired  LinkStatus = "expired"
+	LinkStatusFlagged  LinkStatus = "flagged" // 被标记为可疑，访问前展示风险提示页
 )
 
 type Message interface {
@@ -33,10 +34,41 @@ func (m BaseMessage) GetEventType() string {
 // CacheWarmupMessage 缓存预热消息
 type CacheWarmupMessage struct {
 	BaseMessage
+	WorkspaceID uint64     `json:"workspace_id"`
+	Domain      string     `json:"domain,omitempty"`
 	ShortCode   string     `json:"short_code"`
 	OriginalURL string     `json:"original_url"`
+	ActivateAt  *time.Time `json:"activate_at,omitempty"`
 	ExpiredAt   *time.Time `json:"expired_at"`
 	LogID       uint64     `json:"log_id"`
+	// 访问密码的 bcrypt 哈希，由 redirect-service 在本地校验
+	PasswordHash string `json:"password_hash,omitempty"`
+	// 最大访问次数，0表示不限制，由 redirect-service 原子计数
+	MaxClicks int64 `json:"max_clicks,omitempty"`
+	// 条件跳转规则，由 redirect-service 在跳转时匹配
+	Rules []RedirectRule `json:"rules,omitempty"`
+	// A/B 测试分组
+	Variants []LinkVariant `json:"variants,omitempty"`
+	// 移动端深度链接
+	DeepLink *DeepLink `json:"deep_link,omitempty"`
+	// 分享卡片覆盖值，社交平台爬虫抓取时使用
+	OpenGraph *OpenGraph `json:"open_graph,omitempty"`
+	// 链接的IP访问控制列表
+	IPAccess *IPAccess `json:"ip_access,omitempty"`
+	// 所属工作空间的IP访问控制列表，与链接的列表同时生效
+	WorkspaceIPAccess *IPAccess `json:"workspace_ip_access,omitempty"`
+	// 查询参数和路径转发
+	ForwardQuery  bool   `json:"forward_query,omitempty"`
+	QueryConflict string `json:"query_conflict,omitempty"`
+	ForwardPath   bool   `json:"forward_path,omitempty"`
+	// OriginalURL 为目标地址模板，跳转时展开
+	Template bool `json:"template,omitempty"`
+	// 跳转方式，空表示 302
+	RedirectType string `json:"redirect_type,omitempty"`
+	// 只允许通过签名地址访问，由 redirect-service 在本地校验签名
+	SignedOnly bool `json:"signed_only,omitempty"`
+	// 重定向像素，非空时跳转前展示触发像素的中间页
+	Pixels []RetargetingPixel `json:"pixels,omitempty"`
 }
 
 func (m CacheWarmupMessage) GetKey() string {
@@ -53,10 +85,43 @@ func (m CacheWarmupMessage) Validate() error {
 // CacheUpdateMessage 缓存更新消息
 type CacheUpdateMessage struct {
 	BaseMessage
+	WorkspaceID uint64     `json:"workspace_id"`
+	Domain      string     `json:"domain,omitempty"`
 	ShortCode   string     `json:"short_code"`
 	OriginalURL string     `json:"original_url"`
+	ActivateAt  *time.Time `json:"activate_at,omitempty"`
 	ExpiredAt   *time.Time `json:"expired_at,omitempty"`
 	Status      LinkStatus `json:"status"`
+	// 访问密码的 bcrypt 哈希，由 redirect-service 在本地校验
+	PasswordHash string `json:"password_hash,omitempty"`
+	// 最大访问次数，0表示不限制，由 redirect-service 原子计数
+	MaxClicks int64 `json:"max_clicks,omitempty"`
+	// 条件跳转规则，由 redirect-service 在跳转时匹配
+	Rules []RedirectRule `json:"rules,omitempty"`
+	// A/B 测试分组
+	Variants []LinkVariant `json:"variants,omitempty"`
+	// 移动端深度链接
+	DeepLink *DeepLink `json:"deep_link,omitempty"`
+	// 分享卡片覆盖值，社交平台爬虫抓取时使用
+	OpenGraph *OpenGraph `json:"open_graph,omitempty"`
+	// 链接的IP访问控制列表
+	IPAccess *IPAccess `json:"ip_access,omitempty"`
+	// 所属工作空间的IP访问控制列表，与链接的列表同时生效
+	WorkspaceIPAccess *IPAccess `json:"workspace_ip_access,omitempty"`
+	// 查询参数和路径转发
+	ForwardQuery  bool   `json:"forward_query,omitempty"`
+	QueryConflict string `json:"query_conflict,omitempty"`
+	ForwardPath   bool   `json:"forward_path,omitempty"`
+	// OriginalURL 为目标地址模板，跳转时展开
+	Template bool `json:"template,omitempty"`
+	// 跳转方式，空表示 302
+	RedirectType string `json:"redirect_type,omitempty"`
+	// 只允许通过签名地址访问，由 redirect-service 在本地校验签名
+	SignedOnly bool `json:"signed_only,omitempty"`
+	// 重定向像素，非空时跳转前展示触发像素的中间页
+	Pixels []RetargetingPixel `json:"pixels,omitempty"`
+	// 清零访问计数：链接从过期恢复为有效，或访问次数上限发生变化
+	ResetClicks bool `json:"reset_clicks,omitempty"`
 }
 
 func (m CacheUpdateMessage) GetKey() string {
@@ -70,11 +135,22 @@ func (m CacheUpdateMessage) Validate() error {
 	return nil
 }
 
+// 缓存删除原因
+const (
+	DeleteReasonDeleted   = "deleted"   // 链接被删除，访问计数一并清理
+	DeleteReasonInactive  = "inactive"  // 链接被禁用或过期
+	DeleteReasonExhausted = "exhausted" // 访问次数达到上限
+	DeleteReasonStale     = "stale"     // 无法生成完整的缓存数据，由 redirect-service 回源
+)
+
 // CacheDeleteMessage 缓存删除消息
 type CacheDeleteMessage struct {
 	BaseMessage
-	ShortCode string `json:"short_code"`
-	Reason    string `json:"reason"`
+	WorkspaceID uint64 `json:"workspace_id"`
+	Domain      string `json:"domain,omitempty"`
+	ShortCode   string `json:"short_code"`
+	Reason      string `json:"reason"`
+	ResetClicks bool   `json:"reset_clicks,omitempty"` // 同时清零访问计数，规则与缓存更新消息相同
 }
 
 func (m CacheDeleteMessage) GetKey() string {
@@ -87,3 +163,54 @@ func (m CacheDeleteMessage) Validate() error {
 	}
 	return nil
 }
+
+// LinkExhaustedMessage 短链访问次数达到上限消息，由 redirect-service 发出
+type LinkExhaustedMessage struct {
+	BaseMessage
+	WorkspaceID uint64 `json:"workspace_id"`
+	Domain      string `json:"domain,omitempty"`
+	ShortCode   string `json:"short_code"`
+	Clicks      int64  `json:"clicks"`
+}
+
+func (m LinkExhaustedMessage) GetKey() string {
+	return m.ShortCode
+}
+
+func (m LinkExhaustedMessage) Validate() error {
+	if m.ShortCode == "" {
+		return fmt.Errorf("short_code is required")
+	}
+	return nil
+}
+
+// 访问被拒绝的原因
+const (
+	AccessDeniedReasonDenyList   = "deny_list"   // 客户端IP命中拒绝列表
+	AccessDeniedReasonNotAllowed = "not_allowed" // 客户端IP不在允许列表中
+)
+
+// AccessDeniedMessage 访问被IP访问控制拒绝的消息，由 redirect-service 发出
+type AccessDeniedMessage struct {
+	BaseMessage
+	WorkspaceID uint64 `json:"workspace_id"`
+	Domain      string `json:"domain,omitempty"`
+	ShortCode   string `json:"short_code"`
+	IP          string `json:"ip"`
+	UserAgent   string `json:"user_agent,omitempty"`
+	Referer     string `json:"referer,omitempty"`
+	Scope       string `json:"scope"`          // 拒绝访问的规则来源：link / workspace
+	Reason      string `json:"reason"`         // deny_list / not_allowed
+	Rule        string `json:"rule,omitempty"` // 命中的拒绝地址段
+}
+
+func (m AccessDeniedMessage) GetKey() string {
+	return m.ShortCode
+}
+
+func (m AccessDeniedMessage) Validate() error {
+	if m.ShortCode == "" {
+		return fmt.Errorf("short_code is required")
+	}
+	return nil
+}
@@ -2,9 +2,18 @@ package message
 
 import "time"
 
+// 点击主题上的事件类型
+const (
+	EventTypeClick           = "click_event"
+	EventTypeWarningContinue = "warning_continue" // 访问者在风险提示页选择继续访问
+	EventTypeLinkUnfurl      = "link_unfurl"      // 社交平台爬虫抓取分享卡片
+)
+
 // ClickEventMessage 点击事件消息结构
 type ClickEventMessage struct {
 	BaseMessage
+	WorkspaceID uint64    `json:"workspace_id"`
+	Domain      string    `json:"domain,omitempty"`
 	ShortCode   string    `json:"short_code"`
 	OriginalURL string    `json:"original_url"`
 	IP          string    `json:"ip"`
@@ -15,4 +24,6 @@ type ClickEventMessage struct {
 	Country     string    `json:"country,omitempty"`
 	Region      string    `json:"region,omitempty"`
 	City        string    `json:"city,omitempty"`
+	VariantID   string    `json:"variant_id,omitempty"` // 命中的 A/B 测试分组
+	Bot         bool      `json:"bot,omitempty"`        // 爬虫访问，UA 解析无法识别时仍按爬虫记录
 }
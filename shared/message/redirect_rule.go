@@ -0,0 +1,48 @@
+package message
+
+// RedirectRule 条件跳转规则，按顺序匹配，已设置的条件全部满足时跳转到 Destination
+type RedirectRule struct {
+	Countries   []string `json:"countries,omitempty"`  // ISO 3166-1 alpha-2 国家代码，如 DE
+	Devices     []string `json:"devices,omitempty"`    // mobile / tablet / desktop
+	OS          []string `json:"os,omitempty"`         // ios / android / windows / macos / linux
+	Languages   []string `json:"languages,omitempty"`  // Accept-Language 语言标签，如 de、en-US
+	TimeStart   string   `json:"time_start,omitempty"` // 每日生效时段起点 HH:MM，晚于终点表示跨零点
+	TimeEnd     string   `json:"time_end,omitempty"`   // 每日生效时段终点 HH:MM（不含）
+	Timezone    string   `json:"timezone,omitempty"`   // 时段所用的 IANA 时区，默认 UTC
+	Destination string   `json:"destination"`
+}
+
+// LinkVariant A/B 测试分组，访问者按权重稳定分配到其中一个分组
+type LinkVariant struct {
+	ID          string `json:"id"`
+	Destination string `json:"destination"`
+	Weight      int    `json:"weight"` // 百分比权重，所有分组之和为100
+}
+
+// DeepLink 移动端深度链接，iOS 和 Android 访问者优先打开 App，未安装时跳转到应用商店或网页
+type DeepLink struct {
+	IOSURL          string `json:"ios_url,omitempty"`           // URL scheme 或 universal link
+	IOSStoreURL     string `json:"ios_store_url,omitempty"`     // App Store 地址
+	AndroidURL      string `json:"android_url,omitempty"`       // URL scheme 地址，跳转时转换为 intent://
+	AndroidPackage  string `json:"android_package,omitempty"`   // App 包名
+	AndroidStoreURL string `json:"android_store_url,omitempty"` // Google Play 地址
+}
+
+// RetargetingPixel 重定向像素，跳转页按平台生成固定的代码片段
+type RetargetingPixel struct {
+	Provider   string `json:"provider"` // facebook / google / linkedin
+	TrackingID string `json:"tracking_id"`
+}
+
+// OpenGraph 社交平台分享卡片的覆盖值，未设置的字段使用从目标页面抓取的元数据
+type OpenGraph struct {
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Image       string `json:"image,omitempty"`
+}
+
+// IPAccess CIDR 访问控制列表，命中 Deny 的地址拒绝访问；Allow 非空时只允许其中的地址
+type IPAccess struct {
+	Allow []string `json:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty"`
+}
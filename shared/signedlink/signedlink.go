@@ -0,0 +1,184 @@
+package signedlink
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/url"
+	"shared/errors"
+	"shared/hostname"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 签名短链的查询参数，形如 /<code>?exp=...&kid=...&bind=ip&sig=...
+const (
+	ParamExpires   = "exp"  // 过期时间，Unix 秒
+	ParamKeyID     = "kid"  // 签名密钥ID，用于密钥轮换
+	ParamBinding   = "bind" // 绑定类型，绑定值不出现在地址中，只参与签名
+	ParamSignature = "sig"
+)
+
+// 绑定类型
+const (
+	BindIP    = "ip"    // 只允许指定IP访问
+	BindEmail = "email" // 访问者需填写指定邮箱
+)
+
+var (
+	ErrInvalidSignature  = errors.NewBusinessError("invalid link signature")
+	ErrSignatureExpired  = errors.NewBusinessError("signed link expired")
+	ErrSignatureRequired = errors.NewBusinessError("link requires a signature")
+	ErrNoSigningKey      = errors.NewBusinessError("no signing key configured")
+)
+
+// Key 签名密钥
+type Key struct {
+	ID     string `mapstructure:"id"`
+	Secret string `mapstructure:"secret"`
+}
+
+// Claims 签名覆盖的内容，Domain 为空表示默认域名
+type Claims struct {
+	Domain    string
+	ShortCode string
+	ExpiresAt time.Time
+	Binding   string // 空表示不绑定
+	BindValue string // IP 或邮箱
+}
+
+// Token 从查询参数解析出的签名
+type Token struct {
+	ExpiresAt time.Time
+	KeyID     string
+	Binding   string
+	Signature []byte
+}
+
+// KeyRing 签名密钥环：使用当前密钥签名，按 kid 选择密钥校验。
+// 轮换时先在 redirect-service 加入新密钥，再切换 generate-service 的当前密钥，
+// 旧密钥在其签发的链接全部过期后移除
+type KeyRing struct {
+	active string
+	keys   map[string][]byte
+}
+
+// NewKeyRing 创建密钥环，active 为空时只能校验不能签名
+func NewKeyRing(keys []Key, active string) (*KeyRing, error) {
+	r := &KeyRing{active: active, keys: make(map[string][]byte, len(keys))}
+	for _, k := range keys {
+		if k.ID == "" || k.Secret == "" {
+			return nil, fmt.Errorf("signing key id and secret are required")
+		}
+		if strings.ContainsAny(k.ID, "&=?#") {
+			return nil, fmt.Errorf("invalid signing key id %q", k.ID)
+		}
+		if _, ok := r.keys[k.ID]; ok {
+			return nil, fmt.Errorf("duplicate signing key id %q", k.ID)
+		}
+		r.keys[k.ID] = []byte(k.Secret)
+	}
+	if active != "" {
+		if _, ok := r.keys[active]; !ok {
+			return nil, fmt.Errorf("active signing key %q not found", active)
+		}
+	}
+	return r, nil
+}
+
+// Sign 使用当前密钥签名，返回需追加到短链地址的查询参数
+func (r *KeyRing) Sign(claims Claims) (url.Values, error) {
+	if r.active == "" {
+		return nil, ErrNoSigningKey
+	}
+	q := url.Values{}
+	q.Set(ParamExpires, strconv.FormatInt(claims.ExpiresAt.Unix(), 10))
+	q.Set(ParamKeyID, r.active)
+	if claims.Binding != "" {
+		q.Set(ParamBinding, claims.Binding)
+	}
+	sig := mac(r.keys[r.active], claims)
+	q.Set(ParamSignature, base64.RawURLEncoding.EncodeToString(sig))
+	return q, nil
+}
+
+// Verify 校验签名和有效期，claims 的 Binding 和 ExpiresAt 取自 token
+func (r *KeyRing) Verify(token *Token, claims Claims, now time.Time) error {
+	key, ok := r.keys[token.KeyID]
+	if !ok {
+		return ErrInvalidSignature
+	}
+	claims.ExpiresAt = token.ExpiresAt
+	claims.Binding = token.Binding
+	if !hmac.Equal(token.Signature, mac(key, claims)) {
+		return ErrInvalidSignature
+	}
+	if !now.Before(token.ExpiresAt) {
+		return ErrSignatureExpired
+	}
+	return nil
+}
+
+// Signed 查询参数中是否带有签名
+func Signed(q url.Values) bool {
+	return q.Has(ParamSignature)
+}
+
+// Parse 解析查询参数中的签名，格式错误时返回 ErrInvalidSignature
+func Parse(q url.Values) (*Token, error) {
+	exp, err := strconv.ParseInt(q.Get(ParamExpires), 10, 64)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(q.Get(ParamSignature))
+	if err != nil || len(sig) != sha256.Size {
+		return nil, ErrInvalidSignature
+	}
+	binding := q.Get(ParamBinding)
+	if binding != "" && binding != BindIP && binding != BindEmail {
+		return nil, ErrInvalidSignature
+	}
+	return &Token{
+		ExpiresAt: time.Unix(exp, 0),
+		KeyID:     q.Get(ParamKeyID),
+		Binding:   binding,
+		Signature: sig,
+	}, nil
+}
+
+// Strip 移除签名参数，其余参数按原样保留用于查询参数转发
+func Strip(q url.Values) url.Values {
+	out := make(url.Values, len(q))
+	for k, v := range q {
+		switch k {
+		case ParamExpires, ParamKeyID, ParamBinding, ParamSignature:
+		default:
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// NormalizeBindValue 规范化绑定值，IP 转换为标准形式，邮箱转为小写
+func NormalizeBindValue(binding, value string) string {
+	value = strings.TrimSpace(value)
+	switch binding {
+	case BindIP:
+		if ip := net.ParseIP(value); ip != nil {
+			return ip.String()
+		}
+	case BindEmail:
+		return strings.ToLower(value)
+	}
+	return value
+}
+
+func mac(key []byte, c Claims) []byte {
+	h := hmac.New(sha256.New, key)
+	fmt.Fprintf(h, "v1\n%s\n%s\n%d\n%s\n%s",
+		hostname.Key(c.Domain), c.ShortCode, c.ExpiresAt.Unix(), c.Binding, NormalizeBindValue(c.Binding, c.BindValue))
+	return h.Sum(nil)
+}
@@ -0,0 +1,65 @@
+package signedlink
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestSignVerify(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	oldRing, _ := NewKeyRing([]Key{{ID: "k1", Secret: "old-secret"}}, "k1")
+	ring, err := NewKeyRing([]Key{{ID: "k1", Secret: "old-secret"}, {ID: "k2", Secret: "new-secret"}}, "k2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims := Claims{Domain: "go.example.com", ShortCode: "abc123", ExpiresAt: now.Add(time.Hour), Binding: BindIP, BindValue: "203.0.113.7"}
+
+	tests := []struct {
+		name   string
+		signer *KeyRing
+		edit   func(q url.Values, c *Claims)
+		at     time.Time
+		want   error
+	}{
+		{name: "valid", signer: ring, at: now},
+		{name: "signed with rotated key", signer: oldRing, at: now},
+		{name: "expired", signer: ring, at: now.Add(time.Hour), want: ErrSignatureExpired},
+		{name: "extended expiry", signer: ring, at: now, want: ErrInvalidSignature,
+			edit: func(q url.Values, _ *Claims) { q.Set(ParamExpires, "1800000000") }},
+		{name: "other short code", signer: ring, at: now, want: ErrInvalidSignature,
+			edit: func(_ url.Values, c *Claims) { c.ShortCode = "abc124" }},
+		{name: "other ip", signer: ring, at: now, want: ErrInvalidSignature,
+			edit: func(_ url.Values, c *Claims) { c.BindValue = "203.0.113.8" }},
+		{name: "binding removed", signer: ring, at: now, want: ErrInvalidSignature,
+			edit: func(q url.Values, _ *Claims) { q.Del(ParamBinding) }},
+		{name: "unknown key", signer: ring, at: now, want: ErrInvalidSignature,
+			edit: func(q url.Values, _ *Claims) { q.Set(ParamKeyID, "k3") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := tt.signer.Sign(claims)
+			if err != nil {
+				t.Fatal(err)
+			}
+			visitor := claims
+			if tt.edit != nil {
+				tt.edit(q, &visitor)
+			}
+			token, err := Parse(q)
+			if err == nil {
+				err = ring.Verify(token, visitor, tt.at)
+			}
+			if err != tt.want {
+				t.Fatalf("Verify() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSignWithoutActiveKey(t *testing.T) {
+	ring, _ := NewKeyRing([]Key{{ID: "k1", Secret: "secret"}}, "")
+	if _, err := ring.Sign(Claims{ShortCode: "abc"}); err != ErrNoSigningKey {
+		t.Fatalf("Sign() error = %v, want %v", err, ErrNoSigningKey)
+	}
+}
@@ -13,10 +13,16 @@ type ServerConfig struct {
 	Mode string `mapstructure:"mode"`
 }
 
+// AuthConfig 统计接口只接受 generate-service 转发的请求，令牌需与其 stats.internal_token 一致
+type AuthConfig struct {
+	InternalToken string `mapstructure:"internal_token"`
+}
+
 type Config struct {
 	Server    ServerConfig          `mapstructure:"server"`
 	Log       logger.Config         `mapstructure:"log"`
 	MySQL     database.MySQLConfig  `mapstructure:"mysql"`
 	Kafka     consumer.KafkaConfig  `mapstructure:"kafka"`
 	Generator idgen.GeneratorConfig `mapstructure:"id_generator"`
+	Auth      AuthConfig            `mapstructure:"auth"`
 }
@@ -65,6 +65,8 @@ func (h *RecordClickHandler) Handle(topic string, msg *sarama.ConsumerMessage, s
 		clickTime = clickMsg.ClickTime
 	}
 	req := &click.RecordClickReq{
+		WorkspaceID: clickMsg.WorkspaceID,
+		Domain:      clickMsg.Domain,
 		ShortCode:   clickMsg.ShortCode,
 		OriginalURL: clickMsg.OriginalURL,
 		IP:          clickMsg.IP,
@@ -75,6 +77,9 @@ func (h *RecordClickHandler) Handle(topic string, msg *sarama.ConsumerMessage, s
 		Region:      clickMsg.Region,
 		City:        clickMsg.City,
 		ClickBy:     clickMsg.ClickBy,
+		VariantID:   clickMsg.VariantID,
+		EventType:   clickMsg.EventType,
+		Bot:         clickMsg.Bot,
 	}
 	if err := h.clickService.RecordClick(context.Background(), req); err != nil {
 		logger.Logger.Error("failed to record click", zap.String("topic", topic), zap.Error(err))
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"shared/message"
 	trac "statistics-service/internal/consumer/tracker"
+	"statistics-service/internal/model"
 	"statistics-service/internal/pkg/logger"
 	"statistics-service/internal/service/summary"
 	"sync"
@@ -29,7 +30,7 @@ type SummaryHandler struct {
 	handlerKey     string
 	summaryService *summary.Service
 	tracker        trac.ProcessedEventTracker
-	buffer         map[string]int
+	buffer         map[model.SummaryKey]int
 	messageBuffer  []*ClickMessageWrapper // 保存消息用于手动提交
 	batchSize      int
 	wg             *sync.WaitGroup
@@ -50,7 +51,7 @@ func NewSummaryHandler(
 		handlerKey:     handlerKey,
 		summaryService: summaryService,
 		tracker:        trac.NewDefaultTracker(),
-		buffer:         make(map[string]int),
+		buffer:         make(map[model.SummaryKey]int),
 		messageBuffer:  make([]*ClickMessageWrapper, 0, batchSize),
 		batchSize:      batchSize,
 		cron:           cron.New(cron.WithSeconds()),
@@ -88,6 +89,11 @@ func (h *SummaryHandler) Handle(topic string, msg *sarama.ConsumerMessage, sessi
 		session.MarkMessage(msg, "")
 		return
 	}
+	// 只汇总点击事件，风险提示页的继续访问、爬虫抓取分享卡片等事件不计入点击量
+	if baseMsg.EventType != "" && baseMsg.EventType != message.EventTypeClick {
+		session.MarkMessage(msg, "")
+		return
+	}
 	logger.Logger.Info("start handle message",
 		zap.String("topic", topic), zap.ByteString("value", msg.Value))
 	var clickMsg message.ClickEventMessage
@@ -99,11 +105,17 @@ func (h *SummaryHandler) Handle(topic string, msg *sarama.ConsumerMessage, sessi
 		return
 	}
 
-	// 生成聚合key: shortCode_date
+	// 爬虫访问只保留明细，不计入点击量
+	if clickMsg.Bot {
+		session.MarkMessage(msg, "")
+		return
+	}
+
+	// 生成聚合key: 工作空间、域名、短码和日期
 	if clickMsg.ClickTime.IsZero() {
 		clickMsg.ClickTime = time.Now()
 	}
-	key := h.getKey(clickMsg.ShortCode, clickMsg.ClickTime)
+	key := h.getKey(&clickMsg)
 
 	h.mu.Lock()
 	h.buffer[key]++
@@ -134,7 +146,7 @@ func (h *SummaryHandler) triggerFlush() {
 
 	// 复制并清空缓冲区
 	bufferCopy := h.buffer
-	h.buffer = make(map[string]int)
+	h.buffer = make(map[model.SummaryKey]int)
 	messageBufferCopy := h.messageBuffer
 	h.messageBuffer = make([]*ClickMessageWrapper, 0, h.batchSize)
 	h.mu.Unlock()
@@ -158,8 +170,15 @@ func (h *SummaryHandler) triggerFlush() {
 	h.markMessageAsProcessed(messageBufferCopy)
 }
 
-func (h *SummaryHandler) getKey(shortCode string, clickTime time.Time) string {
-	return shortCode + "_" + clickTime.Format(h.dateFormat)
+func (h *SummaryHandler) getKey(msg *message.ClickEventMessage) model.SummaryKey {
+	return model.SummaryKey{
+		LinkKey: model.LinkKey{
+			WorkspaceID: msg.WorkspaceID,
+			Domain:      msg.Domain,
+			ShortCode:   msg.ShortCode,
+		},
+		StatDate: msg.ClickTime.Format(h.dateFormat),
+	}
 }
 
 func (h *SummaryHandler) restoreToBuffer(msgWrapperBuffer []*ClickMessageWrapper) {
@@ -173,7 +192,7 @@ func (h *SummaryHandler) restoreToBuffer(msgWrapperBuffer []*ClickMessageWrapper
 			continue
 		}
 		wrapper.Count++
-		key := h.getKey(wrapper.ClickMsg.ShortCode, wrapper.ClickMsg.ClickTime)
+		key := h.getKey(wrapper.ClickMsg)
 		h.buffer[key]++
 		h.messageBuffer = append(h.messageBuffer, wrapper)
 	}
@@ -2,8 +2,11 @@ package handler
 
 import (
 	"net/http"
+	"shared/hostname"
 	"shared/model"
+	statsModel "statistics-service/internal/model"
 	"statistics-service/internal/service/click"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -26,6 +29,11 @@ func (h *StatsHandler) GetStatsSummary(c *gin.Context) {
 		})
 		return
 	}
+	workspaceID, ok := getWorkspaceID(c)
+	if !ok {
+		return
+	}
+	key := statsModel.LinkKey{WorkspaceID: workspaceID, Domain: hostname.Normalize(c.Query("domain")), ShortCode: shortCode}
 	var req struct {
 		StartDate *time.Time `form:"start_date" time_format:"2006-01-02"`
 		EndDate   *time.Time `form:"end_date" time_format:"2006-01-02"`
@@ -38,7 +46,7 @@ func (h *StatsHandler) GetStatsSummary(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.clickService.GetStatsSummary(c.Request.Context(), shortCode, req.StartDate, req.EndDate)
+	resp, err := h.clickService.GetStatsSummary(c.Request.Context(), key, req.StartDate, req.EndDate)
 	if err != nil {
 		c.Error(err)
 		return
@@ -55,6 +63,11 @@ func (h *StatsHandler) GetTimeSeries(c *gin.Context) {
 		})
 		return
 	}
+	workspaceID, ok := getWorkspaceID(c)
+	if !ok {
+		return
+	}
+	key := statsModel.LinkKey{WorkspaceID: workspaceID, Domain: hostname.Normalize(c.Query("domain")), ShortCode: shortCode}
 	unit := TimeUnit(c.Param("unit"))
 	if unit == "" {
 		unit = Daily
@@ -86,7 +99,7 @@ func (h *StatsHandler) GetTimeSeries(c *gin.Context) {
 	}
 	resp, err := h.clickService.GetTimeSeriesSummary(
 		c.Request.Context(),
-		shortCode,
+		key,
 		startDate,
 		endDate,
 		unit.getGroupExpr(),
@@ -108,7 +121,12 @@ func (h *StatsHandler) GetGeographicStats(c *gin.Context) {
 		})
 		return
 	}
-	resp, err := h.clickService.GetGeographicStats(c.Request.Context(), shortCode)
+	workspaceID, ok := getWorkspaceID(c)
+	if !ok {
+		return
+	}
+	key := statsModel.LinkKey{WorkspaceID: workspaceID, Domain: hostname.Normalize(c.Query("domain")), ShortCode: shortCode}
+	resp, err := h.clickService.GetGeographicStats(c.Request.Context(), key)
 	if err != nil {
 		c.Error(err)
 		return
@@ -125,10 +143,63 @@ func (h *StatsHandler) GetPlatformStats(c *gin.Context) {
 		})
 		return
 	}
-	resp, err := h.clickService.GetPlatformStats(c.Request.Context(), shortCode)
+	workspaceID, ok := getWorkspaceID(c)
+	if !ok {
+		return
+	}
+	key := statsModel.LinkKey{WorkspaceID: workspaceID, Domain: hostname.Normalize(c.Query("domain")), ShortCode: shortCode}
+	resp, err := h.clickService.GetPlatformStats(c.Request.Context(), key)
 	if err != nil {
 		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, resp)
 }
+
+// GetVariantStats 对比 A/B 测试各分组的点击量
+// @Router /api/v1/stats/{code}/variants [get]
+func (h *StatsHandler) GetVariantStats(c *gin.Context) {
+	shortCode := c.Param("code")
+	if shortCode == "" {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "invalid short code",
+			Message: "short code is required",
+		})
+		return
+	}
+	workspaceID, ok := getWorkspaceID(c)
+	if !ok {
+		return
+	}
+	key := statsModel.LinkKey{WorkspaceID: workspaceID, Domain: hostname.Normalize(c.Query("domain")), ShortCode: shortCode}
+	var req struct {
+		StartDate *time.Time `form:"start_date" time_format:"2006-01-02"`
+		EndDate   *time.Time `form:"end_date" time_format:"2006-01-02"`
+	}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "invalid request",
+			Message: "invalid query parameters",
+		})
+		return
+	}
+	resp, err := h.clickService.GetVariantStats(c.Request.Context(), key, req.StartDate, req.EndDate)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// 读取 generate-service 校验调用方权限后注入的 X-Workspace-ID，统计查询只返回该工作空间的数据
+func getWorkspaceID(c *gin.Context) (uint64, bool) {
+	workspaceID, err := strconv.ParseUint(c.GetHeader("X-Workspace-ID"), 10, 64)
+	if err != nil || workspaceID == 0 {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "invalid workspace",
+			Message: "X-Workspace-ID header is required",
+		})
+		return 0, false
+	}
+	return workspaceID, true
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"shared/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InternalAuth 只接受携带内部调用令牌的请求。统计接口由 generate-service 鉴权后转发，
+// 未配置令牌时拒绝所有请求
+func InternalAuth(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		got := c.GetHeader("X-Internal-Token")
+		if token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, model.ErrorResponse{
+				Error:   "unauthorized",
+				Message: "stats API is only reachable through generate-service",
+			})
+			return
+		}
+		c.Next()
+	}
+}
@@ -5,6 +5,8 @@ import "time"
 // ClickEvent 点击事件表模型
 type ClickEvent struct {
 	ID          uint64    `gorm:"column:id;primaryKey;type:bigint unsigned" json:"id,string"`
+	WorkspaceID uint64    `gorm:"column:workspace_id;type:bigint unsigned;not null;comment:所属工作空间" json:"workspace_id,string"`
+	Domain      string    `gorm:"column:domain;type:varchar(253);not null;default:'';comment:自定义域名" json:"domain,omitempty"`
 	ShortCode   string    `gorm:"column:short_code;type:varchar(20);not null;comment:短链码" json:"short_code"`
 	OriginalURL string    `gorm:"column:original_url;type:varchar(2048);not null;comment:原始URL" json:"original_url"`
 	IP          string    `gorm:"column:ip;type:varchar(45);not null;comment:客户端IP" json:"ip"`
@@ -16,6 +18,8 @@ type ClickEvent struct {
 	DeviceType  string    `gorm:"column:device_type;type:enum('desktop','mobile','tablet','bot','other');comment:设备类型" json:"device_type"`
 	Browser     string    `gorm:"column:browser;type:varchar(100);comment:浏览器" json:"browser"`
 	OS          string    `gorm:"column:os;type:varchar(100);comment:操作系统" json:"os"`
+	VariantID   string    `gorm:"column:variant_id;type:varchar(20);not null;default:'';comment:A/B测试分组" json:"variant_id,omitempty"`
+	EventType   string    `gorm:"column:event_type;type:varchar(30);not null;default:'click_event';comment:事件类型" json:"event_type"`
 	ClickTime   time.Time `gorm:"column:click_time;type:datetime(3);not null;comment:点击时间(精确到毫秒)" json:"click_time"`
 	CreatedAt   time.Time `gorm:"column:created_at;type:datetime(3);not null;default:CURRENT_TIMESTAMP(3)" json:"created_at"`
 	CreatedBy   string    `gorm:"column:created_by;type:varchar(100)" json:"created_by"`
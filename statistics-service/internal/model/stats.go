@@ -1,5 +1,12 @@
 package model
 
+// LinkKey 统计查询的链接标识，同一短码在不同域名下是不同的链接
+type LinkKey struct {
+	WorkspaceID uint64
+	Domain      string // 空表示默认域名
+	ShortCode   string
+}
+
 // GeographicStats 地理统计
 type GeographicStats struct {
 	Country        string `json:"country"`
@@ -9,6 +16,14 @@ type GeographicStats struct {
 	UniqueVisitors int64  `json:"unique_visitors"`
 }
 
+// VariantStats A/B 测试分组统计
+type VariantStats struct {
+	VariantID      string  `json:"variant_id"`
+	Clicks         int64   `json:"clicks"`
+	UniqueVisitors int64   `json:"unique_visitors"`
+	ClickShare     float64 `json:"click_share" gorm:"-"` // 占全部分组点击量的百分比
+}
+
 type PlatformStats struct {
 	DeviceType     string `json:"device_type"`
 	Clicks         int64  `json:"clicks"`
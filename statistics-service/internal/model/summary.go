@@ -7,8 +7,10 @@ import (
 // ClickStatsSummary 点击统计汇总表(按天)
 type ClickStatsSummary struct {
 	ID             int64     `gorm:"primaryKey;autoIncrement" json:"id"`
-	ShortCode      string    `gorm:"size:20;not null;uniqueIndex:uk_short_code_date" json:"shortCode"`
-	StatDate       time.Time `gorm:"type:date;not null;uniqueIndex:uk_short_code_date" json:"statDate"`
+	WorkspaceID    uint64    `gorm:"not null;uniqueIndex:uk_workspace_code_date,priority:1" json:"workspaceId"`
+	Domain         string    `gorm:"size:253;not null;default:'';uniqueIndex:uk_workspace_code_date,priority:2" json:"domain"`
+	ShortCode      string    `gorm:"size:20;not null;uniqueIndex:uk_workspace_code_date,priority:3" json:"shortCode"`
+	StatDate       time.Time `gorm:"type:date;not null;uniqueIndex:uk_workspace_code_date,priority:4" json:"statDate"`
 	TotalClicks    int       `gorm:"not null;default:0" json:"totalClicks"`
 	UniqueVisitors int       `gorm:"not null;default:0" json:"uniqueVisitors"`
 	MobileClicks   int       `gorm:"not null;default:0" json:"mobileClicks"`
@@ -30,9 +32,17 @@ func (ClickStatsSummary) TableName() string {
 	return "click_stats_summary"
 }
 
+// SummaryKey 按天汇总的聚合键，链接在工作空间和域名内唯一
+type SummaryKey struct {
+	LinkKey
+	StatDate string
+}
+
 // StatTotal 实时计算点击总量数据模型
 type StatTotal struct {
 	Id          uint64
+	WorkspaceID uint64
+	Domain      string
 	ShortCode   string
 	StatDate    string
 	TotalClicks int
@@ -37,6 +37,12 @@ type GeographicResponse struct {
 	GeographicStats []*GeographicStats `json:"geographic_stats"`
 }
 
+type VariantResponse struct {
+	ShortCode   string          `json:"short_code"`
+	TotalClicks int64           `json:"total_clicks"`
+	Variants    []*VariantStats `json:"variants"`
+}
+
 type PlatformResponse struct {
 	ShortCode   string           `json:"short_code"`
 	DeviceStats []*PlatformStats `json:"device_stats"`
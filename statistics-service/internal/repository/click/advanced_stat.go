@@ -2,13 +2,14 @@ package click
 
 import (
 	"context"
+	"shared/errors"
 	"statistics-service/internal/model"
 	"time"
 )
 
 func (r *repository) GetClickTimeline(
 	ctx context.Context,
-	shortCode string,
+	key model.LinkKey,
 	startTime *time.Time,
 	endTime *time.Time,
 	groupExpr string,
@@ -16,9 +17,8 @@ func (r *repository) GetClickTimeline(
 ) ([]model.TimeSeriesStats, error) {
 	var data []model.TimeSeriesStats
 
-	query := r.db.WithContext(ctx).Model(&model.ClickEvent{}).
-		Select(periodExpr+"as period, COUNT(*) as clicks, COUNT(DISTINCT ip) as unique_visitors ").
-		Where("short_code = ? AND Delete_flag = 'N'", shortCode)
+	query := r.scope(ctx, key).
+		Select(periodExpr + "as period, COUNT(*) as clicks, COUNT(DISTINCT ip) as unique_visitors ")
 	if startTime != nil {
 		query = query.Where("click_time >= ?", startTime.Format(time.DateOnly))
 	}
@@ -33,11 +33,11 @@ func (r *repository) GetClickTimeline(
 }
 
 // GetGeographicStats 获取地理信息统计
-func (r *repository) GetGeographicStats(ctx context.Context, shortCode string) ([]*model.GeographicStats, error) {
+func (r *repository) GetGeographicStats(ctx context.Context, key model.LinkKey) ([]*model.GeographicStats, error) {
 	var stats []*model.GeographicStats
-	err := r.db.WithContext(ctx).Model(&model.ClickEvent{}).
+	err := r.scope(ctx, key).
 		Select("country, region, city, COUNT(*) as clicks, COUNT(DISTINCT ip) as unique_visitors ").
-		Where("short_code = ? AND Delete_flag = 'N' AND country IS NOT NULL", shortCode).
+		Where("country IS NOT NULL").
 		Group("country, region, city").
 		Order("clicks desc").Scan(&stats).Error
 	if err != nil {
@@ -47,11 +47,11 @@ func (r *repository) GetGeographicStats(ctx context.Context, shortCode string) (
 }
 
 // GetPlatformStats 获取设备统计
-func (r *repository) GetPlatformStats(ctx context.Context, shortCode string) ([]*model.PlatformStats, error) {
+func (r *repository) GetPlatformStats(ctx context.Context, key model.LinkKey) ([]*model.PlatformStats, error) {
 	var stats []*model.PlatformStats
-	err := r.db.WithContext(ctx).Model(&model.ClickEvent{}).
+	err := r.scope(ctx, key).
 		Select("device_type, COUNT(*) as clicks, COUNT(DISTINCT ip) as unique_visitors ").
-		Where("short_code = ? AND Delete_flag = 'N' AND device_type IS NOT NULL", shortCode).
+		Where("device_type IS NOT NULL").
 		Group("device_type, city").
 		Order("clicks desc").Scan(&stats).Error
 	if err != nil {
@@ -59,3 +59,29 @@ func (r *repository) GetPlatformStats(ctx context.Context, shortCode string) ([]
 	}
 	return stats, nil
 }
+
+// GetVariantStats 获取 A/B 测试分组统计，未参与分流的点击不计入
+func (r *repository) GetVariantStats(
+	ctx context.Context,
+	key model.LinkKey,
+	startDate, endDate *time.Time,
+) ([]*model.VariantStats, error) {
+	var stats []*model.VariantStats
+	query := r.scope(ctx, key).
+		Select("variant_id, COUNT(*) as clicks, COUNT(DISTINCT ip) as unique_visitors").
+		Where("variant_id <> ''")
+	if startDate != nil {
+		query = query.Where("click_time >= ?", startDate.Format("2006-01-02 15:04:05"))
+	}
+	if endDate != nil {
+		query = query.Where("click_time <= ?", endDate.Format("2006-01-02 15:04:05"))
+	}
+	err := query.
+		Group("variant_id").
+		Order("variant_id").
+		Scan(&stats).Error
+	if err != nil {
+		return nil, &errors.RepositoryError{Operation: "GetVariantStats", Err: err}
+	}
+	return stats, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"shared/errors"
+	"shared/message"
 	"statistics-service/internal/model"
 	"strings"
 	"time"
@@ -14,10 +15,12 @@ import (
 type Repository interface {
 	// Create 创建点击事件
 	Create(ctx context.Context, event *model.ClickEvent) error
-	GetStatsSummary(ctx context.Context, shortCode string, startDate, endDate *time.Time) (*model.SummaryStats, error)
-	GetClickTimeline(ctx context.Context, shortCode string, startTime *time.Time, endTime *time.Time, groupExpr string, periodExpr string) ([]model.TimeSeriesStats, error)
-	GetGeographicStats(ctx context.Context, shortCode string) ([]*model.GeographicStats, error)
-	GetPlatformStats(ctx context.Context, shortCode string) ([]*model.PlatformStats, error)
+	// 以下查询均限定在指定工作空间内
+	GetStatsSummary(ctx context.Context, key model.LinkKey, startDate, endDate *time.Time) (*model.SummaryStats, error)
+	GetClickTimeline(ctx context.Context, key model.LinkKey, startTime *time.Time, endTime *time.Time, groupExpr string, periodExpr string) ([]model.TimeSeriesStats, error)
+	GetGeographicStats(ctx context.Context, key model.LinkKey) ([]*model.GeographicStats, error)
+	GetPlatformStats(ctx context.Context, key model.LinkKey) ([]*model.PlatformStats, error)
+	GetVariantStats(ctx context.Context, key model.LinkKey, startDate, endDate *time.Time) ([]*model.VariantStats, error)
 }
 
 type repository struct {
@@ -28,6 +31,14 @@ func NewMySQLRepository(db *gorm.DB) Repository {
 	return &repository{db: db}
 }
 
+// 点击明细基础查询，按工作空间、域名和短码限定范围，不含风险提示页的继续访问等其他事件和爬虫访问
+func (r *repository) scope(ctx context.Context, key model.LinkKey) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&model.ClickEvent{}).
+		Where("workspace_id = ? AND domain = ? AND short_code = ? AND delete_flag = 'N'", key.WorkspaceID, key.Domain, key.ShortCode).
+		Where("event_type = ?", message.EventTypeClick).
+		Where("(device_type IS NULL OR device_type <> ?)", model.DeviceBot)
+}
+
 func (r *repository) Create(ctx context.Context, clt *model.ClickEvent) error {
 	if clt == nil {
 		return fmt.Errorf("click event is nil")
@@ -45,14 +56,13 @@ func (r *repository) Create(ctx context.Context, clt *model.ClickEvent) error {
 
 func (r *repository) GetStatsSummary(
 	ctx context.Context,
-	shortCode string,
+	key model.LinkKey,
 	startDate, endDate *time.Time,
 ) (*model.SummaryStats, error) {
 	var stats model.SummaryStats
 
 	// 基础查询
-	query := r.db.WithContext(ctx).Model(&model.ClickEvent{}).
-		Where("short_code = ? AND delete_flag = 'N'", shortCode)
+	query := r.scope(ctx, key)
 
 	// 时间范围过滤
 	if startDate != nil {
@@ -74,42 +84,42 @@ func (r *repository) GetStatsSummary(
 	stats.TotalClicks = totalClicks
 
 	// 获取每日统计
-	dailyStats, err := r.getDailyStats(ctx, shortCode, startDate, endDate)
+	dailyStats, err := r.getDailyStats(ctx, key, startDate, endDate)
 	if err != nil {
 		return nil, &errors.RepositoryError{Operation: "GetStatsSummary", Err: err}
 	}
 	stats.DailyStats = dailyStats
 
 	// 获取来源统计
-	referrerStats, err := r.getReferrerStats(ctx, shortCode, startDate, endDate)
+	referrerStats, err := r.getReferrerStats(ctx, key, startDate, endDate)
 	if err != nil {
 		return nil, &errors.RepositoryError{Operation: "GetStatsSummary", Err: err}
 	}
 	stats.Referrers = referrerStats
 
 	// 获取设备统计
-	countryStats, err := r.getCountryStats(ctx, shortCode, startDate, endDate)
+	countryStats, err := r.getCountryStats(ctx, key, startDate, endDate)
 	if err != nil {
 		return nil, &errors.RepositoryError{Operation: "GetStatsSummary", Err: err}
 	}
 	stats.Countries = countryStats
 
 	// 获取国家统计
-	deviceStats, err := r.getDeviceStats(ctx, shortCode, startDate, endDate)
+	deviceStats, err := r.getDeviceStats(ctx, key, startDate, endDate)
 	if err != nil {
 		return nil, &errors.RepositoryError{Operation: "GetStatsSummary", Err: err}
 	}
 	stats.Devices = deviceStats
 
 	// 获取浏览器统计
-	browserStats, err := r.getBrowserStats(ctx, shortCode, startDate, endDate)
+	browserStats, err := r.getBrowserStats(ctx, key, startDate, endDate)
 	if err != nil {
 		return nil, &errors.RepositoryError{Operation: "GetStatsSummary", Err: err}
 	}
 	stats.Browsers = browserStats
 
 	// 获取操作系统统计
-	systemStats, err := r.getOsStats(ctx, shortCode, startDate, endDate)
+	systemStats, err := r.getOsStats(ctx, key, startDate, endDate)
 	if err != nil {
 		return nil, &errors.RepositoryError{Operation: "GetStatsSummary", Err: err}
 	}
@@ -120,14 +130,13 @@ func (r *repository) GetStatsSummary(
 
 func (r *repository) getDailyStats(
 	ctx context.Context,
-	shortCode string,
+	key model.LinkKey,
 	startDate, endDate *time.Time,
 ) ([]model.DailyStats, error) {
 	var stats []model.DailyStats
 
-	query := r.db.WithContext(ctx).Model(&model.ClickEvent{}).
-		Select(`DATE(click_time) as date, COUNT(*) as clicks, COUNT(DISTINCT ip) as unique_ips`).
-		Where("short_code = ? AND delete_flag = 'N'", shortCode)
+	query := r.scope(ctx, key).
+		Select(`DATE(click_time) as date, COUNT(*) as clicks, COUNT(DISTINCT ip) as unique_ips`)
 	if startDate != nil {
 		query = query.Where("click_time >= ?", startDate.Format("2006-01-02 15:04:05"))
 	}
@@ -144,16 +153,15 @@ func (r *repository) getDailyStats(
 
 func (r *repository) getReferrerStats(
 	ctx context.Context,
-	shortCode string,
+	key model.LinkKey,
 	startDate, endDate *time.Time,
 ) (map[string]int64, error) {
 	var referrerStats []struct {
 		Referer string
 		Count   int64
 	}
-	query := r.db.WithContext(ctx).Model(&model.ClickEvent{}).
-		Select("COALESCE(referer, 'direct') as referer, COUNT(*) as count").
-		Where("short_code = ? AND delete_flag = 'N'", shortCode)
+	query := r.scope(ctx, key).
+		Select("COALESCE(referer, 'direct') as referer, COUNT(*) as count")
 	if startDate != nil {
 		query = query.Where("click_time >= ?", startDate.Format("2006-01-02 15:04:05"))
 	}
@@ -177,16 +185,15 @@ func (r *repository) getReferrerStats(
 // 获取国家统计
 func (r *repository) getCountryStats(
 	ctx context.Context,
-	shortCode string,
+	key model.LinkKey,
 	startDate, endDate *time.Time,
 ) (map[string]int64, error) {
 	var countryStats []struct {
 		Country string
 		Count   int64
 	}
-	query := r.db.WithContext(ctx).Model(&model.ClickEvent{}).
-		Select("COALESCE(country, 'unknown') as country, COUNT(*) as count").
-		Where("short_code = ? AND delete_flag = 'N'", shortCode)
+	query := r.scope(ctx, key).
+		Select("COALESCE(country, 'unknown') as country, COUNT(*) as count")
 	if startDate != nil {
 		query = query.Where("click_time >= ?", startDate.Format("2006-01-02 15:04:05"))
 	}
@@ -210,16 +217,15 @@ func (r *repository) getCountryStats(
 // 获取设备统计
 func (r *repository) getDeviceStats(
 	ctx context.Context,
-	shortCode string,
+	key model.LinkKey,
 	startDate, endDate *time.Time,
 ) (map[string]int64, error) {
 	var deviceStats []struct {
 		Device string
 		Count  int64
 	}
-	query := r.db.WithContext(ctx).Model(&model.ClickEvent{}).
-		Select("COALESCE(device_type, 'other') as device, COUNT(*) as count").
-		Where("short_code = ? AND delete_flag = 'N'", shortCode)
+	query := r.scope(ctx, key).
+		Select("COALESCE(device_type, 'other') as device, COUNT(*) as count")
 	if startDate != nil {
 		query = query.Where("click_time >= ?", startDate.Format("2006-01-02 15:04:05"))
 	}
@@ -243,16 +249,15 @@ func (r *repository) getDeviceStats(
 // 获取浏览器统计
 func (r *repository) getBrowserStats(
 	ctx context.Context,
-	shortCode string,
+	key model.LinkKey,
 	startDate, endDate *time.Time,
 ) (map[string]int64, error) {
 	var osStats []struct {
 		Browser string
 		Count   int64
 	}
-	query := r.db.WithContext(ctx).Model(&model.ClickEvent{}).
-		Select("COALESCE(browser, 'other') as browser, COUNT(*) as count").
-		Where("short_code = ? AND delete_flag = 'N'", shortCode)
+	query := r.scope(ctx, key).
+		Select("COALESCE(browser, 'other') as browser, COUNT(*) as count")
 	if startDate != nil {
 		query = query.Where("click_time >= ?", startDate.Format("2006-01-02 15:04:05"))
 	}
@@ -276,16 +281,15 @@ func (r *repository) getBrowserStats(
 // 获取操作系统统计
 func (r *repository) getOsStats(
 	ctx context.Context,
-	shortCode string,
+	key model.LinkKey,
 	startDate, endDate *time.Time,
 ) (map[string]int64, error) {
 	var osStats []struct {
 		Os    string
 		Count int64
 	}
-	query := r.db.WithContext(ctx).Model(&model.ClickEvent{}).
-		Select("COALESCE(os, 'other') as os, COUNT(*) as count").
-		Where("short_code = ? AND delete_flag = 'N'", shortCode)
+	query := r.scope(ctx, key).
+		Select("COALESCE(os, 'other') as os, COUNT(*) as count")
 	if startDate != nil {
 		query = query.Where("click_time >= ?", startDate.Format("2006-01-02 15:04:05"))
 	}
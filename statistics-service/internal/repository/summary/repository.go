@@ -24,16 +24,18 @@ func (r *Repository) BatchUpsert(ctx context.Context, tx *gorm.DB, username stri
 		return nil
 	}
 	query := `
-		INSERT INTO click_stats_summary (id, short_code, stat_date, total_clicks, created_by, created_at, updated_by, updated_at, version) 
+		INSERT INTO click_stats_summary (id, workspace_id, domain, short_code, stat_date, total_clicks, created_by, created_at, updated_by, updated_at, version) 
 		VALUES `
 
 	now := time.Now()
 	var values []interface{}
 	var valuePlaceholders []string
 	for _, stat := range stats {
-		valuePlaceholders = append(valuePlaceholders, "(?, ?, ?, ?, ?, ?, ?, ?, ?)")
+		valuePlaceholders = append(valuePlaceholders, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
 		values = append(values,
 			stat.Id,
+			stat.WorkspaceID,
+			stat.Domain,
 			stat.ShortCode,
 			stat.StatDate,
 			stat.TotalClicks,
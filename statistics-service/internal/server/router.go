@@ -46,6 +46,7 @@ func setupRouter(cfg *config.Config, srv *Server) {
 
 	api := router.Group("/api/v1")
 	statsRouter := api.Group("/stats")
+	statsRouter.Use(middleware.InternalAuth(cfg.Auth.InternalToken))
 	{
 		codeRouter := statsRouter.Group("/:code")
 		{
@@ -53,6 +54,7 @@ func setupRouter(cfg *config.Config, srv *Server) {
 			codeRouter.GET("/time-series/:unit", statsHandler.GetTimeSeries)
 			codeRouter.GET("/geography", statsHandler.GetGeographicStats)
 			codeRouter.GET("/device", statsHandler.GetPlatformStats)
+			codeRouter.GET("/variants", statsHandler.GetVariantStats)
 		}
 	}
 
@@ -3,6 +3,8 @@ package click
 import "time"
 
 type RecordClickReq struct {
+	WorkspaceID uint64    `json:"workspace_id"`
+	Domain      string    `json:"domain,omitempty"`
 	ShortCode   string    `json:"short_code"`
 	OriginalURL string    `json:"original_url"`
 	IP          string    `json:"ip"`
@@ -14,4 +16,7 @@ type RecordClickReq struct {
 	City        string    `json:"city,omitempty"`
 	Source      string    `json:"source,omitempty"`
 	ClickBy     string    `json:"click_by,omitempty"`
+	VariantID   string    `json:"variant_id,omitempty"`
+	EventType   string    `json:"event_type,omitempty"` // 为空表示点击事件
+	Bot         bool      `json:"bot,omitempty"`        // 由 redirect-service 识别的爬虫访问
 }
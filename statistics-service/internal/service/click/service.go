@@ -2,6 +2,7 @@ package click
 
 import (
 	"context"
+	"shared/message"
 	"statistics-service/internal/model"
 	"statistics-service/internal/pkg/idgen"
 	"statistics-service/internal/repository/click"
@@ -36,6 +37,8 @@ func (s *Service) RecordClick(ctx context.Context, req *RecordClickReq) error {
 	id, _ := s.generator.NextId()
 	clc := &model.ClickEvent{
 		ID:          id,
+		WorkspaceID: req.WorkspaceID,
+		Domain:      req.Domain,
 		ShortCode:   req.ShortCode,
 		OriginalURL: req.OriginalURL,
 		IP:          req.IP,
@@ -45,6 +48,11 @@ func (s *Service) RecordClick(ctx context.Context, req *RecordClickReq) error {
 		Region:      req.Region,
 		City:        req.City,
 		ClickTime:   req.ClickTime,
+		VariantID:   req.VariantID,
+		EventType:   req.EventType,
+	}
+	if clc.EventType == "" {
+		clc.EventType = message.EventTypeClick
 	}
 	deviceInfo, err := s.detector.Parse(req.UserAgent)
 	if err == nil {
@@ -52,6 +60,9 @@ func (s *Service) RecordClick(ctx context.Context, req *RecordClickReq) error {
 		clc.Browser = deviceInfo.Browser
 		clc.OS = deviceInfo.OS
 	}
+	if req.Bot {
+		clc.DeviceType = model.DeviceBot
+	}
 	s.setDefaultValues(ctx, clc, time.Now(), req.ClickBy)
 	return s.clickRepo.Create(ctx, clc)
 }
@@ -67,15 +78,15 @@ func (s *Service) setDefaultValues(ctx context.Context, clc *model.ClickEvent, n
 
 func (s *Service) GetStatsSummary(
 	ctx context.Context,
-	shortCode string,
+	key model.LinkKey,
 	startDate, endDate *time.Time,
 ) (*model.SummaryResponse, error) {
-	summary, err := s.clickRepo.GetStatsSummary(ctx, shortCode, startDate, endDate)
+	summary, err := s.clickRepo.GetStatsSummary(ctx, key, startDate, endDate)
 	if err != nil {
 		return nil, err
 	}
 	return &model.SummaryResponse{
-		ShortCode:   shortCode,
+		ShortCode:   key.ShortCode,
 		TotalClicks: summary.TotalClicks,
 		DailyStats:  summary.DailyStats,
 		Referrers:   summary.Referrers,
@@ -88,13 +99,13 @@ func (s *Service) GetStatsSummary(
 
 func (s *Service) GetTimeSeriesSummary(
 	ctx context.Context,
-	shortCode string,
+	key model.LinkKey,
 	startTime *time.Time,
 	endTime *time.Time,
 	groupExpr string,
 	periodExpr string,
 ) (*model.TimeSeriesResponse, error) {
-	seriesStats, err := s.clickRepo.GetClickTimeline(ctx, shortCode, startTime, endTime, groupExpr, periodExpr)
+	seriesStats, err := s.clickRepo.GetClickTimeline(ctx, key, startTime, endTime, groupExpr, periodExpr)
 	if err != nil {
 		return nil, err
 	}
@@ -110,7 +121,7 @@ func (s *Service) GetTimeSeriesSummary(
 		totalUniqueClicks += stat.UniqueVisitors
 	}
 	resp := &model.TimeSeriesResponse{
-		ShortCode:  shortCode,
+		ShortCode:  key.ShortCode,
 		TimeSeries: timeSeries,
 		Summary: &model.SummaryData{
 			TotalClicks:         totalClicks,
@@ -120,26 +131,52 @@ func (s *Service) GetTimeSeriesSummary(
 	return resp, nil
 }
 
-func (s *Service) GetGeographicStats(ctx context.Context, shortCode string) (*model.GeographicResponse, error) {
-	stats, err := s.clickRepo.GetGeographicStats(ctx, shortCode)
+func (s *Service) GetGeographicStats(ctx context.Context, key model.LinkKey) (*model.GeographicResponse, error) {
+	stats, err := s.clickRepo.GetGeographicStats(ctx, key)
 	if err != nil {
 		return nil, err
 	}
 	resp := &model.GeographicResponse{
-		ShortCode:       shortCode,
+		ShortCode:       key.ShortCode,
 		GeographicStats: stats,
 	}
 	return resp, nil
 }
 
-func (s *Service) GetPlatformStats(ctx context.Context, shortCode string) (*model.PlatformResponse, error) {
-	stats, err := s.clickRepo.GetPlatformStats(ctx, shortCode)
+func (s *Service) GetPlatformStats(ctx context.Context, key model.LinkKey) (*model.PlatformResponse, error) {
+	stats, err := s.clickRepo.GetPlatformStats(ctx, key)
 	if err != nil {
 		return nil, err
 	}
 	resp := &model.PlatformResponse{
-		ShortCode:   shortCode,
+		ShortCode:   key.ShortCode,
 		DeviceStats: stats,
 	}
 	return resp, nil
 }
+
+// GetVariantStats 按 A/B 测试分组对比点击量和独立访客
+func (s *Service) GetVariantStats(
+	ctx context.Context,
+	key model.LinkKey,
+	startDate, endDate *time.Time,
+) (*model.VariantResponse, error) {
+	stats, err := s.clickRepo.GetVariantStats(ctx, key, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	var total int64
+	for _, stat := range stats {
+		total += stat.Clicks
+	}
+	for _, stat := range stats {
+		if total > 0 {
+			stat.ClickShare = float64(stat.Clicks) * 100 / float64(total)
+		}
+	}
+	return &model.VariantResponse{
+		ShortCode:   key.ShortCode,
+		TotalClicks: total,
+		Variants:    stats,
+	}, nil
+}
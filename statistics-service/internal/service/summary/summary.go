@@ -7,7 +7,6 @@ import (
 	"statistics-service/internal/pkg/idgen"
 	"statistics-service/internal/pkg/logger"
 	sumRepo "statistics-service/internal/repository/summary"
-	"strings"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
@@ -31,7 +30,7 @@ func NewService(
 	}
 }
 
-func (s *Service) RecordSummary(ctx context.Context, handlerKey string, buffer map[string]int) error {
+func (s *Service) RecordSummary(ctx context.Context, handlerKey string, buffer map[model.SummaryKey]int) error {
 	logger.Logger.Info("RecordSummary in transaction")
 	if len(buffer) == 0 {
 		return nil
@@ -58,22 +57,17 @@ func (s *Service) RecordSummary(ctx context.Context, handlerKey string, buffer m
 	return s.db.Transaction(fc)
 }
 
-func (s *Service) convertToStats(buffer map[string]int) []*model.StatTotal {
+func (s *Service) convertToStats(buffer map[model.SummaryKey]int) []*model.StatTotal {
 	var stats []*model.StatTotal
 
 	for key, count := range buffer {
-		parts := strings.Split(key, "_")
-		if len(parts) != 2 {
-			logger.Logger.Warn("invalid key format, skipping", zap.String("key", key))
-			continue
-		}
-
-		shortCode, date := parts[0], parts[1]
 		id, _ := s.generator.NextId()
 		stats = append(stats, &model.StatTotal{
 			Id:          id,
-			ShortCode:   shortCode,
-			StatDate:    date,
+			WorkspaceID: key.WorkspaceID,
+			Domain:      key.Domain,
+			ShortCode:   key.ShortCode,
+			StatDate:    key.StatDate,
 			TotalClicks: count,
 			CreatedBy:   "",
 			UpdatedBy:   "",
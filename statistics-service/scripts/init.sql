@@ -5,6 +5,8 @@ USE short_url;
 -- 点击事件明细表
 CREATE TABLE IF NOT EXISTS click_events (
     id BIGINT UNSIGNED PRIMARY KEY,
+    workspace_id BIGINT UNSIGNED NOT NULL COMMENT '所属工作空间',
+    domain VARCHAR(253) NOT NULL DEFAULT '' COMMENT '自定义域名，空表示默认域名',
     short_code VARCHAR(20) NOT NULL COMMENT '短链码',
     original_url VARCHAR(2048) NOT NULL COMMENT '原始URL',
     ip VARCHAR(45) NOT NULL COMMENT '客户端IP',
@@ -16,6 +18,8 @@ CREATE TABLE IF NOT EXISTS click_events (
     device_type ENUM('desktop', 'mobile', 'tablet', 'bot', 'other') COMMENT '设备类型',
     browser VARCHAR(100) COMMENT '浏览器',
     os VARCHAR(100) COMMENT '操作系统',
+    variant_id VARCHAR(20) NOT NULL DEFAULT '' COMMENT 'A/B测试分组',
+    event_type VARCHAR(30) NOT NULL DEFAULT 'click_event' COMMENT '事件类型：click_event / warning_continue / link_unfurl',
     click_time DATETIME(3) NOT NULL COMMENT '点击时间(精确到毫秒)',
     created_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
     created_by VARCHAR(100),
@@ -24,12 +28,15 @@ CREATE TABLE IF NOT EXISTS click_events (
     description VARCHAR(100),
     delete_flag varchar(1) DEFAULT 'N',
     version INT UNSIGNED DEFAULT 0,
-    INDEX idx_short_code_time (short_code, click_time)
+    INDEX idx_workspace_code_time (workspace_id, domain, short_code, click_time),
+    INDEX idx_workspace_code_variant (workspace_id, domain, short_code, variant_id)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='点击事件明细表';
 
 -- 点击统计汇总表(按天)
 CREATE TABLE IF NOT EXISTS click_stats_summary (
     id              BIGINT PRIMARY KEY,
+    workspace_id    BIGINT UNSIGNED NOT NULL COMMENT '所属工作空间',
+    domain          VARCHAR(253) NOT NULL DEFAULT '' COMMENT '自定义域名，空表示默认域名',
     short_code      VARCHAR(20) NOT NULL COMMENT '短链码',
     stat_date       DATE        NOT NULL COMMENT '统计日期',
     total_clicks    INT         NOT NULL DEFAULT 0 COMMENT '总点击量',
@@ -46,5 +53,5 @@ CREATE TABLE IF NOT EXISTS click_stats_summary (
     description     VARCHAR(100),
     delete_flag     varchar(1)           DEFAULT 'N',
     version         INT UNSIGNED         DEFAULT 0,
-    UNIQUE KEY uk_short_code_date (short_code, stat_date)
+    UNIQUE KEY uk_workspace_code_date (workspace_id, domain, short_code, stat_date)
 ) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COMMENT ='点击统计汇总表(按天)';
\ No newline at end of file